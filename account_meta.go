@@ -0,0 +1,250 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AccountMeta 账户的元数据，记录地址所属的客户、用途等信息
+type AccountMeta struct {
+	Address    string            `json:"address"`              // 地址
+	Label      string            `json:"label,omitempty"`      // 标签
+	Tags       []string          `json:"tags,omitempty"`       // 分类
+	Chain      string            `json:"chain,omitempty"`      // 链名称
+	ChainType  uint32            `json:"chainType,omitempty"`  // 链类型
+	Network    string            `json:"network,omitempty"`    // 网络类型，链实现NetworkAPI时记录
+	KeyPath    string            `json:"keyPath,omitempty"`    // 地址的路径
+	CreateTime int64             `json:"createTime,omitempty"` // 创建时间
+	Extra      map[string]string `json:"extra,omitempty"`      // 自定义键值
+}
+
+// AccountQuery 账户查询条件，为空的条件不参与过滤
+type AccountQuery struct {
+	Label         string            // 标签包含的内容
+	Tags          []string          // 需要包含的全部分类
+	Chain         string            // 链名称，不区分大小写
+	Network       string            // 网络类型
+	KeyPathPrefix string            // 路径前缀
+	Extra         map[string]string // 需要匹配的自定义键值
+	CreatedAfter  int64             // 创建时间不早于该时间
+	CreatedBefore int64             // 创建时间早于该时间
+}
+
+func (m *AccountMeta) copy() *AccountMeta {
+	c := *m
+	c.Tags = append([]string(nil), m.Tags...)
+	if m.Extra != nil {
+		c.Extra = make(map[string]string, len(m.Extra))
+		for k, v := range m.Extra {
+			c.Extra[k] = v
+		}
+	}
+	return &c
+}
+
+func (m *AccountMeta) hasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// 是否满足查询条件
+func (q *AccountQuery) match(m *AccountMeta) bool {
+	if q == nil {
+		return true
+	}
+	if q.Label != "" && !strings.Contains(m.Label, q.Label) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !m.hasTag(tag) {
+			return false
+		}
+	}
+	if q.Chain != "" && !strings.EqualFold(m.Chain, q.Chain) {
+		return false
+	}
+	if q.Network != "" && m.Network != q.Network {
+		return false
+	}
+	if q.KeyPathPrefix != "" && !strings.HasPrefix(m.KeyPath, q.KeyPathPrefix) {
+		return false
+	}
+	for k, v := range q.Extra {
+		if m.Extra[k] != v {
+			return false
+		}
+	}
+	if q.CreatedAfter > 0 && m.CreateTime < q.CreatedAfter {
+		return false
+	}
+	if q.CreatedBefore > 0 && m.CreateTime >= q.CreatedBefore {
+		return false
+	}
+	return true
+}
+
+// 记录账户的元数据，已有的标签、分类及自定义键值会被保留
+// 需要在w.mu锁定的情况下调用
+func (w *Wallet) recordAccountMetaLocked(addr, keyPath string, api ChainAPI) {
+	if w.AccountMetas == nil {
+		w.AccountMetas = make(map[string]*AccountMeta)
+	}
+	meta, ok := w.AccountMetas[addr]
+	if !ok {
+		meta = &AccountMeta{Address: addr, CreateTime: time.Now().Unix()}
+		w.AccountMetas[addr] = meta
+	}
+	meta.Chain = api.ChainInfo().ChainName
+	meta.ChainType = api.ChainInfo().ChainType
+	if n, ok := api.(NetworkAPI); ok {
+		meta.Network = string(n.NetworkType())
+	}
+	meta.KeyPath = keyPath
+}
+
+// GetAccountMeta 获取账户的元数据
+func (w *Wallet) GetAccountMeta(address string) (*AccountMeta, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	meta := w.accountMetaLocked(address)
+	if meta == nil {
+		return nil, ErrAccountNotFound
+	}
+	return meta.copy(), nil
+}
+
+// 账户的元数据，元数据功能之前记录的账户使用ChildKeyInfo生成
+// 需要在w.mu锁定的情况下调用
+func (w *Wallet) accountMetaLocked(address string) *AccountMeta {
+	if meta, ok := w.AccountMetas[address]; ok {
+		return meta
+	}
+	pubKeyStr, ok := w.AddrLinkPubkey[address]
+	if !ok {
+		return nil
+	}
+	meta := &AccountMeta{Address: address}
+	if info := w.ChildKeyInfo[pubKeyStr]; info != nil {
+		meta.ChainType = info.ChainType
+		meta.CreateTime = int64(info.Time)
+	}
+	return meta
+}
+
+// QueryAccounts 查询满足条件的账户，按创建时间及地址排序，query为nil时返回全部账户
+func (w *Wallet) QueryAccounts(query *AccountQuery) []*AccountMeta {
+	w.mu.RLock()
+	result := make([]*AccountMeta, 0)
+	for address := range w.AddrLinkPubkey {
+		meta := w.accountMetaLocked(address)
+		if query.match(meta) {
+			result = append(result, meta.copy())
+		}
+	}
+	w.mu.RUnlock()
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreateTime != result[j].CreateTime {
+			return result[i].CreateTime < result[j].CreateTime
+		}
+		return result[i].Address < result[j].Address
+	})
+	return result
+}
+
+// SetAccountLabel 设置账户的标签
+func (w *Wallet) SetAccountLabel(address string, label string) error {
+	return w.updateAccountMeta(address, func(meta *AccountMeta) {
+		meta.Label = label
+	})
+}
+
+// AddAccountTags 为账户添加分类
+func (w *Wallet) AddAccountTags(address string, tags ...string) error {
+	return w.updateAccountMeta(address, func(meta *AccountMeta) {
+		for _, tag := range tags {
+			if tag != "" && !meta.hasTag(tag) {
+				meta.Tags = append(meta.Tags, tag)
+			}
+		}
+	})
+}
+
+// RemoveAccountTags 删除账户的分类
+func (w *Wallet) RemoveAccountTags(address string, tags ...string) error {
+	return w.updateAccountMeta(address, func(meta *AccountMeta) {
+		remain := meta.Tags[:0]
+		for _, t := range meta.Tags {
+			removed := false
+			for _, tag := range tags {
+				if t == tag {
+					removed = true
+					break
+				}
+			}
+			if !removed {
+				remain = append(remain, t)
+			}
+		}
+		meta.Tags = remain
+	})
+}
+
+// SetAccountExtra 设置账户的自定义键值，value为空时删除该键
+func (w *Wallet) SetAccountExtra(address string, key string, value string) error {
+	if key == "" {
+		return fmt.Errorf("wallet SetAccountExtra key is empty")
+	}
+	return w.updateAccountMeta(address, func(meta *AccountMeta) {
+		if value == "" {
+			delete(meta.Extra, key)
+			return
+		}
+		if meta.Extra == nil {
+			meta.Extra = make(map[string]string)
+		}
+		meta.Extra[key] = value
+	})
+}
+
+// 修改账户的元数据并写入存储后端，账户需要已记录在钱包中
+// 在副本上修改，写入失败时恢复修改前的元数据
+func (w *Wallet) updateAccountMeta(address string, update func(meta *AccountMeta)) error {
+	w.mu.Lock()
+	meta := w.accountMetaLocked(address)
+	if meta == nil {
+		w.mu.Unlock()
+		return ErrAccountNotFound
+	}
+	if w.AccountMetas == nil {
+		w.AccountMetas = make(map[string]*AccountMeta)
+	}
+	prev, existed := w.AccountMetas[address]
+	updated := meta.copy()
+	update(updated)
+	w.AccountMetas[address] = updated
+	w.mu.Unlock()
+	if err := w.save(); err != nil {
+		w.mu.Lock()
+		// 期间被其他修改覆盖时不再恢复
+		if w.AccountMetas[address] == updated {
+			if existed {
+				w.AccountMetas[address] = prev
+			} else {
+				delete(w.AccountMetas, address)
+			}
+		}
+		w.mu.Unlock()
+		return fmt.Errorf("wallet updateAccountMeta storage.Put err:%v", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/chain"
+)
+
+// networkTestChain 实现NetworkAPI的测试链
+type networkTestChain struct {
+	*testChain
+	network chain.NetworkType
+}
+
+func (c *networkTestChain) NetworkType() chain.NetworkType {
+	return c.network
+}
+
+func TestWallet_AccountMeta(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetIsSaveSubKey(true)
+	api1 := newTestChain("T1", bip32.ParseHDNum(1))
+	api2 := &networkTestChain{testChain: newTestChain("T2", bip32.ParseHDNum(2)), network: chain.TestNet}
+	addr1, keyPath1, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr2, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(2), 0, bip32.ParseHDNum(0), 0, 0, api2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr3, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(2), 0, bip32.ParseHDNum(0), 0, 1, api2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := wallet.GetAccountMeta(addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Chain != "T1" || meta.KeyPath != keyPath1 || meta.Network != "" || meta.CreateTime == 0 {
+		t.Fatalf("meta=%+v", meta)
+	}
+	if err := wallet.SetAccountLabel(addr1, "customer-alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.AddAccountTags(addr2, "deposit", "vip"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.AddAccountTags(addr3, "deposit"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.SetAccountExtra(addr3, "orderId", "1001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.SetAccountLabel("unknown", "x"); err != ErrAccountNotFound {
+		t.Fatalf("err=%v, want ErrAccountNotFound", err)
+	}
+
+	// 重新加载后依然可以查询
+	loaded, err := LoadWalletFromStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		query *AccountQuery
+		want  int
+	}{
+		{nil, 3},
+		{&AccountQuery{Label: "alice"}, 1},
+		{&AccountQuery{Tags: []string{"deposit"}}, 2},
+		{&AccountQuery{Tags: []string{"deposit", "vip"}}, 1},
+		{&AccountQuery{Chain: "t2", Network: chain.TestNet}, 2},
+		{&AccountQuery{Network: chain.MainNet}, 0},
+		{&AccountQuery{KeyPathPrefix: "/44/1/"}, 1},
+		{&AccountQuery{Extra: map[string]string{"orderId": "1001"}}, 1},
+	} {
+		if result := loaded.QueryAccounts(tc.query); len(result) != tc.want {
+			t.Fatalf("query=%+v result=%d, want %d", tc.query, len(result), tc.want)
+		}
+	}
+
+	if err := loaded.RemoveAccountTags(addr2, "vip"); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.SetAccountExtra(addr3, "orderId", ""); err != nil {
+		t.Fatal(err)
+	}
+	loaded.Lock()
+	if err := loaded.Unlock("123456", 0); err != nil {
+		t.Fatal(err)
+	}
+	if result := loaded.QueryAccounts(&AccountQuery{Tags: []string{"vip"}}); len(result) != 0 {
+		t.Fatalf("tag vip should be removed")
+	}
+	if meta, _ := loaded.GetAccountMeta(addr3); len(meta.Extra) != 0 {
+		t.Fatalf("extra should be removed, meta=%+v", meta)
+	}
+
+	// 元数据功能之前记录的账户
+	delete(loaded.AccountMetas, addr1)
+	meta, err = loaded.GetAccountMeta(addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.ChainType != bip32.ParseHDNum(1) || meta.CreateTime == 0 {
+		t.Fatalf("legacy meta=%+v", meta)
+	}
+	if err := loaded.SetAccountLabel(addr1, "legacy"); err != nil {
+		t.Fatal(err)
+	}
+	if result := loaded.QueryAccounts(&AccountQuery{Label: "legacy"}); len(result) != 1 {
+		t.Fatalf("legacy account should be labeled")
+	}
+}
+
+func TestWallet_AccountMetaSaveFailed(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	addr, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.SetAccountLabel(addr, "hot"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 存储版本冲突时写入失败，内存中的元数据保持不变
+	item, err := store.Get("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put("w1", item.Data, item.Version); err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.SetAccountLabel(addr, "cold"); err == nil {
+		t.Fatal("set label should fail on version conflict")
+	}
+	if err := wallet.AddAccountTags(addr, "exchange"); err == nil {
+		t.Fatal("add tags should fail on version conflict")
+	}
+	meta, err := wallet.GetAccountMeta(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Label != "hot" || len(meta.Tags) != 0 {
+		t.Fatalf("meta=%+v, want the label hot without tags", meta)
+	}
+
+	// 钱包锁定时写入失败，从未设置过的元数据也不会残留
+	wallet2, err := NewWalletWithStorage(NewMemoryStorage(), "w2", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr2, _, err := wallet2.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet2.Lock()
+	if err := wallet2.SetAccountExtra(addr2, "k", "v"); err == nil {
+		t.Fatal("set extra should fail when locked")
+	}
+	meta, err = wallet2.GetAccountMeta(addr2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Extra) != 0 {
+		t.Fatalf("meta extra=%v, want empty", meta.Extra)
+	}
+}
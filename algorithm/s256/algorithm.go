@@ -1,9 +1,11 @@
 package s256
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 
 	"github.com/chain5j/chain5j-pkg/crypto/signature/secp256k1"
+	"github.com/chain5j/chain5j-pkg/crypto/signature/secp256k1/btcecv1"
 	"github.com/chain5j/keybox/algorithm"
 )
 
@@ -16,20 +18,19 @@ func (a *Algorithm) GetPubKeyFromPriKey(priKey []byte) ([]byte, error) {
 		return nil, fmt.Errorf("Chain GetPubKeyFromPriKey parameter error")
 	}
 	cryptoS256 := secp256k1.Secp251k1{}
-	privateKey := cryptoS256.ToECDSA(priKey)
-	if privateKey == nil {
-		return nil, fmt.Errorf("private key is empty")
-	}
+	privateKey := toECDSA(priKey)
+	defer zeroECDSA(privateKey)
 	return cryptoS256.MarshalPublicKey(&privateKey.PublicKey)
 }
 
 // 签名交易体Hash
 func (a *Algorithm) Sign(priKey []byte, hash []byte) (*algorithm.Signature, error) {
-	cryptoS256 := secp256k1.Secp251k1{}
-	privateKey := cryptoS256.ToECDSA(priKey)
-	if privateKey != nil {
+	if len(priKey) == 0 {
 		return nil, fmt.Errorf("private key is empty")
 	}
+	cryptoS256 := secp256k1.Secp251k1{}
+	privateKey := toECDSA(priKey)
+	defer zeroECDSA(privateKey)
 	signResult, err := cryptoS256.Sign(privateKey, hash)
 	if err != nil {
 		return nil, err
@@ -44,3 +45,20 @@ func (a *Algorithm) Sign(priKey []byte, hash []byte) (*algorithm.Signature, erro
 		Pubkey:    marshalPublicKey,
 	}, nil
 }
+
+func toECDSA(priKey []byte) *ecdsa.PrivateKey {
+	privateKey, _ := btcecv1.PrivKeyFromBytes(btcecv1.S256(), priKey)
+	return privateKey.ToECDSA()
+}
+
+// 将私钥的D值清零
+func zeroECDSA(privateKey *ecdsa.PrivateKey) {
+	if privateKey == nil || privateKey.D == nil {
+		return
+	}
+	words := privateKey.D.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	privateKey.D.SetInt64(0)
+}
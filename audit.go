@@ -0,0 +1,407 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 审计的操作类型
+const (
+	AuditUnlock                  = "unlock"
+	AuditExportMasterMnemonic    = "exportMasterMnemonic"
+	AuditExportMasterExtendedKey = "exportMasterExtendedKey"
+	AuditExportMasterRawKey      = "exportMasterRawKey"
+	AuditDelMnemonic             = "delMnemonic"
+	AuditExportRawKey            = "exportRawKey"
+	AuditExportExtendedKey       = "exportExtendedKey"
+	AuditExportKeyStore          = "exportKeyStore"
+	AuditExportPriKey            = "exportPriKey"
+	AuditExportAccountKey        = "exportAccountKey"
+	AuditSign                    = "sign"
+	AuditBackup                  = "backup"
+	AuditDeriveBIP85             = "deriveBIP85"
+)
+
+// 审计的结果
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+var (
+	ErrAuditTampered  = errors.New("audit log is tampered")
+	ErrAuditTruncated = errors.New("audit log is truncated")
+)
+
+// 第一条记录的prevHash
+var auditGenesisHash = strings.Repeat("0", 64)
+
+// AuditEntry 审计日志中的一条记录
+type AuditEntry struct {
+	Seq       uint64 `json:"seq"`                 // 序号，从1开始
+	Time      int64  `json:"time"`                // 时间，unix毫秒
+	Actor     string `json:"actor,omitempty"`     // 操作人
+	Wallet    string `json:"wallet,omitempty"`    // 钱包名称
+	Operation string `json:"operation"`           // 操作类型
+	Address   string `json:"address,omitempty"`   // 地址
+	KeyPath   string `json:"keyPath,omitempty"`   // 地址的路径
+	Hash      string `json:"hash,omitempty"`      // 签名的Hash
+	Outcome   string `json:"outcome"`             // 结果
+	Error     string `json:"error,omitempty"`     // 失败的原因
+	PrevHash  string `json:"prevHash"`            // 上一条记录的entryHash
+	EntryHash string `json:"entryHash"`           // 本条记录的Hash
+	Signature string `json:"signature,omitempty"` // 对entryHash的签名
+}
+
+// 记录的Hash，不包含entryHash及signature
+func (e *AuditEntry) digest() ([]byte, error) {
+	c := *e
+	c.EntryHash = ""
+	c.Signature = ""
+	data, err := json.Marshal(&c)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(data)
+	return h[:], nil
+}
+
+// 日志头，记录最后一条记录，用于检测尾部被截断
+type auditHead struct {
+	Seq       uint64 `json:"seq"`
+	EntryHash string `json:"entryHash"`
+	Signature string `json:"signature,omitempty"`
+}
+
+func (h *auditHead) digest() []byte {
+	d := sha256.Sum256([]byte(fmt.Sprintf("head:%d:%s", h.Seq, h.EntryHash)))
+	return d[:]
+}
+
+// AuditSigner 审计日志的签名及验签
+type AuditSigner interface {
+	Sign(digest []byte) ([]byte, error)
+	Verify(digest []byte, sig []byte) bool
+}
+
+type hmacAuditSigner struct {
+	key []byte
+}
+
+// NewHMACAuditSigner 使用HMAC-SHA256签名，验证时需要相同的密钥
+func NewHMACAuditSigner(key []byte) AuditSigner {
+	return &hmacAuditSigner{key: append([]byte(nil), key...)}
+}
+
+func (s *hmacAuditSigner) Sign(digest []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(digest)
+	return mac.Sum(nil), nil
+}
+
+func (s *hmacAuditSigner) Verify(digest []byte, sig []byte) bool {
+	expected, _ := s.Sign(digest)
+	return hmac.Equal(expected, sig)
+}
+
+type ed25519AuditSigner struct {
+	priKey ed25519.PrivateKey
+	pubKey ed25519.PublicKey
+}
+
+// NewEd25519AuditSigner 使用ed25519私钥签名
+func NewEd25519AuditSigner(priKey ed25519.PrivateKey) AuditSigner {
+	return &ed25519AuditSigner{priKey: priKey, pubKey: priKey.Public().(ed25519.PublicKey)}
+}
+
+// NewEd25519AuditVerifier 使用ed25519公钥验签，只能用于VerifyAuditLog
+func NewEd25519AuditVerifier(pubKey ed25519.PublicKey) AuditSigner {
+	return &ed25519AuditSigner{pubKey: pubKey}
+}
+
+func (s *ed25519AuditSigner) Sign(digest []byte) ([]byte, error) {
+	if s.priKey == nil {
+		return nil, fmt.Errorf("audit ed25519 private key is nil")
+	}
+	return ed25519.Sign(s.priKey, digest), nil
+}
+
+func (s *ed25519AuditSigner) Verify(digest []byte, sig []byte) bool {
+	return ed25519.Verify(s.pubKey, digest, sig)
+}
+
+// AuditLog 只追加的审计日志，每条记录包含上一条记录的Hash，可选对记录进行签名
+// 日志文件每行为一条json记录，同目录下的path.head文件记录最后一条记录，用于检测截断
+type AuditLog struct {
+	mu       sync.Mutex
+	path     string
+	signer   AuditSigner
+	file     *os.File
+	seq      uint64
+	lastHash string
+}
+
+// OpenAuditLog 打开审计日志，已有的日志会先进行校验，新的记录追加在末尾
+// signer 为nil时不签名
+func OpenAuditLog(path string, signer AuditSigner) (*AuditLog, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("OpenAuditLog path is empty")
+	}
+	l := &AuditLog{path: path, signer: signer, lastHash: auditGenesisHash}
+	if info, err := os.Stat(path); err == nil {
+		head, size, err := verifyAuditLog(path, signer)
+		if err != nil {
+			return nil, err
+		}
+		if head != nil {
+			l.seq = head.Seq
+			l.lastHash = head.EntryHash
+		}
+		// 截掉写入中断时残留的不完整的最后一行，新的记录从新行开始
+		if size < info.Size() {
+			if err := os.Truncate(path, size); err != nil {
+				return nil, fmt.Errorf("OpenAuditLog truncate file err:%v", err.Error())
+			}
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAuditLog open file err:%v", err.Error())
+	}
+	l.file = file
+	return l, nil
+}
+
+// Append 追加一条记录，seq、prevHash、entryHash及signature会被填充
+func (l *AuditLog) Append(entry *AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+	if entry.Time == 0 {
+		entry.Time = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	entry.Seq = l.seq + 1
+	entry.PrevHash = l.lastHash
+	digest, err := entry.digest()
+	if err != nil {
+		return err
+	}
+	entry.EntryHash = hex.EncodeToString(digest)
+	entry.Signature = ""
+	if l.signer != nil {
+		sig, err := l.signer.Sign(digest)
+		if err != nil {
+			return fmt.Errorf("audit log sign err:%v", err.Error())
+		}
+		entry.Signature = hex.EncodeToString(sig)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("audit log write err:%v", err.Error())
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("audit log sync err:%v", err.Error())
+	}
+	l.seq = entry.Seq
+	l.lastHash = entry.EntryHash
+	return l.writeHead()
+}
+
+// 写入日志头，先写临时文件再重命名
+func (l *AuditLog) writeHead() error {
+	head := &auditHead{Seq: l.seq, EntryHash: l.lastHash}
+	if l.signer != nil {
+		sig, err := l.signer.Sign(head.digest())
+		if err != nil {
+			return fmt.Errorf("audit log sign head err:%v", err.Error())
+		}
+		head.Signature = hex.EncodeToString(sig)
+	}
+	data, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	tmp := auditHeadPath(l.path) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("audit log write head err:%v", err.Error())
+	}
+	if err := os.Rename(tmp, auditHeadPath(l.path)); err != nil {
+		return fmt.Errorf("audit log write head err:%v", err.Error())
+	}
+	return nil
+}
+
+// Close 关闭审计日志
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func auditHeadPath(path string) string {
+	return path + ".head"
+}
+
+// VerifyAuditLog 校验审计日志，返回最后一条记录
+// 检查每条记录的Hash、Hash链及序号，与日志头比较检测截断；verifier不为nil时校验签名
+// 写入中断时残留的不完整的最后一行未被日志头记录，校验时忽略
+// 日志为空时返回nil
+func VerifyAuditLog(path string, verifier AuditSigner) (*AuditEntry, error) {
+	last, _, err := verifyAuditLog(path, verifier)
+	return last, err
+}
+
+// 校验审计日志，返回最后一条记录及完整记录的长度
+func verifyAuditLog(path string, verifier AuditSigner) (*AuditEntry, int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("VerifyAuditLog read file err:%v", err.Error())
+	}
+	// 最后一行没有换行符时为写入中断的记录，日志头只会在完整写入后更新
+	data = data[:bytes.LastIndexByte(data, '\n')+1]
+	size := int64(len(data))
+	var last, prev *AuditEntry
+	prevHash := auditGenesisHash
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		entry := new(AuditEntry)
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, 0, fmt.Errorf("%w: line %d json.Unmarshal err:%v", ErrAuditTampered, line, err.Error())
+		}
+		if entry.Seq != uint64(line) || entry.PrevHash != prevHash {
+			return nil, 0, fmt.Errorf("%w: line %d breaks the hash chain", ErrAuditTampered, line)
+		}
+		digest, err := entry.digest()
+		if err != nil {
+			return nil, 0, err
+		}
+		if entry.EntryHash != hex.EncodeToString(digest) {
+			return nil, 0, fmt.Errorf("%w: line %d entryHash mismatch", ErrAuditTampered, line)
+		}
+		if verifier != nil && !verifyAuditSignature(verifier, digest, entry.Signature) {
+			return nil, 0, fmt.Errorf("%w: line %d signature is invalid", ErrAuditTampered, line)
+		}
+		prevHash = entry.EntryHash
+		prev, last = last, entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("VerifyAuditLog read file err:%v", err.Error())
+	}
+
+	headData, err := os.ReadFile(auditHeadPath(path))
+	if err != nil {
+		if os.IsNotExist(err) && last == nil {
+			return nil, size, nil
+		}
+		return nil, 0, fmt.Errorf("%w: read head err:%v", ErrAuditTruncated, err.Error())
+	}
+	head := new(auditHead)
+	if err := json.Unmarshal(headData, head); err != nil {
+		return nil, 0, fmt.Errorf("%w: head json.Unmarshal err:%v", ErrAuditTampered, err.Error())
+	}
+	if verifier != nil && !verifyAuditSignature(verifier, head.digest(), head.Signature) {
+		return nil, 0, fmt.Errorf("%w: head signature is invalid", ErrAuditTampered)
+	}
+	lastSeq, lastHash := uint64(0), auditGenesisHash
+	if last != nil {
+		lastSeq, lastHash = last.Seq, last.EntryHash
+	}
+	if head.Seq > lastSeq {
+		return nil, 0, fmt.Errorf("%w: head seq %d, log seq %d", ErrAuditTruncated, head.Seq, lastSeq)
+	}
+	if head.Seq == lastSeq && head.EntryHash == lastHash {
+		return last, size, nil
+	}
+	// 写入记录后、写入日志头前中断时，日志头落后一条记录
+	if prev != nil && head.Seq == prev.Seq && head.EntryHash == prev.EntryHash {
+		return last, size, nil
+	}
+	if prev == nil && head.Seq == 0 && head.EntryHash == auditGenesisHash {
+		return last, size, nil
+	}
+	return nil, 0, fmt.Errorf("%w: head does not match the last entry", ErrAuditTampered)
+}
+
+func verifyAuditSignature(verifier AuditSigner, digest []byte, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) == 0 {
+		return false
+	}
+	return verifier.Verify(digest, sig)
+}
+
+// SetAuditLog 设置钱包的审计日志，解锁、导出密钥及签名等操作会记录到日志中
+// actor 操作人，log为nil时不记录
+func (w *Wallet) SetAuditLog(log *AuditLog, actor string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.auditLog = log
+	w.auditActor = actor
+}
+
+// 记录审计日志，opErr为操作的结果
+// 操作成功但日志写入失败时返回错误，调用方不应返回敏感数据
+func (w *Wallet) audit(operation, address, keyPath string, hash []byte, opErr error) error {
+	w.mu.RLock()
+	log, actor, name := w.auditLog, w.auditActor, w.name
+	if name == "" {
+		name = w.Path
+	}
+	w.mu.RUnlock()
+	if log == nil {
+		return opErr
+	}
+	entry := &AuditEntry{
+		Actor:     actor,
+		Wallet:    name,
+		Operation: operation,
+		Address:   address,
+		KeyPath:   keyPath,
+		Outcome:   AuditSuccess,
+	}
+	if len(hash) > 0 {
+		entry.Hash = hex.EncodeToString(hash)
+	}
+	if opErr != nil {
+		entry.Outcome = AuditFailure
+		entry.Error = opErr.Error()
+	}
+	if err := log.Append(entry); err != nil && opErr == nil {
+		return fmt.Errorf("wallet audit err:%v", err.Error())
+	}
+	return opErr
+}
+
+// 导出的内容为空时视为失败
+func exportErr(value string) error {
+	if value == "" {
+		return fmt.Errorf("wallet export content is empty")
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+)
+
+func appendAuditEntries(t *testing.T, path string, signer AuditSigner, n int) {
+	log, err := OpenAuditLog(path, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	for i := 0; i < n; i++ {
+		if err := log.Append(&AuditEntry{Actor: "alice", Operation: AuditSign, Address: "addr", Outcome: AuditSuccess}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	signer := NewHMACAuditSigner([]byte("audit-key"))
+	path := filepath.Join(t.TempDir(), "audit.log")
+	appendAuditEntries(t, path, signer, 3)
+	// 重新打开后继续哈希链
+	appendAuditEntries(t, path, signer, 2)
+
+	last, err := VerifyAuditLog(path, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Seq != 5 {
+		t.Fatalf("seq=%d, want 5", last.Seq)
+	}
+	if _, err := VerifyAuditLog(path, NewHMACAuditSigner([]byte("wrong-key"))); !errors.Is(err, ErrAuditTampered) {
+		t.Fatalf("err=%v, want ErrAuditTampered", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+
+	// 篡改记录
+	tampered := bytes.Replace(data, []byte(`"address":"addr"`), []byte(`"address":"evil"`), 1)
+	if err := os.WriteFile(path, tampered, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyAuditLog(path, signer); !errors.Is(err, ErrAuditTampered) {
+		t.Fatalf("err=%v, want ErrAuditTampered", err)
+	}
+	if _, err := OpenAuditLog(path, signer); err == nil {
+		t.Fatal("open tampered log should fail")
+	}
+
+	// 删除最后两条记录
+	if err := os.WriteFile(path, bytes.Join(lines[:3], nil), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyAuditLog(path, signer); !errors.Is(err, ErrAuditTruncated) {
+		t.Fatalf("err=%v, want ErrAuditTruncated", err)
+	}
+
+	// 写入日志头前中断，日志头落后一条记录
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	head, err := os.ReadFile(auditHeadPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendAuditEntries(t, path, signer, 1)
+	if err := os.WriteFile(auditHeadPath(path), head, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := VerifyAuditLog(path, signer); err != nil || last.Seq != 6 {
+		t.Fatalf("last=%v err=%v", last, err)
+	}
+}
+
+func TestAuditLog_IncompleteLine(t *testing.T) {
+	signer := NewHMACAuditSigner([]byte("audit-key"))
+	path := filepath.Join(t.TempDir(), "audit.log")
+	appendAuditEntries(t, path, signer, 2)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 写入记录时中断，残留的不完整的最后一行未被日志头记录
+	if err := os.WriteFile(path, append(append([]byte(nil), data...), `{"seq":3,"time":`...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := VerifyAuditLog(path, signer); err != nil || last.Seq != 2 {
+		t.Fatalf("last=%v err=%v", last, err)
+	}
+	// 重新打开时截掉不完整的行，新的记录继续哈希链
+	appendAuditEntries(t, path, signer, 1)
+	if last, err := VerifyAuditLog(path, signer); err != nil || last.Seq != 3 {
+		t.Fatalf("last=%v err=%v", last, err)
+	}
+
+	// 日志头已记录的最后一行被截断
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)-10], 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyAuditLog(path, signer); !errors.Is(err, ErrAuditTruncated) {
+		t.Fatalf("err=%v, want ErrAuditTruncated", err)
+	}
+}
+
+func TestAuditLog_Ed25519(t *testing.T) {
+	pubKey, priKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "audit.log")
+	appendAuditEntries(t, path, NewEd25519AuditSigner(priKey), 2)
+	if _, err := VerifyAuditLog(path, NewEd25519AuditVerifier(pubKey)); err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if _, err := VerifyAuditLog(path, NewEd25519AuditVerifier(otherPub)); !errors.Is(err, ErrAuditTampered) {
+		t.Fatalf("err=%v, want ErrAuditTampered", err)
+	}
+}
+
+func TestWallet_AuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log, err := OpenAuditLog(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetAuditLog(log, "alice")
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := bytes.Repeat([]byte{1}, 32)
+	if _, err := wallet.Sign(addr, "", hash, api); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.ExportRawKey("unknown", keyPath, api); err == nil {
+		t.Fatal("export unknown address should fail")
+	}
+	if wallet.ExportMasterMnemonic() == "" {
+		t.Fatal("mnemonic is empty")
+	}
+	wallet.Lock()
+	if err := wallet.Unlock("wrong", 0); err == nil {
+		t.Fatal("unlock with wrong password should fail")
+	}
+
+	last, err := VerifyAuditLog(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Seq != 4 || last.Operation != AuditUnlock || last.Outcome != AuditFailure || last.Wallet != "w1" {
+		t.Fatalf("last=%+v", last)
+	}
+	data, _ := os.ReadFile(path)
+	if !bytes.Contains(data, []byte(`"operation":"sign","address":"`+addr+`","keyPath":"`+keyPath+`"`)) {
+		t.Fatalf("sign entry not found:\n%s", data)
+	}
+
+	// 审计日志不可写入时，不返回敏感数据
+	log.Close()
+	if err := wallet.Unlock("123456", 0); err == nil {
+		t.Fatal("unlock should fail when audit log is closed")
+	}
+	if !wallet.IsLocked() {
+		t.Fatal("wallet should be locked")
+	}
+}
@@ -0,0 +1,199 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chain5j/keybox/crypto/scrypt"
+	"github.com/chain5j/keybox/crypto/secret"
+	"github.com/pborman/uuid"
+)
+
+// 备份文件格式的版本号
+const backupVersion = 1
+
+var ErrBackupCorrupted = errors.New("backup bundle is corrupted")
+
+// 备份文件，crypto为使用备份密码加密的backupPayload
+type backupBundle struct {
+	Version    int             `json:"version"`    // 格式版本号
+	CreateTime int64           `json:"createTime"` // 备份时间
+	Crypto     json.RawMessage `json:"crypto"`     // 加密后的内容
+	Checksum   string          `json:"checksum"`   // crypto的sha256，用于不解密时校验完整性
+}
+
+// 备份的内容
+type backupPayload struct {
+	Name         string          `json:"name"`                   // 钱包名称
+	MnemonicType MnemonicType    `json:"mnemonicType,omitempty"` // 助记词类型
+	Wallet       json.RawMessage `json:"wallet"`                 // 钱包内容，包含主私钥、助记词、账户、元数据及设置
+}
+
+// BackupInfo 备份文件的信息
+type BackupInfo struct {
+	Version      int          // 格式版本号
+	CreateTime   int64        // 备份时间
+	Name         string       // 备份时的钱包名称
+	MnemonicType MnemonicType // 助记词类型，钱包未保存助记词时为空
+	Accounts     int          // 账户数量
+	WatchOnly    bool         // 是否为只读钱包
+}
+
+// Backup 生成加密的备份文件，包含主私钥、助记词、账户及其保存的子私钥、元数据及钱包设置
+// backupPwd 备份文件的加密密码
+func (w *Wallet) Backup(backupPwd string) (bundle []byte, err error) {
+	defer func() {
+		if err = w.audit(AuditBackup, "", "", nil, err); err != nil {
+			bundle = nil
+		}
+	}()
+	if len(backupPwd) == 0 {
+		return nil, fmt.Errorf("wallet Backup backupPwd is empty")
+	}
+	w.mu.RLock()
+	if w.locked {
+		w.mu.RUnlock()
+		return nil, ErrWalletLocked
+	}
+	name := w.name
+	if name == "" {
+		name = w.Path
+	}
+	mnemonicType, _ := DetectMnemonicType(w.Mnemonic)
+	walletData, err := json.Marshal(w)
+	w.mu.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("wallet Backup json.Marshal err:%v", err.Error())
+	}
+	defer secret.Zero(walletData)
+
+	payload, err := json.Marshal(&backupPayload{Name: name, MnemonicType: mnemonicType, Wallet: walletData})
+	if err != nil {
+		return nil, fmt.Errorf("wallet Backup json.Marshal err:%v", err.Error())
+	}
+	defer secret.Zero(payload)
+	crypto, err := scrypt.EncryptKey(&scrypt.Key{Id: uuid.NewRandom(), PrivateKey: payload}, backupPwd, scryptN, scryptP)
+	if err != nil {
+		return nil, fmt.Errorf("wallet Backup scrypt.EncryptKey err:%v", err.Error())
+	}
+	checksum := sha256.Sum256(crypto)
+	return json.Marshal(&backupBundle{
+		Version:    backupVersion,
+		CreateTime: time.Now().Unix(),
+		Crypto:     crypto,
+		Checksum:   hex.EncodeToString(checksum[:]),
+	})
+}
+
+// VerifyBackup 校验备份文件的完整性，不需要备份密码
+func VerifyBackup(bundle []byte) error {
+	_, err := parseBackup(bundle)
+	return err
+}
+
+func parseBackup(data []byte) (*backupBundle, error) {
+	bundle := new(backupBundle)
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("%w: json.Unmarshal err:%v", ErrBackupCorrupted, err.Error())
+	}
+	if bundle.Version != backupVersion {
+		return nil, fmt.Errorf("backup bundle version %d is not supported", bundle.Version)
+	}
+	checksum := sha256.Sum256(bundle.Crypto)
+	if bundle.Checksum != hex.EncodeToString(checksum[:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrBackupCorrupted)
+	}
+	return bundle, nil
+}
+
+// Restore 使用备份文件在存储后端中重建钱包，存储后端中已存在同名钱包时返回错误
+// name 钱包名称，为空时使用备份时的名称
+// password 新钱包的密码，为空时使用备份时的钱包密码
+func Restore(store Storage, name string, data []byte, backupPwd string, password string) (*Wallet, *BackupInfo, error) {
+	if nil == store {
+		return nil, nil, fmt.Errorf("Restore storage is nil")
+	}
+	bundle, err := parseBackup(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := scrypt.DecryptKey(bundle.Crypto, backupPwd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Restore scrypt.DecryptKey err:%v", err.Error())
+	}
+	defer secret.Zero(key.PrivateKey)
+	payload := new(backupPayload)
+	if err := json.Unmarshal(key.PrivateKey, payload); err != nil {
+		return nil, nil, fmt.Errorf("Restore json.Unmarshal err:%v", err.Error())
+	}
+	defer secret.Zero(payload.Wallet)
+	wallet := new(Wallet)
+	if err := json.Unmarshal(payload.Wallet, wallet); err != nil {
+		return nil, nil, fmt.Errorf("Restore json.Unmarshal err:%v", err.Error())
+	}
+
+	if name == "" {
+		name = payload.Name
+	}
+	if err := validateStorageName(name); err != nil {
+		return nil, nil, err
+	}
+	if password == "" {
+		password = wallet.Password
+	}
+	if password != wallet.Password {
+		if err := wallet.reencryptSubKeys(wallet.Password, password); err != nil {
+			return nil, nil, err
+		}
+	}
+	wallet.storage = store
+	wallet.name = name
+	wallet.Path = name
+	wallet.Password = password
+	if nil == wallet.ChildKeyInfo {
+		wallet.ChildKeyInfo = make(map[string]*ChildKeyPropertyInfo, 0)
+		wallet.AddrLinkPubkey = make(map[string]string, 0)
+	}
+	if err := writeContentToStorage(wallet, password); err != nil {
+		return nil, nil, fmt.Errorf("Restore storage.Put err:%v", err.Error())
+	}
+	return wallet, &BackupInfo{
+		Version:      bundle.Version,
+		CreateTime:   bundle.CreateTime,
+		Name:         payload.Name,
+		MnemonicType: payload.MnemonicType,
+		Accounts:     len(wallet.AddrLinkPubkey),
+		WatchOnly:    wallet.WatchOnly,
+	}, nil
+}
+
+// 修改钱包密码时，使用新密码重新加密保存的子私钥
+func (w *Wallet) reencryptSubKeys(oldPassword, password string) error {
+	for _, info := range w.ChildKeyInfo {
+		if len(info.Key) == 0 {
+			continue
+		}
+		if info.KeyPath == "" {
+			return fmt.Errorf("wallet subKey without keyPath can only be restored with the original password")
+		}
+		key, err := scrypt.DecryptKey(info.Key, getSubPwd(oldPassword, info.KeyPath))
+		if err != nil {
+			return fmt.Errorf("wallet reencryptSubKeys scrypt.DecryptKey err:%v", err.Error())
+		}
+		encryptKey, err := scrypt.EncryptKey(key, getSubPwd(password, info.KeyPath), scryptN, scryptP)
+		secret.Zero(key.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("wallet reencryptSubKeys scrypt.EncryptKey err:%v", err.Error())
+		}
+		info.Key = encryptKey
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+)
+
+func TestWallet_BackupRestore(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorageAndMnemonicType(store, "w1", "123456", MnemonicType_Chinese_Simplified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetIsSaveSubKey(true)
+	wallet.SetIsSaveExtendedKey(true)
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	addr, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.SetAccountLabel(addr, "customer-alice"); err != nil {
+		t.Fatal(err)
+	}
+	rawKey, err := wallet.ExportRawKey(addr, "", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := wallet.Backup("backup-pwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBackup(bundle); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Restore(store, "", bundle, "backup-pwd", ""); err == nil {
+		t.Fatal("restore to an existing wallet should fail")
+	}
+	if _, _, err := Restore(NewMemoryStorage(), "", bundle, "wrong", ""); err == nil {
+		t.Fatal("restore with wrong backup password should fail")
+	}
+	corrupted := bytes.Replace(bundle, []byte(`"ciphertext":"`), []byte(`"ciphertext":"00`), 1)
+	if err := VerifyBackup(corrupted); !errors.Is(err, ErrBackupCorrupted) {
+		t.Fatalf("err=%v, want ErrBackupCorrupted", err)
+	}
+
+	// 使用新密码恢复到另一个存储后端
+	store2 := NewMemoryStorage()
+	restored, info, err := Restore(store2, "w2", bundle, "backup-pwd", "654321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "w1" || info.Accounts != 1 || info.MnemonicType != MnemonicType_Chinese_Simplified {
+		t.Fatalf("info=%+v", info)
+	}
+	loaded, err := LoadWalletFromStorage(store2, "w2", "654321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []*Wallet{restored, loaded} {
+		if !w.IsSaveSubKey || !w.IsSaveExtendedKey {
+			t.Fatal("settings should be restored")
+		}
+		if w.ExportMasterMnemonic() != wallet.ExportMasterMnemonic() {
+			t.Fatal("mnemonic should be restored")
+		}
+		if key, err := w.ExportRawKey(addr, "", api); err != nil || key != rawKey {
+			t.Fatalf("key=%s err=%v", key, err)
+		}
+		if meta, err := w.GetAccountMeta(addr); err != nil || meta.Label != "customer-alice" {
+			t.Fatalf("meta=%+v err=%v", meta, err)
+		}
+	}
+}
+
+func TestDetectMnemonicType(t *testing.T) {
+	// 简体及繁体中文有相同的字，使用包含不同字的助记词
+	entropy := bytes.Repeat([]byte{0x7f}, 16)
+	for _, want := range mnemonicTypes {
+		mnemonic, err := MnemonicCodec(want).NewMnemonic(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := DetectMnemonicType(mnemonic); err != nil || got != want {
+			t.Fatalf("mnemonic=%s got=%s err=%v, want %s", mnemonic, got, err, want)
+		}
+	}
+	for _, mnemonic := range []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"unknown words",
+		"",
+	} {
+		if _, err := DetectMnemonicType(mnemonic); err == nil {
+			t.Fatalf("mnemonic=%s should be invalid", mnemonic)
+		}
+	}
+}
@@ -15,7 +15,9 @@ import (
 	"io"
 	"math/big"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/chain5j/keybox/crypto/secret"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -34,27 +36,71 @@ var (
 	ErrInvalidChecksum        = errors.New("Checksum doesn't match")
 	ErrInvalidPrivateKey      = errors.New("Invalid private key")
 	ErrInvalidPublicKey       = errors.New("Invalid public key")
+	ErrUnknownCurve           = errors.New("Unknown curve")
+	ErrInvalidVersion         = errors.New("Version doesn't match the key type")
+)
+
+// Curve the elliptic curve used for derivation
+type Curve string
 
-	curve       = elliptic.P256()
-	curveParams = curve.Params()
+const (
+	// CurveP256 the legacy curve of keybox, the master key uses "FOO seed" as hmac key
+	CurveP256 Curve = "p256"
+	// CurveSecp256k1 the standard bip32 curve, the master key uses "Bitcoin seed" as hmac key
+	CurveSecp256k1 Curve = "secp256k1"
 )
 
 // Key represents a bip32 extended key
 type Key struct {
-	Key         []byte `json:"key"`          // 33 bytes
-	Version     []byte `json:"version"`      // 4 bytes
-	ChildNumber []byte `json:"child_number"` // 4 bytes bip44 level
-	FingerPrint []byte `json:"finger_print"` // 4 bytes
-	ChainCode   []byte `json:"chain_code"`   // 32 bytes
-	Depth       byte   `json:"depth"`        // 1 bytes
-	IsPrivate   bool   `json:"is_private"`   // unserialized
+	Key         []byte `json:"key"`             // 33 bytes
+	Version     []byte `json:"version"`         // 4 bytes
+	ChildNumber []byte `json:"child_number"`    // 4 bytes bip44 level
+	FingerPrint []byte `json:"finger_print"`    // 4 bytes
+	ChainCode   []byte `json:"chain_code"`      // 32 bytes
+	Depth       byte   `json:"depth"`           // 1 bytes
+	IsPrivate   bool   `json:"is_private"`      // unserialized
+	Curve       Curve  `json:"curve,omitempty"` // unserialized, empty means CurveP256
+}
+
+// ParseCurve parses the curve name, empty means CurveP256
+func ParseCurve(name string) (Curve, error) {
+	switch Curve(name) {
+	case "", CurveP256:
+		return CurveP256, nil
+	case CurveSecp256k1:
+		return CurveSecp256k1, nil
+	}
+	return "", ErrUnknownCurve
 }
 
-// NewMasterKey creates a new master extended key from a seed
+func (c Curve) elliptic() elliptic.Curve {
+	if c == CurveSecp256k1 {
+		return btcec.S256()
+	}
+	return elliptic.P256()
+}
+
+func (c Curve) seedKey() []byte {
+	if c == CurveSecp256k1 {
+		return []byte("Bitcoin seed")
+	}
+	return []byte("FOO seed")
+}
+
+// NewMasterKey creates a new master extended key from a seed on the legacy CurveP256
 func NewMasterKey(seed []byte) (*Key, error) {
+	return NewMasterKeyWithCurve(seed, "")
+}
+
+// NewMasterKeyWithCurve creates a new master extended key from a seed on the given curve
+func NewMasterKeyWithCurve(seed []byte, c Curve) (*Key, error) {
+	c, err := ParseCurve(string(c))
+	if err != nil {
+		return nil, err
+	}
 	// Generate key and chaincode
-	hmac := hmac.New(sha512.New, []byte("FOO seed"))
-	_, err := hmac.Write(seed)
+	hmac := hmac.New(sha512.New, c.seedKey())
+	_, err = hmac.Write(seed)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +111,7 @@ func NewMasterKey(seed []byte) (*Key, error) {
 	chainCode := intermediary[32:]
 
 	// Validate key
-	err = validatePrivateKey(keyBytes)
+	err = validatePrivateKey(c, keyBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +126,9 @@ func NewMasterKey(seed []byte) (*Key, error) {
 		FingerPrint: []byte{0x00, 0x00, 0x00, 0x00},
 		IsPrivate:   true,
 	}
+	if c != CurveP256 {
+		key.Curve = c
+	}
 
 	return key, nil
 }
@@ -102,40 +151,49 @@ func (key *Key) NewChildKey(childIdx uint32) (*Key, error) {
 		ChainCode:   intermediary[32:],
 		Depth:       key.Depth + 1,
 		IsPrivate:   key.IsPrivate,
+		Curve:       key.Curve,
 	}
 
+	// The left half of the intermediary is only needed to compute the child key
+	defer secret.Zero(intermediary[:32])
+
 	// Bip32 CKDpriv
 	if key.IsPrivate {
-		childKey.Version = PrivateWalletVersion
-		fingerprint, err := hash160(publicKeyForPrivateKey(key.Key))
+		childKey.Version = privateVersionOf(key.Version)
+		fingerprint, err := hash160(publicKeyForPrivateKey(key.Curve, key.Key))
 		if err != nil {
 			return nil, err
 		}
 		childKey.FingerPrint = fingerprint[:4]
-		childKey.Key = addPrivateKeys(intermediary[:32], key.Key)
+		childKey.Key = addPrivateKeys(key.Curve, intermediary[:32], key.Key)
 
 		// Validate key
-		err = validatePrivateKey(childKey.Key)
+		err = validatePrivateKey(key.Curve, childKey.Key)
 		if err != nil {
 			return nil, err
 		}
 		// Bip32 CKDpub
 	} else {
-		keyBytes := publicKeyForPrivateKey(intermediary[:32])
+		// The left half must be a valid scalar
+		err := validatePrivateKey(key.Curve, intermediary[:32])
+		if err != nil {
+			return nil, err
+		}
+		keyBytes := publicKeyForPrivateKey(key.Curve, intermediary[:32])
 
 		// Validate key
-		err := validateChildPublicKey(keyBytes)
+		err = validateChildPublicKey(key.Curve, keyBytes)
 		if err != nil {
 			return nil, err
 		}
 
-		childKey.Version = PublicWalletVersion
+		childKey.Version = publicVersionOf(key.Version)
 		fingerprint, err := hash160(key.Key)
 		if err != nil {
 			return nil, err
 		}
 		childKey.FingerPrint = fingerprint[:4]
-		childKey.Key = addPublicKeys(keyBytes, key.Key)
+		childKey.Key = addPublicKeys(key.Curve, keyBytes, key.Key)
 	}
 
 	return childKey, nil
@@ -150,9 +208,10 @@ func (key *Key) getIntermediary(childIdx uint32) ([]byte, error) {
 	var data []byte
 	if childIdx >= FirstHardenedChild {
 		data = append([]byte{0x0}, key.Key...)
+		defer secret.Zero(data)
 	} else {
 		if key.IsPrivate {
-			data = publicKeyForPrivateKey(key.Key)
+			data = publicKeyForPrivateKey(key.Curve, key.Key)
 		} else {
 			data = key.Key
 		}
@@ -173,18 +232,61 @@ func (key *Key) PublicKey() *Key {
 	keyBytes := key.Key
 
 	if key.IsPrivate {
-		keyBytes = publicKeyForPrivateKey(keyBytes)
+		keyBytes = publicKeyForPrivateKey(key.Curve, keyBytes)
 	}
 
 	return &Key{
-		Version:     PublicWalletVersion,
+		Version:     publicVersionOf(key.Version),
 		Key:         keyBytes,
 		Depth:       key.Depth,
 		ChildNumber: key.ChildNumber,
 		FingerPrint: key.FingerPrint,
 		ChainCode:   key.ChainCode,
 		IsPrivate:   false,
+		Curve:       key.Curve,
+	}
+}
+
+// UncompressedPublicKey returns the 65 bytes uncompressed public key (0x04 || X || Y)
+func (key *Key) UncompressedPublicKey() ([]byte, error) {
+	keyBytes := key.Key
+	if key.IsPrivate {
+		keyBytes = publicKeyForPrivateKey(key.Curve, keyBytes)
+	}
+	if len(keyBytes) != PublicKeyCompressedLength {
+		return nil, ErrInvalidPublicKey
+	}
+	x, y := expandPublicKey(key.Curve, keyBytes)
+	if !key.Curve.elliptic().IsOnCurve(x, y) {
+		return nil, ErrInvalidPublicKey
+	}
+	pub := make([]byte, 65)
+	pub[0] = 0x04
+	x.FillBytes(pub[1:33])
+	y.FillBytes(pub[33:])
+	return pub, nil
+}
+
+// Copy returns a deep copy of the key
+func (key *Key) Copy() *Key {
+	return &Key{
+		Key:         copyBytes(key.Key),
+		Version:     copyBytes(key.Version),
+		ChildNumber: copyBytes(key.ChildNumber),
+		FingerPrint: copyBytes(key.FingerPrint),
+		ChainCode:   copyBytes(key.ChainCode),
+		Depth:       key.Depth,
+		IsPrivate:   key.IsPrivate,
+		Curve:       key.Curve,
+	}
+}
+
+// Zero overwrites the key material and chain code with zeros
+func (key *Key) Zero() {
+	if key == nil {
+		return
 	}
+	secret.ZeroAll(key.Key, key.ChainCode)
 }
 
 // Serialize a Key to a 78 byte byte slice
@@ -193,19 +295,21 @@ func (key *Key) Serialize() ([]byte, error) {
 	keyBytes := key.Key
 	if key.IsPrivate {
 		keyBytes = append([]byte{0x0}, keyBytes...)
+		defer secret.Zero(keyBytes)
 	}
 
-	// Write fields to buffer in order
-	buffer := new(bytes.Buffer)
-	buffer.Write(key.Version)
-	buffer.WriteByte(key.Depth)
-	buffer.Write(key.FingerPrint)
-	buffer.Write(key.ChildNumber)
-	buffer.Write(key.ChainCode)
-	buffer.Write(keyBytes)
+	// Write fields to buffer in order, the capacity includes the checksum
+	// so that no partial copy of the key is left behind by a reallocation
+	buffer := make([]byte, 0, 82)
+	buffer = append(buffer, key.Version...)
+	buffer = append(buffer, key.Depth)
+	buffer = append(buffer, key.FingerPrint...)
+	buffer = append(buffer, key.ChildNumber...)
+	buffer = append(buffer, key.ChainCode...)
+	buffer = append(buffer, keyBytes...)
 
 	// Append the standard doublesha256 checksum
-	serializedKey, err := addChecksumToBytes(buffer.Bytes())
+	serializedKey, err := addChecksumToBytes(buffer)
 	if err != nil {
 		return nil, err
 	}
@@ -219,6 +323,7 @@ func (key *Key) B58Serialize() string {
 	if err != nil {
 		return ""
 	}
+	defer secret.Zero(serializedKey)
 
 	return base58.Encode(serializedKey)
 }
@@ -260,6 +365,15 @@ func Deserialize(data []byte) (*Key, error) {
 			return nil, ErrInvalidChecksum
 		}
 	}
+
+	// validate version, accept all the versions of SLIP-0132
+	_, isPrivate, ok := lookupVersion(key.Version)
+	if !ok {
+		return nil, ErrUnknownVersion
+	}
+	if isPrivate != key.IsPrivate {
+		return nil, ErrInvalidVersion
+	}
 	return key, nil
 }
 
@@ -269,6 +383,22 @@ func B58Deserialize(data string) (*Key, error) {
 	return Deserialize(b)
 }
 
+// B58DeserializeWithCurve deserializes a Key encoded in base58 encoding on the given curve
+func B58DeserializeWithCurve(data string, c Curve) (*Key, error) {
+	c, err := ParseCurve(string(c))
+	if err != nil {
+		return nil, err
+	}
+	key, err := B58Deserialize(data)
+	if err != nil {
+		return nil, err
+	}
+	if c != CurveP256 {
+		key.Curve = c
+	}
+	return key, nil
+}
+
 // NewSeed returns a cryptographically secure seed
 func NewSeed() ([]byte, error) {
 	// Well that easy, just make go read 256 random bytes into a slice
@@ -348,33 +478,40 @@ func addChecksumToBytes(data []byte) ([]byte, error) {
 }
 
 // Keys
-func publicKeyForPrivateKey(key []byte) []byte {
-	return compressPublicKey(curve.ScalarBaseMult(key))
+func publicKeyForPrivateKey(c Curve, key []byte) []byte {
+	return compressPublicKey(c.elliptic().ScalarBaseMult(key))
 }
 
-func addPublicKeys(key1 []byte, key2 []byte) []byte {
-	x1, y1 := expandPublicKey(key1)
-	x2, y2 := expandPublicKey(key2)
-	return compressPublicKey(curve.Add(x1, y1, x2, y2))
+func addPublicKeys(c Curve, key1 []byte, key2 []byte) []byte {
+	x1, y1 := expandPublicKey(c, key1)
+	x2, y2 := expandPublicKey(c, key2)
+	return compressPublicKey(c.elliptic().Add(x1, y1, x2, y2))
 }
 
-func addPrivateKeys(key1 []byte, key2 []byte) []byte {
+func addPrivateKeys(c Curve, key1 []byte, key2 []byte) []byte {
 	var key1Int big.Int
 	var key2Int big.Int
 	key1Int.SetBytes(key1)
 	key2Int.SetBytes(key2)
 
 	key1Int.Add(&key1Int, &key2Int)
-	key1Int.Mod(&key1Int, curve.Params().N)
+	key1Int.Mod(&key1Int, c.elliptic().Params().N)
 
-	b := key1Int.Bytes()
-	if len(b) < 32 {
-		extra := make([]byte, 32-len(b))
-		b = append(extra, b...)
-	}
+	b := make([]byte, 32)
+	key1Int.FillBytes(b)
+	zeroBigInt(&key1Int)
+	zeroBigInt(&key2Int)
 	return b
 }
 
+func zeroBigInt(i *big.Int) {
+	words := i.Bits()
+	for j := range words {
+		words[j] = 0
+	}
+	i.SetInt64(0)
+}
+
 func compressPublicKey(x *big.Int, y *big.Int) []byte {
 	var key bytes.Buffer
 
@@ -392,19 +529,26 @@ func compressPublicKey(x *big.Int, y *big.Int) []byte {
 }
 
 // As described at https://crypto.stackexchange.com/a/8916
-func expandPublicKey(key []byte) (*big.Int, *big.Int) {
+func expandPublicKey(c Curve, key []byte) (*big.Int, *big.Int) {
+	curveParams := c.elliptic().Params()
 	Y := big.NewInt(0)
 	X := big.NewInt(0)
 	X.SetBytes(key[1:])
 
-	// y^2 = x^3 + ax^2 + b
-	// a = 0
-	// => y^2 = x^3 + b
+	// y^2 = x^3 + ax + b
+	// secp256k1: a = 0, P256: a = -3
 	ySquared := big.NewInt(0)
-	ySquared.Exp(X, big.NewInt(3), nil)
+	ySquared.Exp(X, big.NewInt(3), curveParams.P)
+	if c != CurveSecp256k1 {
+		threeX := new(big.Int).Mul(X, big.NewInt(3))
+		ySquared.Sub(ySquared, threeX)
+	}
 	ySquared.Add(ySquared, curveParams.B)
+	ySquared.Mod(ySquared, curveParams.P)
 
-	Y.ModSqrt(ySquared, curveParams.P)
+	if Y.ModSqrt(ySquared, curveParams.P) == nil {
+		return big.NewInt(0), big.NewInt(0)
+	}
 
 	Ymod2 := big.NewInt(0)
 	Ymod2.Mod(Y, big.NewInt(2))
@@ -417,9 +561,9 @@ func expandPublicKey(key []byte) (*big.Int, *big.Int) {
 	return X, Y
 }
 
-func validatePrivateKey(key []byte) error {
-	if fmt.Sprintf("%x", key) == "0000000000000000000000000000000000000000000000000000000000000000" || // if the key is zero
-		bytes.Compare(key, curveParams.N.Bytes()) >= 0 || // or is outside of the curve
+func validatePrivateKey(c Curve, key []byte) error {
+	if secret.IsZero(key) || // if the key is zero
+		bytes.Compare(key, c.elliptic().Params().N.Bytes()) >= 0 || // or is outside of the curve
 		len(key) != 32 { // or is too short
 		return ErrInvalidPrivateKey
 	}
@@ -427,8 +571,8 @@ func validatePrivateKey(key []byte) error {
 	return nil
 }
 
-func validateChildPublicKey(key []byte) error {
-	x, y := expandPublicKey(key)
+func validateChildPublicKey(c Curve, key []byte) error {
+	x, y := expandPublicKey(c, key)
 
 	if x.Sign() == 0 || y.Sign() == 0 {
 		return ErrInvalidPublicKey
@@ -437,6 +581,15 @@ func validateChildPublicKey(key []byte) error {
 	return nil
 }
 
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
+
 // Numerical
 func uint32Bytes(i uint32) []byte {
 	bytes := make([]byte, 4)
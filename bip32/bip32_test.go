@@ -1,6 +1,7 @@
 package bip32
 
 import (
+	"encoding/hex"
 	"testing"
 )
 
@@ -19,3 +20,187 @@ func Test_Main(t *testing.T) {
 	t.Log(PubKeyToAddr(mkey.PublicKey().Key))
 
 }
+
+func TestKey_CopyAndZero(t *testing.T) {
+	mkey, err := NewMasterKey([]byte("123456789012345678901234567890ab"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	str := mkey.String()
+	cp := mkey.Copy()
+	mkey.Zero()
+	for _, b := range append(mkey.Key, mkey.ChainCode...) {
+		if b != 0 {
+			t.Fatalf("key should be zeroed")
+		}
+	}
+	if cp.String() != str {
+		t.Fatalf("copy should not share memory with the original key")
+	}
+}
+
+func TestNewMasterKeyWithCurve_Vector1(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	mkey, err := NewMasterKeyWithCurve(seed, CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mkey.String() != "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi" {
+		t.Fatalf("master xprv=%s", mkey.String())
+	}
+	child, err := mkey.NewChildKey(FirstHardenedChild)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err = child.NewChildKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.PublicKey().String() != "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ" {
+		t.Fatalf("m/0H/1 xpub=%s", child.PublicKey().String())
+	}
+}
+
+func TestKey_PublicDerivation(t *testing.T) {
+	for _, c := range []Curve{CurveP256, CurveSecp256k1} {
+		mkey, err := NewMasterKeyWithCurve([]byte("123456789012345678901234567890ab"), c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		account, err := mkey.NewChildKey(FirstHardenedChild)
+		if err != nil {
+			t.Fatal(err)
+		}
+		xpub, err := B58DeserializeWithCurve(account.PublicKey().String(), c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, idx := range []uint32{0, 1, 7} {
+			prv, err := account.NewChildKey(idx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pub, err := xpub.NewChildKey(idx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prv.PublicKey().String() != pub.String() {
+				t.Fatalf("curve %s child %d public derivation mismatch", c, idx)
+			}
+			uncompressed, err := pub.UncompressedPublicKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(uncompressed) != 65 || uncompressed[0] != 0x04 {
+				t.Fatalf("uncompressed public key=%x", uncompressed)
+			}
+		}
+		if _, err := xpub.NewChildKey(FirstHardenedChild); err != ErrHardenedChildPublicKey {
+			t.Fatalf("hardened public derivation err=%v, want ErrHardenedChildPublicKey", err)
+		}
+	}
+}
+
+func TestB58Deserialize_Versions(t *testing.T) {
+	mkey, err := NewMasterKeyWithCurve([]byte("123456789012345678901234567890ab"), CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefixes := map[ScriptType][2]string{
+		ScriptP2PKH:        {"xpub", "tpub"},
+		ScriptP2WPKHInP2SH: {"ypub", "upub"},
+		ScriptP2WPKH:       {"zpub", "vpub"},
+		ScriptP2WSHInP2SH:  {"Ypub", "Upub"},
+		ScriptP2WSH:        {"Zpub", "Vpub"},
+	}
+	for script, prefix := range prefixes {
+		for i, testnet := range []bool{false, true} {
+			for _, key := range []*Key{mkey, mkey.PublicKey()} {
+				k, err := key.WithVersion(script, testnet)
+				if err != nil {
+					t.Fatal(err)
+				}
+				str := k.String()
+				if !key.IsPrivate && str[:4] != prefix[i] {
+					t.Fatalf("script %d testnet %v prefix=%s, want %s", script, testnet, str[:4], prefix[i])
+				}
+				parsed, err := B58Deserialize(str)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if parsed.IsPrivate != key.IsPrivate || parsed.String() != str {
+					t.Fatalf("deserialize %s mismatch", str)
+				}
+				s, net, _, err := ParseVersion(parsed.Version)
+				if err != nil || s != script || net != testnet {
+					t.Fatalf("parse version %x err=%v", parsed.Version, err)
+				}
+				// 子密钥沿用父密钥的版本
+				child, err := parsed.NewChildKey(0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if child.PublicKey().String()[:4] != prefix[i] {
+					t.Fatalf("child version=%x", child.Version)
+				}
+			}
+		}
+	}
+
+	// 未知的版本号
+	data, err := mkey.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append([]byte{0x01, 0x02, 0x03, 0x04}, data[4:78]...)
+	data, _ = addChecksumToBytes(data)
+	if _, err := Deserialize(data); err != ErrUnknownVersion {
+		t.Fatalf("deserialize unknown version err=%v, want ErrUnknownVersion", err)
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	valid := map[string]string{
+		"m":                   "m",
+		"m/44'/60'/0'/0/0":    "m/44'/60'/0'/0/0",
+		"m/44h/60H/0'/1/7":    "m/44'/60'/0'/1/7",
+		"44'/0'":              "m/44'/0'",
+		"m/2147483647'/1/2/3": "m/2147483647'/1/2/3",
+	}
+	for in, want := range valid {
+		path, err := ParseDerivationPath(in)
+		if err != nil {
+			t.Fatalf("%s err:%v", in, err)
+		}
+		if path.String() != want {
+			t.Fatalf("%s => %s, want %s", in, path.String(), want)
+		}
+	}
+	for _, in := range []string{"m/", "m//0", "/44/60", "m/44''", "m/-1", "m/+1", "m/2147483648", "m/1x", "n/0"} {
+		if _, err := ParseDerivationPath(in); err != ErrInvalidPath {
+			t.Fatalf("%s err=%v, want ErrInvalidPath", in, err)
+		}
+	}
+}
+
+func TestKey_DerivePath(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	mkey, err := NewMasterKeyWithCurve(seed, CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := ParseDerivationPath("m/0H/1/2H/2/1000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := mkey.DerivePath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.PublicKey().String() != "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy" {
+		t.Fatalf("xpub=%s", key.PublicKey().String())
+	}
+	if mkey.String() != "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi" {
+		t.Fatalf("master key should not be changed")
+	}
+}
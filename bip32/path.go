@@ -0,0 +1,95 @@
+package bip32
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPath = errors.New("Invalid derivation path")
+
+// DerivationPath a bip32 derivation path, hardened indexes include FirstHardenedChild
+type DerivationPath []uint32
+
+// ParseDerivationPath parses the standard notation, such as m/44'/60'/0'/0/0
+// The "m" prefix is optional, hardened indexes are marked with ', h or H
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	path = strings.TrimSpace(path)
+	if path == "m" || path == "" {
+		return DerivationPath{}, nil
+	}
+	path = strings.TrimPrefix(path, "m/")
+	components := strings.Split(path, "/")
+	result := make(DerivationPath, 0, len(components))
+	for _, component := range components {
+		index, err := parseComponent(component)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, index)
+	}
+	return result, nil
+}
+
+func parseComponent(component string) (uint32, error) {
+	hardened := false
+	if n := len(component); n > 0 {
+		switch component[n-1] {
+		case '\'', 'h', 'H':
+			hardened = true
+			component = component[:n-1]
+		}
+	}
+	// only plain decimal digits, no sign or whitespace
+	if len(component) == 0 || strings.TrimLeft(component, "0123456789") != "" {
+		return 0, ErrInvalidPath
+	}
+	index, err := strconv.ParseUint(component, 10, 32)
+	if err != nil || uint32(index) >= FirstHardenedChild {
+		return 0, ErrInvalidPath
+	}
+	if hardened {
+		return uint32(index) + FirstHardenedChild, nil
+	}
+	return uint32(index), nil
+}
+
+// String returns the standard notation, such as m/44'/60'/0'/0/0
+func (p DerivationPath) String() string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range p {
+		b.WriteString("/")
+		if index >= FirstHardenedChild {
+			b.WriteString(strconv.FormatUint(uint64(index-FirstHardenedChild), 10))
+			b.WriteString("'")
+		} else {
+			b.WriteString(strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return b.String()
+}
+
+// IsHardened whether the index at position i is hardened
+func (p DerivationPath) IsHardened(i int) bool {
+	return p[i] >= FirstHardenedChild
+}
+
+// DerivePath derives the descendant key along the path, intermediate keys are zeroed
+func (key *Key) DerivePath(path DerivationPath) (*Key, error) {
+	if len(path) == 0 {
+		return key.Copy(), nil
+	}
+	current := key
+	for _, index := range path {
+		child, err := current.NewChildKey(index)
+		if current != key {
+			current.Zero()
+		}
+		if err != nil {
+			return nil, err
+		}
+		current = child
+	}
+	return current, nil
+}
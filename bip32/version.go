@@ -0,0 +1,125 @@
+package bip32
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+)
+
+// ScriptType the script type of the extended key, see SLIP-0132
+type ScriptType int
+
+const (
+	ScriptP2PKH        ScriptType = iota // xpub / tpub
+	ScriptP2WPKHInP2SH                   // ypub / upub
+	ScriptP2WPKH                         // zpub / vpub
+	ScriptP2WSHInP2SH                    // Ypub / Upub
+	ScriptP2WSH                          // Zpub / Vpub
+)
+
+var ErrUnknownVersion = errors.New("Unknown extended key version")
+
+type keyVersion struct {
+	private []byte
+	public  []byte
+	script  ScriptType
+	testnet bool
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// https://github.com/satoshilabs/slips/blob/master/slip-0132.md
+var keyVersions = []keyVersion{
+	{PrivateWalletVersion, PublicWalletVersion, ScriptP2PKH, false},                   // xprv / xpub
+	{mustDecodeHex("049d7878"), mustDecodeHex("049d7cb2"), ScriptP2WPKHInP2SH, false}, // yprv / ypub
+	{mustDecodeHex("04b2430c"), mustDecodeHex("04b24746"), ScriptP2WPKH, false},       // zprv / zpub
+	{mustDecodeHex("0295b005"), mustDecodeHex("0295b43f"), ScriptP2WSHInP2SH, false},  // Yprv / Ypub
+	{mustDecodeHex("02aa7a99"), mustDecodeHex("02aa7ed3"), ScriptP2WSH, false},        // Zprv / Zpub
+	{mustDecodeHex("04358394"), mustDecodeHex("043587cf"), ScriptP2PKH, true},         // tprv / tpub
+	{mustDecodeHex("044a4e28"), mustDecodeHex("044a5262"), ScriptP2WPKHInP2SH, true},  // uprv / upub
+	{mustDecodeHex("045f18bc"), mustDecodeHex("045f1cf6"), ScriptP2WPKH, true},        // vprv / vpub
+	{mustDecodeHex("024285b5"), mustDecodeHex("024289ef"), ScriptP2WSHInP2SH, true},   // Uprv / Upub
+	{mustDecodeHex("02575048"), mustDecodeHex("02575483"), ScriptP2WSH, true},         // Vprv / Vpub
+}
+
+// Versions returns the private and public version bytes of the script type and network
+func Versions(script ScriptType, testnet bool) (private []byte, public []byte, err error) {
+	for _, v := range keyVersions {
+		if v.script == script && v.testnet == testnet {
+			return v.private, v.public, nil
+		}
+	}
+	return nil, nil, ErrUnknownVersion
+}
+
+// ScriptTypeForPurpose returns the script type of the purpose, 49 uses ypub, 84 uses zpub, others use xpub
+func ScriptTypeForPurpose(purpose uint32) ScriptType {
+	if purpose >= FirstHardenedChild {
+		purpose -= FirstHardenedChild
+	}
+	switch purpose {
+	case 49:
+		return ScriptP2WPKHInP2SH
+	case 84:
+		return ScriptP2WPKH
+	}
+	return ScriptP2PKH
+}
+
+// ParseVersion returns the script type and network of the version bytes
+func ParseVersion(version []byte) (script ScriptType, testnet bool, isPrivate bool, err error) {
+	v, isPrivate, ok := lookupVersion(version)
+	if !ok {
+		return 0, false, false, ErrUnknownVersion
+	}
+	return v.script, v.testnet, isPrivate, nil
+}
+
+// WithVersion returns a copy of the key using the version bytes of the script type and network
+func (key *Key) WithVersion(script ScriptType, testnet bool) (*Key, error) {
+	private, public, err := Versions(script, testnet)
+	if err != nil {
+		return nil, err
+	}
+	k := key.Copy()
+	if k.IsPrivate {
+		k.Version = copyBytes(private)
+	} else {
+		k.Version = copyBytes(public)
+	}
+	return k, nil
+}
+
+func lookupVersion(version []byte) (keyVersion, bool, bool) {
+	for _, v := range keyVersions {
+		if bytes.Equal(v.private, version) {
+			return v, true, true
+		}
+		if bytes.Equal(v.public, version) {
+			return v, false, true
+		}
+	}
+	return keyVersion{}, false, false
+}
+
+// the private version of the same script type and network, xprv for unknown versions
+func privateVersionOf(version []byte) []byte {
+	if v, _, ok := lookupVersion(version); ok {
+		return v.private
+	}
+	return PrivateWalletVersion
+}
+
+// the public version of the same script type and network, xpub for unknown versions
+func publicVersionOf(version []byte) []byte {
+	if v, _, ok := lookupVersion(version); ok {
+		return v.public
+	}
+	return PublicWalletVersion
+}
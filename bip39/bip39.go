@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync/atomic"
 
 	"github.com/chain5j/keybox/bip39/wordlists"
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
 )
 
 var (
@@ -40,13 +42,13 @@ var (
 		21: big.NewInt(2),
 	}
 
-	// wordList is the set of words to use
-	wordList []string
-
-	// wordMap is a reverse lookup map for wordList
-	wordMap map[string]int
+	// defaultMnemonic is the codec used by the package-level functions
+	defaultMnemonic atomic.Pointer[Mnemonic]
 )
 
+// IdeographicSpace is the separator of Japanese mnemonics.
+const IdeographicSpace = "\u3000"
+
 var (
 	// ErrInvalidMnemonic is returned when trying to use a malformed mnemonic.
 	ErrInvalidMnemonic = errors.New("Invalid mnenomic")
@@ -65,30 +67,90 @@ var (
 )
 
 func init() {
-	SetWordList(wordlists.ChineseSimplified)
+	SetWordList(wordlists.English)
 }
 
-// SetWordList sets the list of words to use for mnemonics. Currently the list
-// that is set is used package-wide.
-func SetWordList(list []string) {
-	wordList = list
-	wordMap = map[string]int{}
-	for i, v := range wordList {
-		wordMap[v] = i
+// Mnemonic is a mnemonic codec carrying its own word list. It is immutable
+// and safe for concurrent use, so codecs of different languages can be used
+// side by side.
+type Mnemonic struct {
+	// wordList is the set of words to use
+	wordList []string
+
+	// wordMap is a reverse lookup map for wordList, keyed by NFKD form
+	wordMap map[string]int
+
+	// separator joins the words of a new mnemonic
+	separator string
+}
+
+// NewMnemonicCodec returns a codec using the given list of 2048 words.
+// Mnemonics of the Japanese list are joined with IdeographicSpace.
+func NewMnemonicCodec(list []string) *Mnemonic {
+	separator := " "
+	if len(list) > 0 && list[0] == wordlists.Japanese[0] {
+		separator = IdeographicSpace
+	}
+	return NewMnemonicCodecWithSeparator(list, separator)
+}
+
+// NewMnemonicCodecWithSeparator returns a codec using the given list of 2048
+// words and the separator to join the words of a new mnemonic.
+func NewMnemonicCodecWithSeparator(list []string, separator string) *Mnemonic {
+	m := &Mnemonic{
+		wordList:  list,
+		wordMap:   make(map[string]int, len(list)),
+		separator: separator,
 	}
+	for i, v := range list {
+		m.wordMap[norm.NFKD.String(v)] = i
+	}
+	return m
+}
+
+// DefaultMnemonic returns the codec used by the package-level functions.
+func DefaultMnemonic() *Mnemonic {
+	return defaultMnemonic.Load()
 }
 
-// GetWordList gets the list of words to use for mnemonics.
+// SetWordList sets the list of words used by the package-level functions.
+// Prefer a codec from NewMnemonicCodec when several languages are in use.
+func SetWordList(list []string) {
+	defaultMnemonic.Store(NewMnemonicCodec(list))
+}
+
+// GetWordList gets the list of words used by the package-level functions.
 func GetWordList() []string {
-	return wordList
+	return DefaultMnemonic().WordList()
 }
 
-// GetWordIndex gets word index in wordMap.
+// GetWordIndex gets word index in the list used by the package-level functions.
 func GetWordIndex(word string) (int, bool) {
-	idx, ok := wordMap[word]
+	return DefaultMnemonic().WordIndex(word)
+}
+
+// WordList gets the list of words of the codec.
+func (m *Mnemonic) WordList() []string {
+	return m.wordList
+}
+
+// WordIndex gets word index in the list of the codec.
+func (m *Mnemonic) WordIndex(word string) (int, bool) {
+	idx, ok := m.wordMap[norm.NFKD.String(word)]
 	return idx, ok
 }
 
+// DetectMnemonic returns the first codec which the mnemonic is valid for,
+// checksum included. ErrInvalidMnemonic is returned if there is none.
+func DetectMnemonic(mnemonic string, codecs ...*Mnemonic) (*Mnemonic, error) {
+	for _, m := range codecs {
+		if _, err := m.EntropyFromMnemonic(mnemonic); err == nil {
+			return m, nil
+		}
+	}
+	return nil, ErrInvalidMnemonic
+}
+
 // NewEntropy will create random entropy bytes
 // so long as the requested size bitSize is an appropriate size.
 //
@@ -108,6 +170,12 @@ func NewEntropy(bitSize int) ([]byte, error) {
 // and returns the input entropy used to generate the given mnemonic.
 // An error is returned if the given mnemonic is invalid.
 func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	return DefaultMnemonic().EntropyFromMnemonic(mnemonic)
+}
+
+// EntropyFromMnemonic returns the input entropy used to generate the given
+// mnemonic with the word list of the codec.
+func (m *Mnemonic) EntropyFromMnemonic(mnemonic string) ([]byte, error) {
 	mnemonicSlice, isValid := splitMnemonicWords(mnemonic)
 	if !isValid {
 		return nil, ErrInvalidMnemonic
@@ -116,7 +184,7 @@ func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
 	// Decode the words into a big.Int.
 	b := big.NewInt(0)
 	for _, v := range mnemonicSlice {
-		index, ok := wordMap[v]
+		index, ok := m.wordMap[v]
 		if !ok {
 			return nil, fmt.Errorf("word `%v` not found in reverse map", v)
 		}
@@ -162,6 +230,11 @@ func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
 // the given entropy.
 // If the provide entropy is invalid, an error will be returned.
 func NewMnemonic(entropy []byte) (string, error) {
+	return DefaultMnemonic().NewMnemonic(entropy)
+}
+
+// NewMnemonic returns the mnemonic words of the codec for the given entropy.
+func (m *Mnemonic) NewMnemonic(entropy []byte) (string, error) {
 	// Compute some lengths for convenience.
 	entropyBitLength := len(entropy) * 8
 	checksumBitLength := entropyBitLength / 32
@@ -202,18 +275,23 @@ func NewMnemonic(entropy []byte) (string, error) {
 		wordBytes := padByteSlice(word.Bytes(), 2)
 
 		// Convert bytes to an index and add that word to the list.
-		words[i] = wordList[binary.BigEndian.Uint16(wordBytes)]
+		words[i] = m.wordList[binary.BigEndian.Uint16(wordBytes)]
 	}
 
-	return strings.Join(words, " "), nil
+	return strings.Join(words, m.separator), nil
 }
 
 // MnemonicToByteArray takes a mnemonic string and turns it into a byte array
 // suitable for creating another mnemonic.
 // An error is returned if the mnemonic is invalid.
 func MnemonicToByteArray(mnemonic string, raw ...bool) ([]byte, error) {
+	return DefaultMnemonic().MnemonicToByteArray(mnemonic, raw...)
+}
+
+// MnemonicToByteArray turns a mnemonic of the codec into a byte array.
+func (m *Mnemonic) MnemonicToByteArray(mnemonic string, raw ...bool) ([]byte, error) {
 	var (
-		mnemonicSlice    = strings.Split(mnemonic, " ")
+		mnemonicSlice    = strings.Fields(norm.NFKD.String(mnemonic))
 		entropyBitSize   = len(mnemonicSlice) * 11
 		checksumBitSize  = entropyBitSize % 32
 		fullByteSize     = (entropyBitSize-checksumBitSize)/8 + 1
@@ -222,7 +300,7 @@ func MnemonicToByteArray(mnemonic string, raw ...bool) ([]byte, error) {
 
 	// Pre validate that the mnemonic is well formed and only contains words that
 	// are present in the word list.
-	if !IsMnemonicValid(mnemonic) {
+	if !m.IsMnemonicValid(mnemonic) {
 		return nil, ErrInvalidMnemonic
 	}
 
@@ -230,7 +308,7 @@ func MnemonicToByteArray(mnemonic string, raw ...bool) ([]byte, error) {
 	checksummedEntropy := big.NewInt(0)
 	modulo := big.NewInt(2048)
 	for _, v := range mnemonicSlice {
-		index := big.NewInt(int64(wordMap[v]))
+		index := big.NewInt(int64(m.wordMap[v]))
 		checksummedEntropy.Mul(checksummedEntropy, modulo)
 		checksummedEntropy.Add(checksummedEntropy, index)
 	}
@@ -265,7 +343,13 @@ func MnemonicToByteArray(mnemonic string, raw ...bool) ([]byte, error) {
 // NewSeedWithErrorChecking creates a hashed seed output given the mnemonic string and a password.
 // An error is returned if the mnemonic is not convertible to a byte array.
 func NewSeedWithErrorChecking(mnemonic string, password string) ([]byte, error) {
-	_, err := MnemonicToByteArray(mnemonic)
+	return DefaultMnemonic().NewSeedWithErrorChecking(mnemonic, password)
+}
+
+// NewSeedWithErrorChecking creates a hashed seed output given a mnemonic of
+// the codec and a password.
+func (m *Mnemonic) NewSeedWithErrorChecking(mnemonic string, password string) ([]byte, error) {
+	_, err := m.MnemonicToByteArray(mnemonic)
 	if err != nil {
 		return nil, err
 	}
@@ -273,17 +357,25 @@ func NewSeedWithErrorChecking(mnemonic string, password string) ([]byte, error)
 }
 
 // NewSeed creates a hashed seed output given a provided string and password.
+// Both are NFKD normalized as BIP39 requires, so an ideographic space is
+// treated as a space.
 // No checking is performed to validate that the string provided is a valid mnemonic.
 func NewSeed(mnemonic string, password string) []byte {
-	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+password), 2048, 64, sha512.New)
+	return pbkdf2.Key([]byte(norm.NFKD.String(mnemonic)), []byte("mnemonic"+norm.NFKD.String(password)), 2048, 64, sha512.New)
 }
 
 // IsMnemonicValid attempts to verify that the provided mnemonic is valid.
 // Validity is determined by both the number of words being appropriate,
 // and that all the words in the mnemonic are present in the word list.
 func IsMnemonicValid(mnemonic string) bool {
+	return DefaultMnemonic().IsMnemonicValid(mnemonic)
+}
+
+// IsMnemonicValid verifies that the provided mnemonic is valid for the
+// word list of the codec. The checksum is not verified.
+func (m *Mnemonic) IsMnemonicValid(mnemonic string) bool {
 	// Create a list of all the words in the mnemonic sentence
-	words := strings.Fields(mnemonic)
+	words := strings.Fields(norm.NFKD.String(mnemonic))
 
 	// Get word count
 	wordCount := len(words)
@@ -295,7 +387,7 @@ func IsMnemonicValid(mnemonic string) bool {
 
 	// Check if all words belong in the wordlist
 	for _, word := range words {
-		if _, ok := wordMap[word]; !ok {
+		if _, ok := m.wordMap[word]; !ok {
 			return false
 		}
 	}
@@ -379,8 +471,9 @@ func compareByteSlices(a, b []byte) bool {
 }
 
 func splitMnemonicWords(mnemonic string) ([]string, bool) {
-	// Create a list of all the words in the mnemonic sentence
-	words := strings.Fields(mnemonic)
+	// Create a list of all the words in the mnemonic sentence,
+	// NFKD turns the ideographic space into a space
+	words := strings.Fields(norm.NFKD.String(mnemonic))
 
 	// Get num of words
 	numOfWords := len(words)
@@ -1,10 +1,16 @@
 package bip39
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip39/wordlists"
+	"golang.org/x/text/unicode/norm"
 )
 
 type vector struct {
@@ -72,3 +78,138 @@ func testVectors() []vector {
 		},
 	}
 }
+
+func TestMnemonicCodec(t *testing.T) {
+	english := NewMnemonicCodec(wordlists.English)
+	chinese := NewMnemonicCodec(wordlists.ChineseSimplified)
+	for _, vector := range testVectors() {
+		entropy, _ := hex.DecodeString(vector.entropy)
+		mnemonic, err := english.NewMnemonic(entropy)
+		if err != nil || mnemonic != vector.mnemonic {
+			t.Fatalf("mnemonic=%s err=%v", mnemonic, err)
+		}
+		seed, err := english.NewSeedWithErrorChecking(mnemonic, "TREZOR")
+		if err != nil || hex.EncodeToString(seed) != vector.seed {
+			t.Fatalf("seed=%x err=%v", seed, err)
+		}
+		if chinese.IsMnemonicValid(mnemonic) {
+			t.Fatal("english mnemonic should be invalid for the chinese codec")
+		}
+		if m, err := DetectMnemonic(mnemonic, chinese, english); err != nil || m != english {
+			t.Fatalf("detect err=%v", err)
+		}
+	}
+	if _, err := DetectMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", english); err != ErrInvalidMnemonic {
+		t.Fatalf("err=%v, want ErrInvalidMnemonic", err)
+	}
+}
+
+// jsonVector is an entry of testdata/test_JP_BIP39.json, which uses the format of
+// https://github.com/bip32JP/bip32JP.github.io/blob/master/test_JP_BIP39.json.
+// testdata/vectors_multilingual.json uses the same format with the language of the
+// wordlist, the mnemonics are NFC and the passphrases contain accented letters
+type jsonVector struct {
+	Language   string `json:"language,omitempty"`
+	Entropy    string `json:"entropy"`
+	Mnemonic   string `json:"mnemonic"`
+	Passphrase string `json:"passphrase"`
+	Seed       string `json:"seed"`
+	Xprv       string `json:"bip32_xprv"`
+}
+
+func TestJapaneseVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/test_JP_BIP39.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var japaneseVectors []jsonVector
+	if err := json.Unmarshal(data, &japaneseVectors); err != nil || len(japaneseVectors) != 24 {
+		t.Fatalf("vectors=%d err=%v", len(japaneseVectors), err)
+	}
+	japanese := NewMnemonicCodec(wordlists.Japanese)
+	for _, vector := range japaneseVectors {
+		entropy, _ := hex.DecodeString(vector.Entropy)
+		mnemonic, err := japanese.NewMnemonic(entropy)
+		if err != nil || norm.NFC.String(mnemonic) != norm.NFC.String(vector.Mnemonic) {
+			t.Fatalf("mnemonic=%s err=%v", mnemonic, err)
+		}
+		// 使用空格分隔及NFC形式的助记词得到相同的种子
+		for _, m := range []string{vector.Mnemonic, strings.ReplaceAll(vector.Mnemonic, IdeographicSpace, " "), norm.NFC.String(mnemonic)} {
+			got, err := japanese.EntropyFromMnemonic(m)
+			if err != nil || hex.EncodeToString(got) != vector.Entropy {
+				t.Fatalf("entropy=%x err=%v", got, err)
+			}
+			seed, err := japanese.NewSeedWithErrorChecking(m, vector.Passphrase)
+			if err != nil || hex.EncodeToString(seed) != vector.Seed {
+				t.Fatalf("seed=%x err=%v", seed, err)
+			}
+			key, err := bip32.NewMasterKeyWithCurve(seed, bip32.CurveSecp256k1)
+			if err != nil || key.String() != vector.Xprv {
+				t.Fatalf("xprv=%s err=%v", key, err)
+			}
+		}
+	}
+}
+
+func TestMultilingualVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/vectors_multilingual.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []jsonVector
+	if err := json.Unmarshal(data, &vectors); err != nil || len(vectors) == 0 {
+		t.Fatalf("vectors=%d err=%v", len(vectors), err)
+	}
+	codecs := map[string]*Mnemonic{
+		"french":              NewMnemonicCodec(wordlists.French),
+		"spanish":             NewMnemonicCodec(wordlists.Spanish),
+		"italian":             NewMnemonicCodec(wordlists.Italian),
+		"korean":              NewMnemonicCodec(wordlists.Korean),
+		"chinese_simplified":  NewMnemonicCodec(wordlists.ChineseSimplified),
+		"chinese_traditional": NewMnemonicCodec(wordlists.ChineseTraditional),
+	}
+	for _, vector := range vectors {
+		codec, ok := codecs[vector.Language]
+		if !ok {
+			t.Fatalf("unknown language %s", vector.Language)
+		}
+		entropy, _ := hex.DecodeString(vector.Entropy)
+		mnemonic, err := codec.NewMnemonic(entropy)
+		if err != nil || norm.NFC.String(mnemonic) != vector.Mnemonic {
+			t.Fatalf("%s: mnemonic=%s err=%v", vector.Language, mnemonic, err)
+		}
+		// NFC及NFKD形式的助记词和密码得到相同的种子
+		for _, m := range []string{vector.Mnemonic, norm.NFKD.String(vector.Mnemonic)} {
+			for _, p := range []string{vector.Passphrase, norm.NFKD.String(vector.Passphrase)} {
+				seed, err := codec.NewSeedWithErrorChecking(m, p)
+				if err != nil || hex.EncodeToString(seed) != vector.Seed {
+					t.Fatalf("%s: seed=%x err=%v", vector.Language, seed, err)
+				}
+				key, err := bip32.NewMasterKeyWithCurve(seed, bip32.CurveSecp256k1)
+				if err != nil || key.String() != vector.Xprv {
+					t.Fatalf("%s: xprv=%s err=%v", vector.Language, key, err)
+				}
+			}
+		}
+	}
+}
+
+func TestNormalization(t *testing.T) {
+	french := NewMnemonicCodec(wordlists.French)
+	entropy, _ := hex.DecodeString("7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f")
+	mnemonic, err := french.NewMnemonic(entropy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 单词表为NFKD形式，输入NFC形式的单词也可以识别
+	composed := norm.NFC.String(mnemonic)
+	if !french.IsMnemonicValid(composed) {
+		t.Fatal("composed mnemonic should be valid")
+	}
+	if got, err := french.EntropyFromMnemonic(composed); err != nil || !bytes.Equal(got, entropy) {
+		t.Fatalf("entropy=%x err=%v", got, err)
+	}
+	if !bytes.Equal(NewSeed(composed, "café"), NewSeed(mnemonic, norm.NFKD.String("café"))) {
+		t.Fatal("seed should not depend on the normalization form")
+	}
+}
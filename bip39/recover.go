@@ -0,0 +1,218 @@
+package bip39
+
+import (
+	"crypto/sha256"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// UnknownWord marks a word of the mnemonic that is unknown to the operator.
+const UnknownWord = "?"
+
+// uniquePrefixLength is the number of letters identifying a word of the
+// latin wordlists.
+const uniquePrefixLength = 4
+
+// SuggestWords returns at most max words of the list nearest to the given
+// word: the words sharing its 4-letter prefix first, then the others by edit
+// distance.
+func (m *Mnemonic) SuggestWords(word string, max int) []string {
+	target := []rune(norm.NFKD.String(word))
+	type suggestion struct {
+		index    int
+		prefix   bool
+		distance int
+	}
+	suggestions := make([]suggestion, 0, len(m.wordList))
+	for i, w := range m.wordList {
+		r := []rune(norm.NFKD.String(w))
+		suggestions = append(suggestions, suggestion{
+			index:    i,
+			prefix:   len(target) >= uniquePrefixLength && len(r) >= uniquePrefixLength && string(r[:uniquePrefixLength]) == string(target[:uniquePrefixLength]),
+			distance: editDistance(target, r),
+		})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].prefix != suggestions[j].prefix {
+			return suggestions[i].prefix
+		}
+		return suggestions[i].distance < suggestions[j].distance
+	})
+	if max > len(suggestions) {
+		max = len(suggestions)
+	}
+	words := make([]string, 0, max)
+	for _, s := range suggestions[:max] {
+		words = append(words, m.wordList[s.index])
+	}
+	return words
+}
+
+// RecoverCandidates returns the mnemonics satisfying the checksum when one
+// word of the mnemonic is wrong:
+//   - an UnknownWord or a word not in the list is replaced by every word of
+//     the list, words nearer to the mistyped one first;
+//   - a mnemonic one word short gets every word inserted at every position;
+//   - a mnemonic of known words failing the checksum gets each word replaced
+//     by every word of the list, or moved to another position.
+//
+// ErrInvalidMnemonic is returned if more than one word is unknown or the
+// word count can not be fixed by one word.
+func (m *Mnemonic) RecoverCandidates(mnemonic string) ([]string, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	indices := make([]int, len(words))
+	unknown := -1
+	for i, w := range words {
+		index, ok := m.wordMap[w]
+		if !ok {
+			if unknown >= 0 {
+				return nil, ErrInvalidMnemonic
+			}
+			unknown = i
+			index = -1
+		}
+		indices[i] = index
+	}
+
+	c := &candidates{m: m, seen: make(map[string]bool)}
+	switch {
+	case isValidWordCount(len(words)) && unknown >= 0:
+		for _, w := range m.SuggestWords(words[unknown], len(m.wordList)) {
+			indices[unknown] = m.wordMap[norm.NFKD.String(w)]
+			c.add(indices)
+		}
+	case isValidWordCount(len(words) + 1):
+		if unknown >= 0 {
+			return nil, ErrInvalidMnemonic
+		}
+		inserted := make([]int, len(words)+1)
+		for pos := 0; pos <= len(words); pos++ {
+			copy(inserted, indices[:pos])
+			copy(inserted[pos+1:], indices[pos:])
+			for i := range m.wordList {
+				inserted[pos] = i
+				c.add(inserted)
+			}
+		}
+	case isValidWordCount(len(words)):
+		// misplaced word
+		moved := make([]int, len(words))
+		for from := range words {
+			for to := range words {
+				if from == to {
+					continue
+				}
+				moveWord(moved, indices, from, to)
+				c.add(moved)
+			}
+		}
+		// mistyped word which is in the list
+		replaced := append([]int(nil), indices...)
+		for pos := range words {
+			for i := range m.wordList {
+				replaced[pos] = i
+				c.add(replaced)
+			}
+			replaced[pos] = indices[pos]
+		}
+	default:
+		return nil, ErrInvalidMnemonic
+	}
+	return c.mnemonics, nil
+}
+
+// candidates collects the distinct mnemonics satisfying the checksum.
+type candidates struct {
+	m         *Mnemonic
+	seen      map[string]bool
+	mnemonics []string
+}
+
+func (c *candidates) add(indices []int) {
+	if !checksumValid(indices) {
+		return
+	}
+	words := make([]string, len(indices))
+	for i, index := range indices {
+		words[i] = c.m.wordList[index]
+	}
+	mnemonic := strings.Join(words, c.m.separator)
+	if !c.seen[mnemonic] {
+		c.seen[mnemonic] = true
+		c.mnemonics = append(c.mnemonics, mnemonic)
+	}
+}
+
+// moveWord copies indices into dst with the word at from moved to to.
+func moveWord(dst, indices []int, from, to int) {
+	word := indices[from]
+	rest := dst[:0]
+	for i, index := range indices {
+		if i != from {
+			rest = append(rest, index)
+		}
+	}
+	copy(dst[to+1:], rest[to:len(indices)-1])
+	dst[to] = word
+}
+
+func isValidWordCount(n int) bool {
+	return n%3 == 0 && n >= 12 && n <= 24
+}
+
+// checksumValid verifies the checksum of the word indices.
+func checksumValid(indices []int) bool {
+	n := len(indices)
+	checksumBits := n / 3
+	entropyBits := n*11 - checksumBits
+	data := make([]byte, (n*11+7)/8)
+	for i, index := range indices {
+		for b := 0; b < 11; b++ {
+			if index&(1<<uint(10-b)) != 0 {
+				bit := i*11 + b
+				data[bit/8] |= 0x80 >> uint(bit%8)
+			}
+		}
+	}
+	hash := sha256.Sum256(data[:entropyBits/8])
+	for b := 0; b < checksumBits; b++ {
+		bit := entropyBits + b
+		if (data[bit/8]>>uint(7-bit%8))&1 != (hash[0]>>uint(7-b))&1 {
+			return false
+		}
+	}
+	return true
+}
+
+// editDistance returns the Levenshtein distance of a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
@@ -0,0 +1,92 @@
+package bip39
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chain5j/keybox/bip39/wordlists"
+)
+
+const legalWinner = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSuggestWords(t *testing.T) {
+	english := NewMnemonicCodec(wordlists.English)
+	for _, tc := range []struct {
+		word string
+		want string
+	}{
+		{"sausge", "sausage"},
+		{"winnr", "winner"},
+		{"yello", "yellow"},
+		{"thanks", "thank"},
+		{"abando", "abandon"},
+	} {
+		if got := english.SuggestWords(tc.word, 3); len(got) != 3 || got[0] != tc.want {
+			t.Fatalf("word=%s suggestions=%v, want %s first", tc.word, got, tc.want)
+		}
+	}
+}
+
+func TestRecoverCandidates(t *testing.T) {
+	english := NewMnemonicCodec(wordlists.English)
+	words := strings.Fields(legalWinner)
+	for _, tc := range []struct {
+		name     string
+		mnemonic string
+	}{
+		{"unknown word", strings.Replace(legalWinner, "sausage", UnknownWord, 1)},
+		{"mistyped word", strings.Replace(legalWinner, "sausage", "sausge", 1)},
+		{"wrong word in the list", strings.Replace(legalWinner, "sausage", "salute", 1)},
+		{"missing word", strings.Join(append(append([]string(nil), words[:5]...), words[6:]...), " ")},
+		{"misplaced word", strings.Join([]string{words[0], words[1], words[2], words[3], words[5], words[4], words[6], words[7], words[8], words[9], words[10], words[11]}, " ")},
+	} {
+		candidates, err := english.RecoverCandidates(tc.mnemonic)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !contains(candidates, legalWinner) {
+			t.Fatalf("%s: %d candidates without the mnemonic", tc.name, len(candidates))
+		}
+		for _, c := range candidates {
+			if _, err := english.EntropyFromMnemonic(c); err != nil {
+				t.Fatalf("%s: candidate %s err=%v", tc.name, c, err)
+			}
+		}
+	}
+	// 输错的单词优先返回相近的候选
+	candidates, _ := english.RecoverCandidates(strings.Replace(legalWinner, "sausage", "sausge", 1))
+	if candidates[0] != legalWinner {
+		t.Fatalf("candidate=%s, want %s", candidates[0], legalWinner)
+	}
+
+	for _, mnemonic := range []string{
+		strings.Replace(strings.Replace(legalWinner, "sausage", "?", 1), "wave", "?", 1),
+		"legal winner thank",
+	} {
+		if _, err := english.RecoverCandidates(mnemonic); err != ErrInvalidMnemonic {
+			t.Fatalf("mnemonic=%s err=%v, want ErrInvalidMnemonic", mnemonic, err)
+		}
+	}
+}
+
+func TestMoveWord(t *testing.T) {
+	dst := make([]int, 5)
+	moveWord(dst, []int{0, 1, 2, 3, 4}, 1, 3)
+	if fmt.Sprint(dst) != "[0 2 3 1 4]" {
+		t.Fatalf("dst=%v", dst)
+	}
+	moveWord(dst, []int{0, 1, 2, 3, 4}, 4, 0)
+	if fmt.Sprint(dst) != "[4 0 1 2 3]" {
+		t.Fatalf("dst=%v", dst)
+	}
+}
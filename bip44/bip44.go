@@ -7,11 +7,22 @@ package bip44
 import (
 	"github.com/chain5j/keybox/bip32"
 	"github.com/chain5j/keybox/bip39"
+	"github.com/chain5j/keybox/crypto/secret"
 )
 
 const CoinTypeBTC uint32 = 0x80000000
 const Purpose uint32 = 0x8000002C
 
+// Purpose45 keybox的组织布局：m / 45' / coin_type' / org' / account' / change / address_index
+const Purpose45 uint32 = 0x8000002D
+
+// 与BIP44相同的布局，地址类型由purpose决定：m / purpose' / coin_type' / account' / change / address_index
+const (
+	Purpose49 uint32 = 0x80000031 // BIP49，P2WPKH-nested-in-P2SH地址
+	Purpose84 uint32 = 0x80000054 // BIP84，P2WPKH地址
+	Purpose86 uint32 = 0x80000056 // BIP86，P2TR地址
+)
+
 // https://github.com/satoshilabs/slips/blob/master/slip-0044.md
 // const (
 //	TypeBTC  uint32 = 0x80000000
@@ -24,11 +35,13 @@ func NewKeyFromMnemonic(mnemonic string, coin, account, chain, address uint32) (
 	if err != nil {
 		return nil, err
 	}
+	defer secret.Zero(seed)
 
 	masterKey, err := bip32.NewMasterKey(seed)
 	if err != nil {
 		return nil, err
 	}
+	defer masterKey.Zero()
 
 	return NewKeyFromMasterKey(masterKey, coin, account, chain, address)
 }
@@ -41,66 +54,25 @@ func NewKeyFromMnemonic(mnemonic string, coin, account, chain, address uint32) (
 // address_index  地址索引
 
 func NewKeyFromMasterKey(masterKey *bip32.Key, coin_type, account, change, address_index uint32) (*bip32.Key, error) {
-	child, err := masterKey.NewChildKey(Purpose)
-	if err != nil {
-		return nil, err
-	}
-
-	child, err = child.NewChildKey(coin_type)
-	if err != nil {
-		return nil, err
-	}
-
-	child, err = child.NewChildKey(account)
-	if err != nil {
-		return nil, err
-	}
-
-	child, err = child.NewChildKey(change)
-	if err != nil {
-		return nil, err
-	}
-
-	child, err = child.NewChildKey(address_index)
-	if err != nil {
-		return nil, err
-	}
-
-	return child, nil
+	return deriveKey(masterKey, Purpose, coin_type, account, change, address_index)
 }
 
 func NewKeyFromMasterKeyWithOrg(masterKey *bip32.Key, purpose, coinType, org, account, change, addressIndex uint32) (*bip32.Key, error) {
-	child, err := masterKey.NewChildKey(purpose)
-	if err != nil {
-		return nil, err
-	}
-
-	child, err = child.NewChildKey(coinType)
-	if err != nil {
-		return nil, err
-	}
-
-	if purpose != Purpose {
-		child, err = child.NewChildKey(org)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	child, err = child.NewChildKey(account)
-	if err != nil {
-		return nil, err
-	}
-
-	child, err = child.NewChildKey(change)
-	if err != nil {
-		return nil, err
+	if purpose == Purpose45 {
+		return deriveKey(masterKey, purpose, coinType, org, account, change, addressIndex)
 	}
+	return deriveKey(masterKey, purpose, coinType, account, change, addressIndex)
+}
 
-	child, err = child.NewChildKey(addressIndex)
-	if err != nil {
-		return nil, err
+// NewAccountKeyFromMasterKeyWithOrg 派生账户层级的私钥，其扩展公钥可用于只读钱包
+func NewAccountKeyFromMasterKeyWithOrg(masterKey *bip32.Key, purpose, coinType, org, account uint32) (*bip32.Key, error) {
+	if purpose == Purpose45 {
+		return deriveKey(masterKey, purpose, coinType, org, account)
 	}
+	return deriveKey(masterKey, purpose, coinType, account)
+}
 
-	return child, nil
+// 按路径逐级派生，中间节点在派生出下一级后清零
+func deriveKey(masterKey *bip32.Key, path ...uint32) (*bip32.Key, error) {
+	return masterKey.DerivePath(bip32.DerivationPath(path))
 }
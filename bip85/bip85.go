@@ -0,0 +1,234 @@
+// https://github.com/bitcoin/bips/blob/master/bip-0085.mediawiki
+package bip85
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip39"
+	"github.com/chain5j/keybox/bip39/wordlists"
+	"github.com/chain5j/keybox/crypto/secret"
+)
+
+// Purpose is the hardened purpose of the BIP85 derivation path.
+const Purpose uint32 = bip32.FirstHardenedChild + 83696968
+
+// Applications of BIP85.
+const (
+	AppBIP39       uint32 = 39
+	AppHDSeedWIF   uint32 = 2
+	AppXPRV        uint32 = 32
+	AppHex         uint32 = 128169
+	AppPasswordB64 uint32 = 707764
+	AppPasswordB85 uint32 = 707785
+)
+
+const (
+	entropyHmacKey      = "bip-entropy-from-k"
+	wifVersion     byte = 0x80
+	wifCompressed  byte = 0x01
+	maxHexBytes         = 64
+)
+
+// Language is the BIP85 code of a BIP39 wordlist.
+type Language uint32
+
+// Languages of BIP85, Czech is not supported as there is no wordlist for it.
+const (
+	English            Language = 0
+	Japanese           Language = 1
+	Korean             Language = 2
+	Spanish            Language = 3
+	ChineseSimplified  Language = 4
+	ChineseTraditional Language = 5
+	French             Language = 6
+	Italian            Language = 7
+	Czech              Language = 8
+)
+
+var (
+	ErrPrivateKeyRequired = errors.New("BIP85 requires an extended private key")
+	ErrUnknownLanguage    = errors.New("Unknown BIP85 language")
+	ErrInvalidWordCount   = errors.New("BIP85 mnemonic word count must be 12, 18 or 24")
+	ErrInvalidLength      = errors.New("BIP85 length is out of range")
+	ErrInvalidKey         = errors.New("BIP85 derived an invalid private key")
+)
+
+// WordList returns the BIP39 wordlist of the language.
+func (l Language) WordList() ([]string, error) {
+	switch l {
+	case English:
+		return wordlists.English, nil
+	case Japanese:
+		return wordlists.Japanese, nil
+	case Korean:
+		return wordlists.Korean, nil
+	case Spanish:
+		return wordlists.Spanish, nil
+	case ChineseSimplified:
+		return wordlists.ChineseSimplified, nil
+	case ChineseTraditional:
+		return wordlists.ChineseTraditional, nil
+	case French:
+		return wordlists.French, nil
+	case Italian:
+		return wordlists.Italian, nil
+	}
+	return nil, ErrUnknownLanguage
+}
+
+// DeriveEntropy derives the 64 bytes entropy of the path, such as
+// m/83696968'/39'/0'/12'/0'.
+func DeriveEntropy(master *bip32.Key, path bip32.DerivationPath) ([]byte, error) {
+	if nil == master || !master.IsPrivate {
+		return nil, ErrPrivateKeyRequired
+	}
+	key, err := master.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	mac := hmac.New(sha512.New, []byte(entropyHmacKey))
+	mac.Write(key.Key)
+	return mac.Sum(nil), nil
+}
+
+// Path returns the hardened path m/83696968'/{app}'/{indexes}'...
+func Path(app uint32, indexes ...uint32) bip32.DerivationPath {
+	path := bip32.DerivationPath{Purpose, bip32.FirstHardenedChild + app}
+	for _, index := range indexes {
+		path = append(path, bip32.FirstHardenedChild+index)
+	}
+	return path
+}
+
+// NewMnemonic derives a BIP39 mnemonic of 12, 18 or 24 words,
+// m/83696968'/39'/{language}'/{words}'/{index}'.
+func NewMnemonic(master *bip32.Key, language Language, words int, index uint32) (string, error) {
+	list, err := language.WordList()
+	if err != nil {
+		return "", err
+	}
+	if words != 12 && words != 18 && words != 24 {
+		return "", ErrInvalidWordCount
+	}
+	entropy, err := DeriveEntropy(master, Path(AppBIP39, uint32(language), uint32(words), index))
+	if err != nil {
+		return "", err
+	}
+	defer secret.Zero(entropy)
+	return bip39.NewMnemonicCodec(list).NewMnemonic(entropy[:words*4/3])
+}
+
+// NewWIF derives a compressed mainnet WIF private key, m/83696968'/2'/{index}'.
+func NewWIF(master *bip32.Key, index uint32) (string, error) {
+	entropy, err := DeriveEntropy(master, Path(AppHDSeedWIF, index))
+	if err != nil {
+		return "", err
+	}
+	defer secret.Zero(entropy)
+	if err := validatePrivateKey(entropy[:32]); err != nil {
+		return "", err
+	}
+	payload := append(append(make([]byte, 0, 33), entropy[:32]...), wifCompressed)
+	defer secret.Zero(payload)
+	return base58.CheckEncode(payload, wifVersion), nil
+}
+
+// NewXPRV derives a secp256k1 master extended private key, m/83696968'/32'/{index}'.
+// The first 32 bytes of the entropy are the chain code, the others are the key.
+func NewXPRV(master *bip32.Key, index uint32) (*bip32.Key, error) {
+	entropy, err := DeriveEntropy(master, Path(AppXPRV, index))
+	if err != nil {
+		return nil, err
+	}
+	defer secret.Zero(entropy)
+	if err := validatePrivateKey(entropy[32:]); err != nil {
+		return nil, err
+	}
+	return &bip32.Key{
+		Version:     bip32.PrivateWalletVersion,
+		ChainCode:   append([]byte(nil), entropy[:32]...),
+		Key:         append([]byte(nil), entropy[32:]...),
+		Depth:       0x0,
+		ChildNumber: []byte{0x00, 0x00, 0x00, 0x00},
+		FingerPrint: []byte{0x00, 0x00, 0x00, 0x00},
+		IsPrivate:   true,
+		Curve:       bip32.CurveSecp256k1,
+	}, nil
+}
+
+// NewHex derives 16 to 64 bytes of entropy, m/83696968'/128169'/{numBytes}'/{index}'.
+func NewHex(master *bip32.Key, numBytes int, index uint32) ([]byte, error) {
+	if numBytes < 16 || numBytes > maxHexBytes {
+		return nil, ErrInvalidLength
+	}
+	entropy, err := DeriveEntropy(master, Path(AppHex, uint32(numBytes), index))
+	if err != nil {
+		return nil, err
+	}
+	defer secret.Zero(entropy)
+	return append([]byte(nil), entropy[:numBytes]...), nil
+}
+
+// NewBase64Password derives a base64 password of 20 to 86 characters,
+// m/83696968'/707764'/{length}'/{index}'.
+func NewBase64Password(master *bip32.Key, length int, index uint32) (string, error) {
+	if length < 20 || length > 86 {
+		return "", ErrInvalidLength
+	}
+	entropy, err := DeriveEntropy(master, Path(AppPasswordB64, uint32(length), index))
+	if err != nil {
+		return "", err
+	}
+	defer secret.Zero(entropy)
+	return base64.StdEncoding.EncodeToString(entropy)[:length], nil
+}
+
+// NewBase85Password derives a base85 password of 10 to 80 characters,
+// m/83696968'/707785'/{length}'/{index}'.
+func NewBase85Password(master *bip32.Key, length int, index uint32) (string, error) {
+	if length < 10 || length > 80 {
+		return "", ErrInvalidLength
+	}
+	entropy, err := DeriveEntropy(master, Path(AppPasswordB85, uint32(length), index))
+	if err != nil {
+		return "", err
+	}
+	defer secret.Zero(entropy)
+	return base85Encode(entropy)[:length], nil
+}
+
+// base85Alphabet is the alphabet of RFC 1924, which python's base64.b85encode uses.
+const base85Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+
+// base85Encode encodes data whose length is a multiple of 4.
+func base85Encode(data []byte) string {
+	out := make([]byte, 0, len(data)/4*5)
+	for i := 0; i+4 <= len(data); i += 4 {
+		v := uint32(data[i])<<24 | uint32(data[i+1])<<16 | uint32(data[i+2])<<8 | uint32(data[i+3])
+		var chunk [5]byte
+		for j := 4; j >= 0; j-- {
+			chunk[j] = base85Alphabet[v%85]
+			v /= 85
+		}
+		out = append(out, chunk[:]...)
+	}
+	return string(out)
+}
+
+// validatePrivateKey checks the key is in [1, n-1] of secp256k1.
+func validatePrivateKey(key []byte) error {
+	k := new(big.Int).SetBytes(key)
+	defer k.SetInt64(0)
+	if k.Sign() == 0 || k.Cmp(btcec.S256().N) >= 0 {
+		return ErrInvalidKey
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package bip85
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip39"
+)
+
+// test vectors from https://github.com/bitcoin/bips/blob/master/bip-0085.mediawiki
+const masterKey = "xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb"
+
+func master(t *testing.T) *bip32.Key {
+	key, err := bip32.B58DeserializeWithCurve(masterKey, bip32.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestDeriveEntropy(t *testing.T) {
+	for _, v := range []struct {
+		path       string
+		derivedKey string
+		entropy    string
+	}{
+		{
+			path:       "m/83696968'/0'/0'",
+			derivedKey: "cca20ccb0e9a90feb0912870c3323b24874b0ca3d8018c4b96d0b97c0e82ded0",
+			entropy:    "efecfbccffea313214232d29e71563d941229afb4338c21f9517c41aaa0d16f00b83d2a09ef747e7a64e8e2bd5a14869e693da66ce94ac2da570ab7ee48618f7",
+		},
+		{
+			path:       "m/83696968'/0'/1'",
+			derivedKey: "503776919131758bb7de7beb6c0ae24894f4ec042c26032890c29359216e21ba",
+			entropy:    "70c6e3e8ebee8dc4c0dbba66076819bb8c09672527c4277ca8729532ad711872218f826919f6b67218adde99018a6df9095ab2b58d803b5b93ec9802085a690e",
+		},
+	} {
+		path, err := bip32.ParseDerivationPath(v.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := master(t).DerivePath(path)
+		if err != nil || hex.EncodeToString(key.Key) != v.derivedKey {
+			t.Fatalf("%s: derivedKey=%x err=%v", v.path, key.Key, err)
+		}
+		if path.String() != Path(0, path[2]-bip32.FirstHardenedChild).String() {
+			t.Fatalf("path=%s", Path(0, path[2]-bip32.FirstHardenedChild))
+		}
+		entropy, err := DeriveEntropy(master(t), path)
+		if err != nil || hex.EncodeToString(entropy) != v.entropy {
+			t.Fatalf("%s: entropy=%x err=%v", v.path, entropy, err)
+		}
+	}
+	if _, err := DeriveEntropy(master(t).PublicKey(), Path(0, 0)); err != ErrPrivateKeyRequired {
+		t.Fatalf("err=%v, want ErrPrivateKeyRequired", err)
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, v := range []struct {
+		words    int
+		mnemonic string
+	}{
+		{12, "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose"},
+		{18, "near account window bike charge season chef number sketch tomorrow excuse sniff circle vital hockey outdoor supply token"},
+		{24, "puppy ocean match cereal symbol another shed magic wrap hammer bulb intact gadget divorce twin tonight reason outdoor destroy simple truth cigar social volcano"},
+	} {
+		mnemonic, err := NewMnemonic(master(t), English, v.words, 0)
+		if err != nil || mnemonic != v.mnemonic {
+			t.Fatalf("mnemonic=%s err=%v", mnemonic, err)
+		}
+	}
+	// 其他语言使用相同的熵
+	english, _ := NewMnemonic(master(t), English, 12, 1)
+	for _, language := range []Language{Japanese, Korean, Spanish, ChineseSimplified, ChineseTraditional, French, Italian} {
+		mnemonic, err := NewMnemonic(master(t), language, 12, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list, _ := language.WordList()
+		if _, err := bip39.NewMnemonicCodec(list).EntropyFromMnemonic(mnemonic); err != nil {
+			t.Fatalf("language=%d mnemonic=%s err=%v", language, mnemonic, err)
+		}
+		if mnemonic == english {
+			t.Fatalf("language=%d should derive a different path", language)
+		}
+	}
+	if _, err := NewMnemonic(master(t), Czech, 12, 0); !errors.Is(err, ErrUnknownLanguage) {
+		t.Fatalf("err=%v, want ErrUnknownLanguage", err)
+	}
+	if _, err := NewMnemonic(master(t), English, 15, 0); !errors.Is(err, ErrInvalidWordCount) {
+		t.Fatalf("err=%v, want ErrInvalidWordCount", err)
+	}
+}
+
+func TestApplications(t *testing.T) {
+	wif, err := NewWIF(master(t), 0)
+	if err != nil || wif != "Kzyv4uF39d4Jrw2W7UryTHwZr1zQVNk4dAFyqE6BuMrMh1Za7uhp" {
+		t.Fatalf("wif=%s err=%v", wif, err)
+	}
+	xprv, err := NewXPRV(master(t), 0)
+	if err != nil || xprv.String() != "xprv9s21ZrQH143K2srSbCSg4m4kLvPMzcWydgmKEnMmoZUurYuBuYG46c6P71UGXMzmriLzCCBvKQWBUv3vPB3m1SATMhp3uEjXHJ42jFg7myX" {
+		t.Fatalf("xprv=%s err=%v", xprv, err)
+	}
+	entropy, err := NewHex(master(t), 64, 0)
+	if err != nil || hex.EncodeToString(entropy) != "492db4698cf3b73a5a24998aa3e9d7fa96275d85724a91e71aa2d645442f878555d078fd1f1f67e368976f04137b1f7a0d19232136ca50c44614af72b5582a5c" {
+		t.Fatalf("hex=%x err=%v", entropy, err)
+	}
+	pwd, err := NewBase64Password(master(t), 21, 0)
+	if err != nil || pwd != "dKLoepugzdVJvdL56ogNV" {
+		t.Fatalf("pwd=%s err=%v", pwd, err)
+	}
+	pwd, err = NewBase85Password(master(t), 12, 0)
+	if err != nil || pwd != "_s`{TW89)i4`" {
+		t.Fatalf("pwd=%s err=%v", pwd, err)
+	}
+
+	for _, err := range []error{
+		func() error { _, err := NewHex(master(t), 15, 0); return err }(),
+		func() error { _, err := NewBase64Password(master(t), 87, 0); return err }(),
+		func() error { _, err := NewBase85Password(master(t), 9, 0); return err }(),
+	} {
+		if err != ErrInvalidLength {
+			t.Fatalf("err=%v, want ErrInvalidLength", err)
+		}
+	}
+}
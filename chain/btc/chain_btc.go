@@ -5,14 +5,16 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/base58"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
-	"github.com/chain5j/chain5j-pkg/crypto/signature/secp256k1"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/chain5j/keybox"
 	"github.com/chain5j/keybox/algorithm/s256"
 	"github.com/chain5j/keybox/chain"
 	"github.com/chain5j/keybox/crypto/address"
+	"github.com/chain5j/keybox/crypto/secret"
 )
 
 type Chain struct {
@@ -22,6 +24,23 @@ type Chain struct {
 	netId       byte
 }
 
+func init() {
+	keybox.MustRegisterChain(&keybox.ChainRegistration{
+		Info:     keybox.ChainInfoBTC,
+		Networks: []chain.NetworkType{chain.MainNet, chain.TestNet, chain.DevNet},
+		Factory: func(networkType chain.NetworkType) keybox.ChainAPI {
+			return NewChain(networkType)
+		},
+	})
+	keybox.MustRegisterChain(&keybox.ChainRegistration{
+		Info:     keybox.ChainInfoOMNI,
+		Networks: []chain.NetworkType{chain.MainNet, chain.TestNet, chain.DevNet},
+		Factory: func(networkType chain.NetworkType) keybox.ChainAPI {
+			return NewOmniChain(networkType)
+		},
+	})
+}
+
 func NewChain(networkType chain.NetworkType) *Chain {
 	netId := byte(0x80)
 	switch networkType {
@@ -39,11 +58,23 @@ func NewChain(networkType chain.NetworkType) *Chain {
 	}
 }
 
+// NewOmniChain OMNI链，使用BTC的密钥、地址及交易，账户路径使用OMNI的币种类型
+func NewOmniChain(networkType chain.NetworkType) *Chain {
+	c := NewChain(networkType)
+	c.chainInfo = keybox.ChainInfoOMNI
+	return c
+}
+
 // 获取链信息
 func (c *Chain) ChainInfo() *keybox.ChainInfo {
 	return c.chainInfo
 }
 
+// 获取网络类型
+func (c *Chain) NetworkType() chain.NetworkType {
+	return c.networkType
+}
+
 // 比特币中的netId就是PrivateKeyID[wifPrvkey]
 func (c *Chain) ExportPrivateKey(priKey []byte, isCompressPubKey bool) (string, error) {
 	privateKey, _ := btcec.PrivKeyFromBytes(priKey)
@@ -75,14 +106,75 @@ func (c *Chain) GetAddressFromPubKey(pubKey []byte) (string, error) {
 	return address.BTCAddress(netType, pubKey), nil
 }
 
+// 从公钥获取压缩公钥的P2PKH地址，用于Electrum标准种子的账户
+func (c *Chain) GetAddressFromCompressedPubKey(pubKey []byte) (string, error) {
+	if pubKey == nil || len(pubKey) == 0 {
+		return "", fmt.Errorf("pubKey is empty")
+	}
+	key, err := btcec.ParsePubKey(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return c.GetAddressFromPubKey(key.SerializeCompressed())
+}
+
+// 按purpose从公钥获取地址，44'及其他purpose为P2PKH地址
+// 49'为P2SH-P2WPKH地址，84'为P2WPKH地址，86'为P2TR地址，均使用压缩公钥
+func (c *Chain) GetAddressFromPubKeyWithPurpose(pubKey []byte, purpose uint32) (string, error) {
+	if purpose != keybox.Purpose49 && purpose != keybox.Purpose84 && purpose != keybox.Purpose86 {
+		return c.GetAddressFromPubKey(pubKey)
+	}
+	if pubKey == nil || len(pubKey) == 0 {
+		return "", fmt.Errorf("pubKey is empty")
+	}
+	params, err := ParseNetworkToConf(string(c.networkType))
+	if err != nil {
+		return "", err
+	}
+	key, err := btcec.ParsePubKey(pubKey)
+	if err != nil {
+		return "", err
+	}
+	var addr btcutil.Address
+	switch purpose {
+	case keybox.Purpose49:
+		witnessProg, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.SerializeCompressed()), params)
+		if err != nil {
+			return "", err
+		}
+		script, err := txscript.PayToAddrScript(witnessProg)
+		if err != nil {
+			return "", err
+		}
+		addr, err = btcutil.NewAddressScriptHash(script, params)
+		if err != nil {
+			return "", err
+		}
+	case keybox.Purpose84:
+		addr, err = btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.SerializeCompressed()), params)
+	case keybox.Purpose86:
+		outputKey := txscript.ComputeTaprootKeyNoScript(key)
+		addr, err = btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), params)
+	}
+	if err != nil {
+		return "", err
+	}
+	return addr.EncodeAddress(), nil
+}
+
 // 签名直接返回签名的string
 func (c *Chain) SignToStr(priKey []byte, rawTxBytes []byte) (string, error) {
-	wifPrvkey, err := c.ExportPrivateKey(priKey, false)
-	fmt.Println("wifPrvkey", wifPrvkey)
+	privateKey, _ := btcec.PrivKeyFromBytes(priKey)
+	defer privateKey.Zero()
+	parseNetworkToConf, err := ParseNetworkToConf(string(c.networkType))
 	if err != nil {
 		return "", err
 	}
-	signedRawTx, err := c.SignRawTx(hex.EncodeToString(rawTxBytes), wifPrvkey)
+	wif, err := btcutil.NewWIF(privateKey, parseNetworkToConf, false)
+	if err != nil {
+		return "", err
+	}
+	signedRawTx, err := c.SignRawTxWithKey(hex.EncodeToString(rawTxBytes), wif)
 	if err != nil {
 		return "", err
 	}
@@ -97,15 +189,17 @@ func (c *Chain) ExportPrivateKey2(priKey []byte, isCompressPubKey bool) (string,
 	if isCompressPubKey {
 		encodeLen++
 	}
-	cryptoS256 := secp256k1.Secp251k1{}
-	privateKey := cryptoS256.ToECDSA(priKey)
-	if privateKey == nil {
+	if len(priKey) == 0 {
 		return "", fmt.Errorf("private key is empty")
 	}
+	privateKey, _ := btcec.PrivKeyFromBytes(priKey)
+	defer privateKey.Zero()
+	keyBytes := privateKey.Serialize()
+	defer secret.Zero(keyBytes)
 
 	p := make([]byte, 0, encodeLen)
 	p = append(p, c.netId)
-	p = paddedAppend(btcec.PrivKeyBytesLen, p, privateKey.D.Bytes())
+	p = paddedAppend(btcec.PrivKeyBytesLen, p, keyBytes)
 	if isCompressPubKey {
 		p = append(p, compressMagic)
 	}
@@ -5,11 +5,21 @@
 package btc
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/chain5j/chain5j-pkg/util/hexutil"
+	"github.com/chain5j/keybox"
+	"github.com/chain5j/keybox/bip32"
 	"github.com/chain5j/keybox/crypto/address"
 )
 
@@ -95,3 +105,152 @@ func TestChain_SignToStr(t *testing.T) {
 	}
 	fmt.Println("signToStr", signToStr)
 }
+
+func TestChain_SignToStrWitness(t *testing.T) {
+	priKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	privateKey, _ := btcec.PrivKeyFromBytes(priKey)
+	chain := NewChain("testnet")
+	conf, err := ParseNetworkToConf("testnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一私钥的P2SH-P2WPKH、P2WPKH、P2TR地址及压缩公钥的P2PKH地址（Electrum标准种子）各有一个UTXO
+	tx := wire.NewMsgTx(2)
+	var inputs []RawTxInput
+	for i, purpose := range []uint32{keybox.Purpose49, keybox.Purpose84, keybox.Purpose86, 0} {
+		var addrStr string
+		if purpose == 0 {
+			addrStr, err = chain.GetAddressFromCompressedPubKey(privateKey.PubKey().SerializeUncompressed())
+		} else {
+			addrStr, err = chain.GetAddressFromPubKeyWithPurpose(privateKey.PubKey().SerializeUncompressed(), purpose)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := btcutil.DecodeAddress(addrStr, conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txid := chainhash.DoubleHashH([]byte{byte(i)})
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&txid, uint32(i)), nil, nil))
+		inputs = append(inputs, RawTxInput{
+			Txid:         txid.String(),
+			Vout:         uint32(i),
+			ScriptPubKey: hex.EncodeToString(script),
+			Amount:       0.001,
+		})
+	}
+	toAddr, _ := btcutil.DecodeAddress("myxu5JjH9zU5L2GEhaqiCUUjKm71SZ1hzp", conf)
+	toScript, _ := txscript.PayToAddrScript(toAddr)
+	tx.AddTxOut(wire.NewTxOut(250000, toScript))
+
+	signRaw := func(inputs []RawTxInput) (string, error) {
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		msg, err := json.Marshal(&SignRawTransactionCmd{RawTx: hex.EncodeToString(buf.Bytes()), Inputs: &inputs})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return chain.SignToStr(priKey, msg)
+	}
+	signedRawTx, err := signRaw(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signedBytes, _ := hex.DecodeString(signedRawTx)
+	signedTx := wire.NewMsgTx(2)
+	if err := signedTx.Deserialize(bytes.NewReader(signedBytes)); err != nil {
+		t.Fatal(err)
+	}
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for i, input := range inputs {
+		script, _ := hex.DecodeString(input.ScriptPubKey)
+		fetcher.AddPrevOut(signedTx.TxIn[i].PreviousOutPoint, wire.NewTxOut(100000, script))
+	}
+	sigHashes := txscript.NewTxSigHashes(signedTx, fetcher)
+	for i, txIn := range signedTx.TxIn {
+		if i < 3 && len(txIn.Witness) == 0 {
+			t.Fatalf("input %d has no witness", i)
+		}
+		prevOut := fetcher.FetchPrevOutput(txIn.PreviousOutPoint)
+		vm, err := txscript.NewEngine(prevOut.PkScript, signedTx, i, txscript.StandardVerifyFlags, nil, sigHashes, prevOut.Value, fetcher)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.Execute(); err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+	}
+
+	// 缺少金额时无法计算隔离见证签名哈希，应明确报错
+	inputs[1].Amount = 0
+	if _, err := signRaw(inputs); err == nil {
+		t.Fatal("expected error for witness input without amount")
+	}
+}
+
+func TestWallet_SignTxPolicy(t *testing.T) {
+	wallet, err := keybox.NewWalletWithStorage(keybox.NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := NewChain("testnet")
+	conf, _ := ParseNetworkToConf("testnet")
+	addr, _, err := wallet.CreateAccount(keybox.Purpose44, 0, 0, bip32.ParseHDNum(0), 0, 0, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toAddr := "myxu5JjH9zU5L2GEhaqiCUUjKm71SZ1hzp"
+	engine, err := keybox.NewPolicyEngine(&keybox.Policy{Accounts: map[string]*keybox.AccountPolicy{
+		addr: {AllowTo: []string{toAddr}, MaxTxValue: "300000"},
+	}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetPolicy(engine)
+
+	fromAddr, _ := btcutil.DecodeAddress(addr, conf)
+	fromScript, _ := txscript.PayToAddrScript(fromAddr)
+	buildTx := func(to string, value int64) []byte {
+		tx := wire.NewMsgTx(2)
+		txid := chainhash.DoubleHashH([]byte(to))
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&txid, 0), nil, nil))
+		toAddr, _ := btcutil.DecodeAddress(to, conf)
+		toScript, _ := txscript.PayToAddrScript(toAddr)
+		tx.AddTxOut(wire.NewTxOut(value, toScript))
+		// 找零到签名地址，不计入策略的金额
+		tx.AddTxOut(wire.NewTxOut(500000, fromScript))
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		inputs := []RawTxInput{{Txid: txid.String(), ScriptPubKey: hex.EncodeToString(fromScript), Amount: 0.01}}
+		msg, err := json.Marshal(&SignRawTransactionCmd{RawTx: hex.EncodeToString(buf.Bytes()), Inputs: &inputs})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+
+	signedRawTx, err := wallet.SignTx(addr, "", buildTx(toAddr, 250000), chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hex.DecodeString(signedRawTx); err != nil || len(signedRawTx) == 0 {
+		t.Fatalf("signedRawTx=%s err=%v", signedRawTx, err)
+	}
+	if _, err := wallet.SignTx(addr, "", buildTx(toAddr, 300001), chain); !errors.Is(err, keybox.ErrPolicyTxLimit) {
+		t.Fatalf("err=%v, want ErrPolicyTxLimit", err)
+	}
+	if _, err := wallet.SignTx(addr, "", buildTx("mvmSUX991W3GrhYzjQX84qWduQDE8EBnfW", 1000), chain); !errors.Is(err, keybox.ErrPolicyDestination) {
+		t.Fatalf("err=%v, want ErrPolicyDestination", err)
+	}
+}
@@ -5,6 +5,7 @@
 package btc
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -12,6 +13,10 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/chain5j/chain5j-pkg/util/hexutil"
+	"github.com/chain5j/keybox"
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip39"
+	"github.com/chain5j/keybox/chain"
 )
 
 func TestChain_GetAddressFromPubKey(t *testing.T) {
@@ -130,3 +135,142 @@ func TestWifToAddr(t *testing.T) {
 	}
 	println("addr", pubKeyAddress.EncodeAddress())
 }
+
+// BIP49、BIP84、BIP86的测试向量
+func TestChain_GetAddressFromPubKeyWithPurpose(t *testing.T) {
+	seed := bip39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	mKey, err := bip32.NewMasterKeyWithCurve(seed, bip32.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := NewChain("mainnet")
+	for _, tc := range []struct {
+		purpose uint32
+		address string
+	}{
+		{keybox.Purpose49, "37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf"},
+		{keybox.Purpose84, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"},
+		{keybox.Purpose86, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+	} {
+		key, err := mKey.DerivePath(bip32.DerivationPath{tc.purpose, keybox.TypeBTC, bip32.FirstHardenedChild, 0, 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey, err := chain.GetPubKeyFromPriKey(key.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := chain.GetAddressFromPubKeyWithPurpose(pubKey, tc.purpose)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != tc.address {
+			t.Fatalf("purpose %d address got %s, want %s", tc.purpose-bip32.FirstHardenedChild, addr, tc.address)
+		}
+	}
+}
+
+func TestChain_Registered(t *testing.T) {
+	reg, err := keybox.LookupChainByCoinType(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.Info != keybox.ChainInfoBTC {
+		t.Fatal("coinType 0 should be BTC")
+	}
+	api, err := keybox.NewChainAPI("btc", chain.TestNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := api.(*Chain); !ok || c.NetworkType() != chain.TestNet {
+		t.Fatal("registered factory should create the BTC chain")
+	}
+}
+
+func TestOmniChain_Registered(t *testing.T) {
+	api, err := keybox.NewChainAPI("omni", chain.MainNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.ChainInfo().ChainType != keybox.TypeOMNI {
+		t.Fatal("OMNI chain should use the OMNI coin type")
+	}
+	pubKey, err := api.GetPubKeyFromPriKey(bytes.Repeat([]byte{0x01}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	omniAddr, err := api.GetAddressFromPubKey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	btcAddr, err := NewChain(chain.MainNet).GetAddressFromPubKey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if omniAddr != btcAddr {
+		t.Fatal("OMNI address should be the BTC address")
+	}
+}
+
+func TestWallet_CreateElectrumAccount(t *testing.T) {
+	// Electrum测试中的隔离见证种子及其接收、找零地址
+	seed := "bitter grass shiver impose acquire brush forget axis eager alone wine silver"
+	wallet, err := keybox.ImportSeedWithStorage(keybox.NewMemoryStorage(), "w1", "123456", seed, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wallet.SeedType != keybox.SeedType_ElectrumSegwit {
+		t.Fatalf("seedType=%s", wallet.SeedType)
+	}
+	api := NewChain(chain.MainNet)
+	for _, tc := range []struct {
+		change uint32
+		want   string
+	}{
+		{0, "bc1q3g5tmkmlvxryhh843v4dz026avatc0zzr6h3af"},
+		{1, "bc1qdy94n2q5qcp0kg7v9yzwe6wvfkhnvyzje7nx2p"},
+	} {
+		addr, keyPath, err := wallet.CreateElectrumAccount(tc.change, 0, api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != tc.want {
+			t.Fatalf("change %d addr=%s, want %s", tc.change, addr, tc.want)
+		}
+		// 重新派生校验地址后可以导出私钥
+		if _, err := wallet.ExportRawKey(addr, keyPath, api); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWallet_CreateElectrumStandardAccount(t *testing.T) {
+	// Electrum测试中的标准种子及其接收、找零地址，P2PKH地址使用压缩公钥
+	seed := "cycle rocket west magnet parrot shuffle foot correct salt library feed song"
+	wallet, err := keybox.ImportSeedWithStorage(keybox.NewMemoryStorage(), "w1", "123456", seed, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wallet.SeedType != keybox.SeedType_ElectrumStandard {
+		t.Fatalf("seedType=%s", wallet.SeedType)
+	}
+	api := NewChain(chain.MainNet)
+	for _, tc := range []struct {
+		change uint32
+		want   string
+	}{
+		{0, "1NNkttn1YvVGdqBW4PR6zvc3Zx3H5owKRf"},
+		{1, "1KSezYMhAJMWqFbVFB2JshYg69UpmEXR4D"},
+	} {
+		addr, keyPath, err := wallet.CreateElectrumAccount(tc.change, 0, api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != tc.want {
+			t.Fatalf("change %d addr=%s, want %s", tc.change, addr, tc.want)
+		}
+		if _, err := wallet.ExportRawKey(addr, keyPath, api); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -5,30 +5,50 @@
 package btc
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/chain5j/keybox"
 	log "github.com/chain5j/log15"
 )
 
 func (c *Chain) SignRawTx(rawTx, privateKeyWif string) (signedRawTx string, err error) {
+	wif, err := btcutil.DecodeWIF(privateKeyWif)
+	if err != nil {
+		return
+	}
+	defer wif.PrivKey.Zero()
+	return c.SignRawTxWithKey(rawTx, wif)
+}
+
+// SignRawTxWithKey 使用已解码的私钥进行签名，私钥不会被转换为字符串
+func (c *Chain) SignRawTxWithKey(rawTx string, wif *btcutil.WIF) (signedRawTx string, err error) {
 	msg := new(CustomHexMsg)
 	err = msg.UnmarshalJSON(rawTx)
 	if err != nil {
 		return
 	}
-	msg.PrivKeys = &[]string{privateKeyWif}
 	if msg.Flags == nil {
 		var flagALL = "ALL"
 		msg.Flags = &flagALL
 	}
 	signCmd := &SignRawTransactionCmd{
-		RawTx:    msg.RawTx,
-		Inputs:   msg.Inputs,
-		PrivKeys: msg.PrivKeys,
-		Flags:    msg.Flags,
+		RawTx:  msg.RawTx,
+		Inputs: msg.Inputs,
+		Flags:  msg.Flags,
 	}
 	conf, err := ParseNetworkToConf(string(c.networkType))
-	result, err := SignRawTransaction(signCmd, conf)
+	if err != nil {
+		return
+	}
+	result, err := SignRawTransactionWithKeys(signCmd, []*btcutil.WIF{wif}, conf)
 	if err != nil {
 		return
 	}
@@ -44,3 +64,40 @@ func (c *Chain) SignRawTx(rawTx, privateKeyWif string) (signedRawTx string, err
 	signedRawTx = result.Hex
 	return
 }
+
+// DecodeTx 解析SignToStr的待签名内容（CustomHexMsg的JSON），签名内容即rawTx本身
+// 交易信息为全部输出的地址及金额（satoshi），OP_RETURN等不转移金额的输出会被忽略，无法解析地址的输出使用脚本的16进制作为地址
+func (c *Chain) DecodeTx(rawTx []byte) ([]byte, *keybox.TxInfo, error) {
+	var cmd SignRawTransactionCmd
+	if err := json.Unmarshal(rawTx, &cmd); err != nil {
+		return nil, nil, fmt.Errorf("btc DecodeTx json.Unmarshal err:%v", err.Error())
+	}
+	serializedTx, err := hex.DecodeString(cmd.RawTx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("btc DecodeTx hex decode rawTx err:%v", err.Error())
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+		return nil, nil, fmt.Errorf("btc DecodeTx deserialize err:%v", err.Error())
+	}
+	params, err := ParseNetworkToConf(string(c.networkType))
+	if err != nil {
+		return nil, nil, err
+	}
+	info := new(keybox.TxInfo)
+	for _, out := range tx.TxOut {
+		class, addrs, _, err := txscript.ExtractPkScriptAddrs(out.PkScript, params)
+		if err == nil && class == txscript.NullDataTy && out.Value == 0 {
+			continue
+		}
+		to := hex.EncodeToString(out.PkScript)
+		if err == nil && len(addrs) == 1 {
+			to = addrs[0].EncodeAddress()
+		}
+		info.Outputs = append(info.Outputs, keybox.TxOutput{To: to, Value: big.NewInt(out.Value)})
+	}
+	if len(info.Outputs) == 0 {
+		return nil, nil, fmt.Errorf("btc DecodeTx tx has no outputs")
+	}
+	return rawTx, info, nil
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -26,7 +27,7 @@ type RawTxInput struct {
 	Vout         uint32  `json:"vout"`
 	ScriptPubKey string  `json:"scriptPubKey"`
 	RedeemScript string  `json:"redeemScript"`
-	Amount       float64 `json:"amount,omitempty"` // only for bch
+	Amount       float64 `json:"amount,omitempty"` // required for segwit, taproot and bch inputs
 }
 
 // SignRawTransactionCmd defines the signrawtransaction JSON-RPC command.
@@ -53,6 +54,25 @@ func NewSignRawTransactionCmd(hexEncodedTx string, inputs *[]RawTxInput, privKey
 
 // SignRawTransaction handles the signrawtransaction command.
 func SignRawTransaction(cmd *SignRawTransactionCmd, chainCfg *chaincfg.Params) (*btcjson.SignRawTransactionResult, error) {
+	// Parse list of private keys, if present. If there are any keys here
+	// they are the keys that we may use for signing. If empty we will
+	// use any keys known to us already.
+	var wifs []*btcutil.WIF
+	if cmd.PrivKeys != nil {
+		for _, key := range *cmd.PrivKeys {
+			wif, err := btcutil.DecodeWIF(key)
+			if err != nil {
+				return nil, err
+			}
+			wifs = append(wifs, wif)
+		}
+	}
+	return SignRawTransactionWithKeys(cmd, wifs, chainCfg)
+}
+
+// SignRawTransactionWithKeys handles the signrawtransaction command with the
+// already decoded private keys, cmd.PrivKeys is ignored.
+func SignRawTransactionWithKeys(cmd *SignRawTransactionCmd, wifs []*btcutil.WIF, chainCfg *chaincfg.Params) (*btcjson.SignRawTransactionResult, error) {
 	serializedTx, err := hex.DecodeString(cmd.RawTx)
 	if err != nil {
 		return nil, err
@@ -86,6 +106,7 @@ func SignRawTransaction(cmd *SignRawTransactionCmd, chainCfg *chaincfg.Params) (
 	// TODO: really we probably should look these up with btcd anyway to
 	// make sure that they match the blockchain if present.
 	inputs := make(map[wire.OutPoint][]byte)
+	amounts := make(map[wire.OutPoint]int64)
 	scripts := make(map[string][]byte)
 	var cmdInputs []RawTxInput
 	if cmd.Inputs != nil {
@@ -108,7 +129,7 @@ func SignRawTransaction(cmd *SignRawTransactionCmd, chainCfg *chaincfg.Params) (
 		// get scripts from the wallet.
 		// Empty strings are ok for this one and hex.DecodeString will
 		// DTRT.
-		if cmd.PrivKeys != nil && len(*cmd.PrivKeys) != 0 {
+		if len(wifs) != 0 {
 			redeemScript, err := hex.DecodeString(rti.RedeemScript)
 			if err != nil {
 				return nil, err
@@ -120,35 +141,42 @@ func SignRawTransaction(cmd *SignRawTransactionCmd, chainCfg *chaincfg.Params) (
 			}
 			scripts[addr.String()] = redeemScript
 		}
-		inputs[wire.OutPoint{
+		amount, err := btcutil.NewAmount(rti.Amount)
+		if err != nil {
+			return nil, err
+		}
+		outPoint := wire.OutPoint{
 			Hash:  *inputHash,
 			Index: rti.Vout,
-		}] = script
+		}
+		inputs[outPoint] = script
+		amounts[outPoint] = int64(amount)
 	}
 
-	// Parse list of private keys, if present. If there are any keys here
-	// they are the keys that we may use for signing. If empty we will
-	// use any keys known to us already.
 	var keys map[string]*btcutil.WIF
-	if cmd.PrivKeys != nil {
+	if len(wifs) != 0 {
 		keys = make(map[string]*btcutil.WIF)
 
-		for _, key := range *cmd.PrivKeys {
-			wif, err := btcutil.DecodeWIF(key)
-			if err != nil {
-				return nil, err
-			}
-
+		for _, wif := range wifs {
 			if !wif.IsForNet(chainCfg) {
 				s := "key network doesn't match wallet's"
 				return nil, errors.New(s)
 			}
 
-			addr, err := btcutil.NewAddressPubKey(wif.SerializePubKey(), chainCfg)
-			if err != nil {
-				return nil, err
+			// P2PKH addresses of both the compressed and uncompressed public key
+			for _, compress := range []bool{wif.CompressPubKey, !wif.CompressPubKey} {
+				w, err := btcutil.NewWIF(wif.PrivKey, chainCfg, compress)
+				if err != nil {
+					return nil, err
+				}
+				addr, err := btcutil.NewAddressPubKey(w.SerializePubKey(), chainCfg)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := keys[addr.EncodeAddress()]; !ok {
+					keys[addr.EncodeAddress()] = w
+				}
 			}
-			keys[addr.EncodeAddress()] = wif
 		}
 	}
 
@@ -174,7 +202,12 @@ func SignRawTransaction(cmd *SignRawTransactionCmd, chainCfg *chaincfg.Params) (
 	// `complete' denotes that we successfully signed all outputs and that
 	// all scripts will run to completion. This is returned as part of the
 	// reply.
-	signErrs, err := signTransaction(&tx, hashType, inputs, keys, scripts, chainCfg)
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, txIn := range tx.TxIn {
+		op := txIn.PreviousOutPoint
+		prevOutFetcher.AddPrevOut(op, wire.NewTxOut(amounts[op], inputs[op]))
+	}
+	signErrs, err := signTransaction(&tx, hashType, inputs, prevOutFetcher, keys, scripts, chainCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -225,11 +258,13 @@ type SignatureError struct {
 // The transaction pointed to by tx is modified by this function.
 func signTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 	additionalPrevScripts map[wire.OutPoint][]byte,
+	prevOutFetcher txscript.PrevOutputFetcher,
 	additionalKeysByAddress map[string]*btcutil.WIF,
 	p2shRedeemScriptsByAddress map[string][]byte,
 	chainCfg *chaincfg.Params) ([]SignatureError, error) {
 
 	var signErrors []SignatureError
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
 	for i, txIn := range tx.TxIn {
 		prevOutScript, ok := additionalPrevScripts[txIn.PreviousOutPoint]
 		if !ok {
@@ -267,8 +302,17 @@ func signTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 		// SigHashSingle inputs can only be signed if there's a
 		// corresponding output. However this could be already signed,
 		// so we always verify the output.
+		amount := prevOutFetcher.FetchPrevOutput(txIn.PreviousOutPoint).Value
 		if (hashType&txscript.SigHashSingle) != txscript.SigHashSingle || i < len(tx.TxOut) {
-			script, err := txscript.SignTxOutput(chainCfg, tx, i, prevOutScript, hashType, getKey, getScript, txIn.SignatureScript)
+			// P2WPKH, P2SH-P2WPKH and P2TR are not supported by SignTxOutput
+			signed, err := signWitnessInput(tx, i, hashType, prevOutScript, amount, sigHashes, additionalKeysByAddress, chainCfg)
+			if err == nil && !signed {
+				var script []byte
+				script, err = txscript.SignTxOutput(chainCfg, tx, i, prevOutScript, hashType, getKey, getScript, txIn.SignatureScript)
+				if err == nil {
+					txIn.SignatureScript = script
+				}
+			}
 			// Failure to sign isn't an error, it just means that
 			// the tx isn't complete.
 			if err != nil {
@@ -278,12 +322,11 @@ func signTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 				})
 				continue
 			}
-			txIn.SignatureScript = script
 		}
 
 		// Either it was already signed or we just signed it.
 		// Find out if it is completely satisfied or still needs more.
-		vm, err := txscript.NewEngine(prevOutScript, tx, i, txscript.StandardVerifyFlags, nil, nil, 0, nil)
+		vm, err := txscript.NewEngine(prevOutScript, tx, i, txscript.StandardVerifyFlags, nil, sigHashes, amount, prevOutFetcher)
 		if err == nil {
 			err = vm.Execute()
 		}
@@ -296,3 +339,100 @@ func signTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 	}
 	return signErrors, nil
 }
+
+// signWitnessInput signs a P2WPKH, P2SH-P2WPKH or P2TR key path input with the
+// compressed public key of the matching key. It returns false if prevOutScript
+// is not one of these types or no key matches, so the caller falls back to
+// SignTxOutput.
+func signWitnessInput(tx *wire.MsgTx, idx int, hashType txscript.SigHashType,
+	prevOutScript []byte, amount int64, sigHashes *txscript.TxSigHashes,
+	keysByAddress map[string]*btcutil.WIF, chainCfg *chaincfg.Params) (bool, error) {
+
+	class := txscript.GetScriptClass(prevOutScript)
+	if class != txscript.WitnessV0PubKeyHashTy &&
+		class != txscript.WitnessV1TaprootTy &&
+		class != txscript.ScriptHashTy {
+		return false, nil
+	}
+	for _, wif := range keysByAddress {
+		pubKey := wif.PrivKey.PubKey()
+		witnessAddr, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubKey.SerializeCompressed()), chainCfg)
+		if err != nil {
+			return false, err
+		}
+		witnessScript, err := txscript.PayToAddrScript(witnessAddr)
+		if err != nil {
+			return false, err
+		}
+		switch class {
+		case txscript.WitnessV0PubKeyHashTy:
+			if !bytes.Equal(prevOutScript, witnessScript) {
+				continue
+			}
+			if amount <= 0 {
+				return false, errors.New("amount is required for P2WPKH input")
+			}
+			witness, err := txscript.WitnessSignature(tx, sigHashes, idx, amount, witnessScript, hashType, wif.PrivKey, true)
+			if err != nil {
+				return false, err
+			}
+			tx.TxIn[idx].Witness = witness
+			return true, nil
+		case txscript.ScriptHashTy:
+			scriptAddr, err := btcutil.NewAddressScriptHash(witnessScript, chainCfg)
+			if err != nil {
+				return false, err
+			}
+			nestedScript, err := txscript.PayToAddrScript(scriptAddr)
+			if err != nil {
+				return false, err
+			}
+			if !bytes.Equal(prevOutScript, nestedScript) {
+				continue
+			}
+			if amount <= 0 {
+				return false, errors.New("amount is required for P2SH-P2WPKH input")
+			}
+			witness, err := txscript.WitnessSignature(tx, sigHashes, idx, amount, witnessScript, hashType, wif.PrivKey, true)
+			if err != nil {
+				return false, err
+			}
+			sigScript, err := txscript.NewScriptBuilder().AddData(witnessScript).Script()
+			if err != nil {
+				return false, err
+			}
+			tx.TxIn[idx].SignatureScript = sigScript
+			tx.TxIn[idx].Witness = witness
+			return true, nil
+		case txscript.WitnessV1TaprootTy:
+			taprootKey := txscript.ComputeTaprootKeyNoScript(pubKey)
+			taprootAddr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(taprootKey), chainCfg)
+			if err != nil {
+				return false, err
+			}
+			taprootScript, err := txscript.PayToAddrScript(taprootAddr)
+			if err != nil {
+				return false, err
+			}
+			if !bytes.Equal(prevOutScript, taprootScript) {
+				continue
+			}
+			if amount <= 0 {
+				return false, errors.New("amount is required for P2TR input")
+			}
+			// BIP341: SIGHASH_DEFAULT commits to the same data as SIGHASH_ALL
+			// and produces a 64-byte signature
+			taprootHashType := hashType
+			if taprootHashType == txscript.SigHashAll {
+				taprootHashType = txscript.SigHashDefault
+			}
+			witness, err := txscript.TaprootWitnessSignature(tx, sigHashes, idx, amount, prevOutScript, taprootHashType, wif.PrivKey)
+			if err != nil {
+				return false, err
+			}
+			tx.TxIn[idx].Witness = witness
+			return true, nil
+		}
+	}
+	return false, nil
+}
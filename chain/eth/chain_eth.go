@@ -18,6 +18,16 @@ type Chain struct {
 	networkType chain.NetworkType
 }
 
+func init() {
+	keybox.MustRegisterChain(&keybox.ChainRegistration{
+		Info:     keybox.ChainInfoETH,
+		Networks: []chain.NetworkType{chain.MainNet, chain.TestNet, chain.DevNet},
+		Factory: func(networkType chain.NetworkType) keybox.ChainAPI {
+			return NewChain(networkType)
+		},
+	})
+}
+
 func NewChain(networkType chain.NetworkType) *Chain {
 	return &Chain{
 		chainInfo:   keybox.ChainInfoETH,
@@ -30,6 +40,11 @@ func (a *Chain) ChainInfo() *keybox.ChainInfo {
 	return a.chainInfo
 }
 
+// 获取网络类型
+func (a *Chain) NetworkType() chain.NetworkType {
+	return a.networkType
+}
+
 // 导出私钥
 func (a *Chain) ExportPrivateKey(priKey []byte, isCompressPubKey bool) (string, error) {
 	return hexutil.Encode(priKey), nil
@@ -44,6 +59,22 @@ func (a *Chain) GetAddressFromPubKey(pubKey []byte) (string, error) {
 	return types.BytesToAddress(bytes).Hex(), nil
 }
 
+// 从EIP-155的未签名交易中解析签名Hash及交易信息，合约创建交易的To为空
+func (a *Chain) DecodeTx(rawTx []byte) ([]byte, *keybox.TxInfo, error) {
+	tx, err := DecodeTransaction(rawTx)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := &keybox.TxInfo{
+		Value: tx.Value,
+		Data:  tx.Data,
+	}
+	if tx.To != nil {
+		info.To = tx.To.Hex()
+	}
+	return keccak.Keccak256(rawTx), info, nil
+}
+
 // 签名直接返回签名的string
 func (a *Chain) SignToStr(priKey []byte, hash []byte) (string, error) {
 	signature, err := a.Sign(priKey, hash)
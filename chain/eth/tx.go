@@ -0,0 +1,145 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package eth
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/chain5j/chain5j-pkg/codec/rlp"
+	"github.com/chain5j/chain5j-pkg/crypto/keccak"
+	"github.com/chain5j/chain5j-pkg/types"
+	"github.com/chain5j/chain5j-pkg/util/hexutil"
+)
+
+// Transaction 未签名的以太坊交易（EIP-155）
+// 只读钱包构造交易后，将SigningHash交给持有私钥的钱包签名，再通过WithSignature组装成已签名交易
+type Transaction struct {
+	ChainId  *big.Int       // 链ID
+	Nonce    uint64         // nonce
+	GasPrice *big.Int       // gas价格
+	GasLimit uint64         // gas上限
+	To       *types.Address // 接收地址，nil表示创建合约
+	Value    *big.Int       // 转账金额
+	Data     []byte         // 交易数据
+}
+
+// NewTransaction 创建未签名的交易
+// to 接收地址，为空表示创建合约
+func NewTransaction(chainId *big.Int, nonce uint64, to string, value *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) (*Transaction, error) {
+	if chainId == nil || chainId.Sign() <= 0 {
+		return nil, fmt.Errorf("eth NewTransaction chainId is invalid")
+	}
+	tx := &Transaction{
+		ChainId:  chainId,
+		Nonce:    nonce,
+		GasPrice: bigOrZero(gasPrice),
+		GasLimit: gasLimit,
+		Value:    bigOrZero(value),
+		Data:     data,
+	}
+	if to != "" {
+		if !types.IsHexAddress(to) {
+			return nil, fmt.Errorf("eth NewTransaction to address %s is invalid", to)
+		}
+		addr := types.HexToAddress(to)
+		tx.To = &addr
+	}
+	return tx, nil
+}
+
+// DecodeTransaction 解析EncodeUnsigned编码的未签名交易
+func DecodeTransaction(rawTx []byte) (*Transaction, error) {
+	var dec struct {
+		Nonce    uint64
+		GasPrice *big.Int
+		GasLimit uint64
+		To       []byte
+		Value    *big.Int
+		Data     []byte
+		ChainId  *big.Int
+		R        *big.Int
+		S        *big.Int
+	}
+	if err := rlp.DecodeBytes(rawTx, &dec); err != nil {
+		return nil, fmt.Errorf("eth DecodeTransaction rlp decode err:%v", err.Error())
+	}
+	if dec.ChainId.Sign() <= 0 || dec.R.Sign() != 0 || dec.S.Sign() != 0 {
+		return nil, fmt.Errorf("eth DecodeTransaction tx is not an unsigned EIP-155 transaction")
+	}
+	tx := &Transaction{
+		ChainId:  dec.ChainId,
+		Nonce:    dec.Nonce,
+		GasPrice: dec.GasPrice,
+		GasLimit: dec.GasLimit,
+		Value:    dec.Value,
+		Data:     dec.Data,
+	}
+	switch len(dec.To) {
+	case 0:
+	case types.AddressLength:
+		addr := types.BytesToAddress(dec.To)
+		tx.To = &addr
+	default:
+		return nil, fmt.Errorf("eth DecodeTransaction to address is invalid")
+	}
+	return tx, nil
+}
+
+// EncodeUnsigned 未签名交易的rlp编码，即EIP-155的签名内容
+func (tx *Transaction) EncodeUnsigned() ([]byte, error) {
+	return rlp.EncodeToBytes(tx.fields(tx.ChainId, big.NewInt(0), big.NewInt(0)))
+}
+
+// SigningHash 交易的签名Hash，可直接用于Wallet.Sign
+func (tx *Transaction) SigningHash() ([]byte, error) {
+	data, err := tx.EncodeUnsigned()
+	if err != nil {
+		return nil, err
+	}
+	return keccak.Keccak256(data), nil
+}
+
+// WithSignature 使用签名组装已签名的交易，返回0x开头的rawTx
+// sign 为R||S||V的65字节签名（Wallet.Sign返回值hex解码），V为0或1
+func (tx *Transaction) WithSignature(sign []byte) (string, error) {
+	if len(sign) != 65 || sign[64] > 1 {
+		return "", fmt.Errorf("eth WithSignature sign is invalid")
+	}
+	v := new(big.Int).Mul(tx.ChainId, big.NewInt(2))
+	v.Add(v, big.NewInt(35+int64(sign[64])))
+	r := new(big.Int).SetBytes(sign[:32])
+	s := new(big.Int).SetBytes(sign[32:64])
+	data, err := rlp.EncodeToBytes(tx.fields(v, r, s))
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(data), nil
+}
+
+func (tx *Transaction) fields(v, r, s *big.Int) []interface{} {
+	var to []byte
+	if tx.To != nil {
+		to = tx.To.Bytes()
+	}
+	return []interface{}{
+		tx.Nonce,
+		bigOrZero(tx.GasPrice),
+		tx.GasLimit,
+		to,
+		bigOrZero(tx.Value),
+		tx.Data,
+		v,
+		r,
+		s,
+	}
+}
+
+func bigOrZero(b *big.Int) *big.Int {
+	if b == nil {
+		return big.NewInt(0)
+	}
+	return b
+}
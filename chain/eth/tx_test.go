@@ -0,0 +1,74 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package eth
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/chain5j/chain5j-pkg/util/hexutil"
+)
+
+// https://eips.ethereum.org/EIPS/eip-155 中的示例
+func TestTransaction_EIP155(t *testing.T) {
+	value, _ := new(big.Int).SetString("1000000000000000000", 10)
+	tx, err := NewTransaction(big.NewInt(1), 9, "0x3535353535353535353535353535353535353535", value, 21000, big.NewInt(20000000000), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := tx.EncodeUnsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(unsigned) != "ec098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a764000080018080" {
+		t.Fatalf("unsigned=%x", unsigned)
+	}
+	hash, err := tx.SigningHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(hash) != "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53" {
+		t.Fatalf("hash=%x", hash)
+	}
+
+	chain := NewChain("mainnet")
+	priKey, _ := hexutil.Decode("0x4646464646464646464646464646464646464646464646464646464646464646")
+	signStr, err := chain.SignToStr(priKey, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign, _ := hex.DecodeString(signStr)
+	rawTx, err := tx.WithSignature(sign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rawTx != "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83" {
+		t.Fatalf("rawTx=%s", rawTx)
+	}
+}
+
+func TestChain_DecodeTx(t *testing.T) {
+	unsigned, _ := hex.DecodeString("ec098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a764000080018080")
+	chain := NewChain("mainnet")
+	hash, info, err := chain.DecodeTx(unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(hash) != "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53" {
+		t.Fatalf("hash=%x", hash)
+	}
+	if info.To != "0x3535353535353535353535353535353535353535" || info.Value.String() != "1000000000000000000" || len(info.Data) != 0 {
+		t.Fatalf("info=%+v", info)
+	}
+
+	// 已签名的交易及其他编码不能作为未签名交易
+	signed, _ := hexutil.Decode("0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83")
+	for _, raw := range [][]byte{signed, {0x01, 0x02}, nil} {
+		if _, _, err := chain.DecodeTx(raw); err == nil {
+			t.Fatalf("DecodeTx(%x) should fail", raw)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/chain"
+)
+
+var (
+	ErrChainNotFound       = errors.New("chain not registered")
+	ErrChainExists         = errors.New("chain already registered")
+	ErrNetworkNotSupported = errors.New("network is not supported by the chain")
+)
+
+// ChainFactory 按网络类型创建链
+type ChainFactory func(networkType chain.NetworkType) ChainAPI
+
+// ChainRegistration 注册的链
+// Info.ChainType 为SLIP-44的币种类型（强化派生），用作默认的coinType
+type ChainRegistration struct {
+	Info     *ChainInfo          // 链名称、币种类型及算法
+	Networks []chain.NetworkType // 支持的网络类型，为空时不限制
+	Factory  ChainFactory        // 创建链
+}
+
+// 是否支持该网络类型
+func (r *ChainRegistration) supportNetwork(networkType chain.NetworkType) bool {
+	if len(r.Networks) == 0 {
+		return true
+	}
+	for _, n := range r.Networks {
+		if n == networkType {
+			return true
+		}
+	}
+	return false
+}
+
+var chainRegistry = struct {
+	sync.RWMutex
+	byName     map[string]*ChainRegistration // key为大写的链名称
+	byCoinType map[uint32]*ChainRegistration
+}{
+	byName:     make(map[string]*ChainRegistration),
+	byCoinType: make(map[uint32]*ChainRegistration),
+}
+
+// RegisterChain 注册链，链名称不区分大小写，链名称及币种类型不能重复
+// 链的实现在init中注册，如chain/btc、chain/eth
+func RegisterChain(reg *ChainRegistration) error {
+	if reg == nil || reg.Info == nil || reg.Info.ChainName == "" || reg.Factory == nil {
+		return fmt.Errorf("RegisterChain parameter error")
+	}
+	if reg.Info.ChainType < bip32.FirstHardenedChild {
+		return fmt.Errorf("RegisterChain chainType should more than the %x", bip32.FirstHardenedChild)
+	}
+	name := strings.ToUpper(reg.Info.ChainName)
+	chainRegistry.Lock()
+	defer chainRegistry.Unlock()
+	if _, ok := chainRegistry.byName[name]; ok {
+		return fmt.Errorf("%w: %s", ErrChainExists, reg.Info.ChainName)
+	}
+	if exist, ok := chainRegistry.byCoinType[reg.Info.ChainType]; ok {
+		return fmt.Errorf("%w: coinType %x is used by %s", ErrChainExists, reg.Info.ChainType, exist.Info.ChainName)
+	}
+	chainRegistry.byName[name] = reg
+	chainRegistry.byCoinType[reg.Info.ChainType] = reg
+	return nil
+}
+
+// MustRegisterChain 注册链，失败时panic
+func MustRegisterChain(reg *ChainRegistration) {
+	if err := RegisterChain(reg); err != nil {
+		panic(err)
+	}
+}
+
+// LookupChain 按链名称查找注册的链，不区分大小写
+func LookupChain(name string) (*ChainRegistration, error) {
+	chainRegistry.RLock()
+	defer chainRegistry.RUnlock()
+	reg, ok := chainRegistry.byName[strings.ToUpper(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrChainNotFound, name)
+	}
+	return reg, nil
+}
+
+// LookupChainByCoinType 按SLIP-44的币种类型查找注册的链，coinType可以为强化派生或原始值
+func LookupChainByCoinType(coinType uint32) (*ChainRegistration, error) {
+	if coinType < bip32.FirstHardenedChild {
+		coinType += bip32.FirstHardenedChild
+	}
+	chainRegistry.RLock()
+	defer chainRegistry.RUnlock()
+	reg, ok := chainRegistry.byCoinType[coinType]
+	if !ok {
+		return nil, fmt.Errorf("%w: coinType %x", ErrChainNotFound, coinType)
+	}
+	return reg, nil
+}
+
+// RegisteredChains 全部注册的链，按链名称排序
+func RegisteredChains() []*ChainRegistration {
+	chainRegistry.RLock()
+	result := make([]*ChainRegistration, 0, len(chainRegistry.byName))
+	for _, reg := range chainRegistry.byName {
+		result = append(result, reg)
+	}
+	chainRegistry.RUnlock()
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Info.ChainName < result[j].Info.ChainName
+	})
+	return result
+}
+
+// NewChainAPI 按链名称及网络类型创建注册的链
+func NewChainAPI(name string, networkType chain.NetworkType) (ChainAPI, error) {
+	reg, err := LookupChain(name)
+	if err != nil {
+		return nil, err
+	}
+	if !reg.supportNetwork(networkType) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNetworkNotSupported, reg.Info.ChainName, networkType)
+	}
+	return reg.Factory(networkType), nil
+}
+
+// 未指定coinType（为0）时使用链的币种类型
+func defaultCoinType(coinType uint32, api ChainAPI) uint32 {
+	if coinType == 0 && api != nil && api.ChainInfo() != nil {
+		return api.ChainInfo().ChainType
+	}
+	return coinType
+}
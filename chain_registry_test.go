@@ -0,0 +1,95 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/chain"
+)
+
+// 注册测试链，测试结束后删除
+func registerTestChain(t *testing.T, name string, coinType uint32) {
+	reg := &ChainRegistration{
+		Info:     newTestChain(name, coinType).ChainInfo(),
+		Networks: []chain.NetworkType{chain.MainNet, chain.TestNet},
+		Factory: func(networkType chain.NetworkType) ChainAPI {
+			return &networkTestChain{testChain: newTestChain(name, coinType), network: networkType}
+		},
+	}
+	if err := RegisterChain(reg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		chainRegistry.Lock()
+		delete(chainRegistry.byName, strings.ToUpper(name))
+		delete(chainRegistry.byCoinType, coinType)
+		chainRegistry.Unlock()
+	})
+}
+
+func TestChainRegistry(t *testing.T) {
+	registerTestChain(t, "Reg1", bip32.ParseHDNum(90001))
+
+	reg, err := LookupChain("REG1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.Info.ChainName != "Reg1" {
+		t.Fatalf("chain name got %s", reg.Info.ChainName)
+	}
+	for _, coinType := range []uint32{90001, bip32.ParseHDNum(90001)} {
+		if reg, err := LookupChainByCoinType(coinType); err != nil || reg.Info.ChainName != "Reg1" {
+			t.Fatalf("lookup coinType %x err:%v", coinType, err)
+		}
+	}
+	if _, err := LookupChain("unknown"); !errors.Is(err, ErrChainNotFound) {
+		t.Fatal("unknown chain should fail")
+	}
+	if err := RegisterChain(&ChainRegistration{Info: &ChainInfo{ChainName: "reg1", ChainType: bip32.ParseHDNum(90002)}, Factory: reg.Factory}); !errors.Is(err, ErrChainExists) {
+		t.Fatal("duplicated chain name should fail")
+	}
+	if err := RegisterChain(&ChainRegistration{Info: &ChainInfo{ChainName: "Reg2", ChainType: bip32.ParseHDNum(90001)}, Factory: reg.Factory}); !errors.Is(err, ErrChainExists) {
+		t.Fatal("duplicated coinType should fail")
+	}
+	if err := RegisterChain(&ChainRegistration{Info: &ChainInfo{ChainName: "Reg2", ChainType: 90002}, Factory: reg.Factory}); err == nil {
+		t.Fatal("not hardened coinType should fail")
+	}
+	found := false
+	for _, r := range RegisteredChains() {
+		found = found || r.Info.ChainName == "Reg1"
+	}
+	if !found {
+		t.Fatal("registered chains should contain Reg1")
+	}
+
+	api, err := NewChainAPI("reg1", chain.TestNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.(NetworkAPI).NetworkType() != chain.TestNet {
+		t.Fatal("chain should be created with the network type")
+	}
+	if _, err := NewChainAPI("reg1", chain.DevNet); !errors.Is(err, ErrNetworkNotSupported) {
+		t.Fatal("unsupported network should fail")
+	}
+
+	// coinType为0时使用链的币种类型
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, keyPath, err := wallet.CreateAccount(bip44.Purpose, 0, 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyPath != "/44/90001/0/0/0" {
+		t.Fatalf("keyPath got %s", keyPath)
+	}
+}
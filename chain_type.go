@@ -7,6 +7,7 @@ package keybox
 import "github.com/chain5j/keybox/bip44"
 
 // https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+// 链的实现通过RegisterChain注册
 const (
 	TypeBTC  uint32 = 0x80000000
 	TypeETH  uint32 = 0x8000003c
@@ -14,12 +15,16 @@ const (
 )
 
 const (
-	Purpose45 uint32 = 0x8000002d
+	Purpose44 uint32 = bip44.Purpose
+	Purpose45 uint32 = bip44.Purpose45
+	Purpose49 uint32 = bip44.Purpose49
+	Purpose84 uint32 = bip44.Purpose84
+	Purpose86 uint32 = bip44.Purpose86
 )
 
 type ChainInfo struct {
 	ChainName     string // 链名称（eth，btc）
-	ChainType     uint32 // 链分配的类型值
+	ChainType     uint32 // 链分配的类型值，即SLIP-44的币种类型（强化派生）
 	AlgorithmName string // 链的算法名称（s256,p256,gm2）
 	Algorithm     uint32 // 链算法类型值
 }
@@ -27,7 +32,7 @@ type ChainInfo struct {
 var (
 	ChainInfoBTC = &ChainInfo{
 		ChainName:     "BTC",
-		ChainType:     bip44.CoinTypeBTC,
+		ChainType:     TypeBTC,
 		AlgorithmName: "S256",
 		Algorithm:     0x80000200,
 	}
@@ -37,4 +42,11 @@ var (
 		AlgorithmName: "S256",
 		Algorithm:     0x80000200,
 	}
+	// OMNI使用BTC的密钥、地址及交易
+	ChainInfoOMNI = &ChainInfo{
+		ChainName:     "OMNI",
+		ChainType:     TypeOMNI,
+		AlgorithmName: "S256",
+		Algorithm:     0x80000200,
+	}
 )
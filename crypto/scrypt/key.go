@@ -47,6 +47,7 @@ type encryptedKeyJSONV3 struct {
 	Id      string     `json:"id"`
 	Version int        `json:"version"`
 	Address string     `json:"address,omitempty"`
+	Path    string     `json:"path,omitempty"`
 	Crypto  CryptoJSON `json:"crypto"`
 }
 
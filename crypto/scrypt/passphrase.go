@@ -119,11 +119,22 @@ func EncryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
 		key.Id.String(),
 		version,
 		key.Addr,
+		key.Path,
 		cryptoStruct,
 	}
 	return json.Marshal(encryptedKeyJSONV3)
 }
 
+// KeyPath returns the derivation path recorded in the json blob without
+// decrypting it, empty if the blob does not record one.
+func KeyPath(keyjson []byte) string {
+	k := new(encryptedKeyJSONV3)
+	if err := json.Unmarshal(keyjson, k); err != nil {
+		return ""
+	}
+	return k.Path
+}
+
 // DecryptKey decrypts a key from a json blob, returning the private key itself.
 func DecryptKey(keyjson []byte, auth string) (*Key, error) {
 	// Parse the json into a simple map to fetch the key version
@@ -135,7 +146,7 @@ func DecryptKey(keyjson []byte, auth string) (*Key, error) {
 	var (
 		keyBytes, keyId []byte
 		err             error
-		addr            string
+		addr, path      string
 	)
 	if version, ok := m["version"].(string); ok && version == "1" {
 		k := new(encryptedKeyJSONV1)
@@ -150,6 +161,7 @@ func DecryptKey(keyjson []byte, auth string) (*Key, error) {
 		}
 		keyBytes, keyId, err = decryptKeyV3(k, auth)
 		addr = k.Address
+		path = k.Path
 	}
 	// Handle any decryption errors and return the key
 	if err != nil {
@@ -158,6 +170,7 @@ func DecryptKey(keyjson []byte, auth string) (*Key, error) {
 
 	return &Key{
 		Id:         uuid.UUID(keyId),
+		Path:       path,
 		Addr:       addr,
 		PrivateKey: keyBytes,
 	}, nil
@@ -77,3 +77,25 @@ func TestEncryptKey(t *testing.T) {
 	prvKey1 := hex.EncodeToString(key.PrivateKey)
 	fmt.Println("prvKey1", prvKey1)
 }
+
+// Tests that the derivation path is kept in the json blob.
+func TestKeyPath(t *testing.T) {
+	key := &Key{Path: "/44/60/0/0/0", PrivateKey: []byte{1, 2, 3}}
+	keyjson, err := EncryptKey(key, "foo", veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path := KeyPath(keyjson); path != key.Path {
+		t.Fatalf("path=%s, want %s", path, key.Path)
+	}
+	decrypted, err := DecryptKey(keyjson, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted.Path != key.Path {
+		t.Fatalf("decrypted path=%s, want %s", decrypted.Path, key.Path)
+	}
+	if path := KeyPath([]byte("{}")); path != "" {
+		t.Fatalf("path=%s, want empty", path)
+	}
+}
@@ -0,0 +1,42 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package secret
+
+import "runtime"
+
+// Copy 复制敏感数据（私钥、种子等），避免与调用方共享底层数组，使用完毕后需要调用Zero清零
+func Copy(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	s := make([]byte, len(b))
+	copy(s, b)
+	return s
+}
+
+// Zero 将字节数组清零
+func Zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+	// 防止编译器将清零操作优化掉
+	runtime.KeepAlive(b)
+}
+
+// ZeroAll 将多个字节数组清零
+func ZeroAll(bs ...[]byte) {
+	for _, b := range bs {
+		Zero(b)
+	}
+}
+
+// IsZero 字节数组是否已全部清零
+func IsZero(b []byte) bool {
+	var v byte
+	for _, c := range b {
+		v |= c
+	}
+	return v == 0
+}
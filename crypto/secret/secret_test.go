@@ -0,0 +1,27 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package secret
+
+import "testing"
+
+func TestZero(t *testing.T) {
+	raw := []byte{1, 2, 3, 4}
+	s := Copy(raw)
+	s[0] = 9
+	if raw[0] != 1 {
+		t.Fatalf("Copy should copy the input")
+	}
+	Zero(s)
+	if !IsZero(s) {
+		t.Fatalf("bytes should be zeroed: %v", s)
+	}
+	if IsZero(raw) {
+		t.Fatalf("source bytes should not be zeroed")
+	}
+	ZeroAll(raw, nil)
+	if !IsZero(raw) {
+		t.Fatalf("bytes should be zeroed: %v", raw)
+	}
+}
@@ -0,0 +1,202 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/chain5j/keybox/bip32"
+)
+
+// DerivedAddress 批量派生的地址
+type DerivedAddress struct {
+	Address string `json:"address"` // 地址
+	PubKey  string `json:"pubKey"`  // 16进制公钥
+	KeyPath string `json:"keyPath"` // 地址的路径
+}
+
+// DeriveAddresses 按路径模板批量派生地址，派生使用多个协程并行进行
+// template 路径模板，*表示地址索引所在的层级，如m/44'/60'/0'/0/*，*'表示强化派生
+// start 起始索引，count 派生数量
+// persist 是否将地址记录到钱包中并写入存储后端，只读钱包只记录地址与公钥
+func (w *Wallet) DeriveAddresses(template string, start, count uint32, persist bool, api ChainAPI) ([]*DerivedAddress, error) {
+	if api == nil {
+		return nil, fmt.Errorf("wallet DeriveAddresses chainApi is nil")
+	}
+	if count == 0 || uint64(start)+uint64(count) > uint64(bip32.FirstHardenedChild) {
+		return nil, fmt.Errorf("wallet DeriveAddresses start or count is invalid")
+	}
+	childKeyPath, pos, err := parsePathTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mKey     *bip32.Key
+		password string
+	)
+	if w.WatchOnly {
+		password, err = w.unlockedPassword()
+	} else {
+		mKey, password, err = w.unlockedSecrets()
+		defer mKey.Zero()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*DerivedAddress, count)
+	infos := make([]*ChildKeyPropertyInfo, count)
+	errs := make([]error, count)
+	jobs := make(chan uint32)
+	var wg sync.WaitGroup
+	for n := 0; n < runtime.NumCPU() && n < int(count); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				path := make(bip32.DerivationPath, len(childKeyPath))
+				copy(path, childKeyPath)
+				path[pos] += start + i
+				results[i], infos[i], errs[i] = w.deriveAddress(mKey, path, persist, password, api)
+			}
+		}()
+	}
+	for i := uint32(0); i < count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if persist {
+		// 全部派生成功后才记录到钱包中，写入失败时撤销记录
+		undo := w.recordDerivedAddresses(results, infos, api)
+		if err := w.save(); err != nil {
+			undo()
+			return nil, fmt.Errorf("wallet DeriveAddresses storage.Put err:%v", err.Error())
+		}
+	}
+	return results, nil
+}
+
+// 派生单个地址，persist时返回需要记录到钱包中的属性信息
+func (w *Wallet) deriveAddress(mKey *bip32.Key, childKeyPath bip32.DerivationPath, persist bool, password string, api ChainAPI) (*DerivedAddress, *ChildKeyPropertyInfo, error) {
+	keyPath := formatChildKeyPath(childKeyPath)
+	purpose, coinType, org := childKeyPathProperties(childKeyPath)
+	if w.WatchOnly {
+		addr, pubKey, err := w.deriveWatchAddress(keyPath, api)
+		if err != nil {
+			return nil, nil, err
+		}
+		var info *ChildKeyPropertyInfo
+		if persist {
+			// 只读钱包只记录地址与公钥
+			if info, err = w.newChildKeyInfo(purpose, coinType, org, keyPath, nil, password, api); err != nil {
+				return nil, nil, err
+			}
+		}
+		return &DerivedAddress{Address: addr, PubKey: w.getPubKey(pubKey), KeyPath: keyPath}, info, nil
+	}
+
+	addr, pubKey, key, err := w.deriveAccount(mKey, childKeyPath, api)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer key.Zero()
+	var info *ChildKeyPropertyInfo
+	if persist {
+		// 与CreateAccount一致，IsSaveSubKey时才保存子私钥
+		saveKey := key
+		if !w.IsSaveSubKey {
+			saveKey = nil
+		}
+		if info, err = w.newChildKeyInfo(w.addressPurpose(childKeyPath), coinType, org, keyPath, saveKey, password, api); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &DerivedAddress{Address: addr, PubKey: w.getPubKey(pubKey), KeyPath: keyPath}, info, nil
+}
+
+// 将派生的地址记录到钱包中（不写入存储后端），返回撤销记录的函数
+func (w *Wallet) recordDerivedAddresses(results []*DerivedAddress, infos []*ChildKeyPropertyInfo, api ChainAPI) (undo func()) {
+	type record struct {
+		addr, pubKeyStr    string
+		prevPubKeyStr      string
+		prevInfo           *ChildKeyPropertyInfo
+		prevMeta           *AccountMeta
+		hasPubKey, hasInfo bool
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	records := make([]record, len(results))
+	for i, result := range results {
+		r := record{addr: result.Address, pubKeyStr: result.PubKey}
+		r.prevPubKeyStr, r.hasPubKey = w.AddrLinkPubkey[r.addr]
+		r.prevInfo, r.hasInfo = w.ChildKeyInfo[r.pubKeyStr]
+		if meta, ok := w.AccountMetas[r.addr]; ok {
+			r.prevMeta = meta.copy()
+		}
+		records[i] = r
+		w.recordChildKeyInfoLocked(r.addr, r.pubKeyStr, infos[i], api)
+	}
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		// 倒序恢复，同一地址被记录多次时恢复到最初的状态
+		for i := len(records) - 1; i >= 0; i-- {
+			r := records[i]
+			if r.hasPubKey {
+				w.AddrLinkPubkey[r.addr] = r.prevPubKeyStr
+			} else {
+				delete(w.AddrLinkPubkey, r.addr)
+			}
+			if r.hasInfo {
+				w.ChildKeyInfo[r.pubKeyStr] = r.prevInfo
+			} else {
+				delete(w.ChildKeyInfo, r.pubKeyStr)
+			}
+			if r.prevMeta != nil {
+				w.AccountMetas[r.addr] = r.prevMeta
+			} else {
+				delete(w.AccountMetas, r.addr)
+			}
+		}
+	}
+}
+
+// 解析路径模板，返回索引为0时的路径及*所在的层级
+func parsePathTemplate(template string) (bip32.DerivationPath, int, error) {
+	components := strings.Split(strings.TrimSpace(template), "/")
+	pos := -1
+	for i, c := range components {
+		if strings.HasPrefix(c, "*") {
+			if pos >= 0 {
+				return nil, 0, fmt.Errorf("wallet path template %s should contain only one *", template)
+			}
+			pos = i
+			components[i] = "0" + c[1:]
+		}
+	}
+	if pos < 0 {
+		return nil, 0, fmt.Errorf("wallet path template %s should contain *", template)
+	}
+	childKeyPath, err := parseChildKeyPath(strings.Join(components, "/"))
+	if err != nil {
+		return nil, 0, err
+	}
+	// m及兼容写法开头的/不属于任何层级
+	if components[0] == "m" || components[0] == "" {
+		pos--
+	}
+	return childKeyPath, pos, nil
+}
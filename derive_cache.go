@@ -0,0 +1,151 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/chain5j/keybox/bip32"
+)
+
+// DefaultDeriveCacheSize 派生缓存默认保存的中间节点数量
+const DefaultDeriveCacheSize = 256
+
+// 中间节点（账户层级及链层级）的派生缓存，按最近最少使用淘汰，淘汰及清空时私钥会被清零
+type deriveCache struct {
+	mu       sync.Mutex
+	capacity int    // 0使用DefaultDeriveCacheSize，小于0不缓存
+	gen      uint64 // 清空的代数，清空前开始的派生结果不再写入缓存
+	nodes    map[string]*list.Element
+	lru      *list.List
+}
+
+type deriveCacheNode struct {
+	path string
+	key  *bip32.Key
+}
+
+func (c *deriveCache) size() int {
+	if c.capacity == 0 {
+		return DefaultDeriveCacheSize
+	}
+	return c.capacity
+}
+
+// 当前的代数
+func (c *deriveCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// 获取缓存节点的副本，调用方使用完后需要清零
+func (c *deriveCache) get(path bip32.DerivationPath) *bip32.Key {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.nodes[path.String()]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*deriveCacheNode).key.Copy()
+}
+
+// 缓存节点的副本，gen与当前代数不一致时不缓存
+func (c *deriveCache) put(path bip32.DerivationPath, key *bip32.Key, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen || c.size() < 0 {
+		return
+	}
+	if c.nodes == nil {
+		c.nodes = make(map[string]*list.Element)
+		c.lru = list.New()
+	}
+	p := path.String()
+	if _, ok := c.nodes[p]; ok {
+		return
+	}
+	c.nodes[p] = c.lru.PushFront(&deriveCacheNode{path: p, key: key.Copy()})
+	for c.lru.Len() > c.size() {
+		c.removeLocked(c.lru.Back())
+	}
+}
+
+// 清空缓存并清零所有节点
+func (c *deriveCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for c.lru != nil && c.lru.Len() > 0 {
+		c.removeLocked(c.lru.Back())
+	}
+}
+
+func (c *deriveCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lru == nil {
+		return 0
+	}
+	return c.lru.Len()
+}
+
+func (c *deriveCache) setCapacity(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacity = capacity
+	for c.lru != nil && c.lru.Len() > 0 && c.lru.Len() > c.size() {
+		c.removeLocked(c.lru.Back())
+	}
+}
+
+// 需要在c.mu锁定的情况下调用
+func (c *deriveCache) removeLocked(elem *list.Element) {
+	node := c.lru.Remove(elem).(*deriveCacheNode)
+	delete(c.nodes, node.path)
+	node.key.Zero()
+}
+
+// SetDeriveCacheSize 设置派生缓存保存的中间节点数量，size<=0时关闭缓存
+func (w *Wallet) SetDeriveCacheSize(size int) {
+	if size <= 0 {
+		size = -1
+	}
+	w.cache.setCapacity(size)
+}
+
+// 按路径派生子私钥，账户层级及链层级的中间节点会被缓存，同一账户下的地址只需派生最后一级
+func (w *Wallet) deriveKey(mKey *bip32.Key, childKeyPath bip32.DerivationPath) (*bip32.Key, error) {
+	n := len(childKeyPath)
+	if n < 3 {
+		return mKey.DerivePath(childKeyPath)
+	}
+	gen := w.cache.generation()
+	chainPath := childKeyPath[:n-1]
+	chainKey := w.cache.get(chainPath)
+	if chainKey == nil {
+		accountPath := childKeyPath[:n-2]
+		accountKey := w.cache.get(accountPath)
+		if accountKey == nil {
+			var err error
+			accountKey, err = mKey.DerivePath(accountPath)
+			if err != nil {
+				return nil, err
+			}
+			w.cache.put(accountPath, accountKey, gen)
+		}
+		var err error
+		chainKey, err = accountKey.NewChildKey(childKeyPath[n-2])
+		accountKey.Zero()
+		if err != nil {
+			return nil, err
+		}
+		w.cache.put(chainPath, chainKey, gen)
+	}
+	defer chainKey.Zero()
+	return chainKey.NewChildKey(childKeyPath[n-1])
+}
@@ -0,0 +1,135 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/crypto/scrypt"
+	"github.com/chain5j/keybox/crypto/secret"
+)
+
+func TestWallet_DeriveCache(t *testing.T) {
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetDeriveCacheSize(4)
+	mKey, _, err := wallet.unlockedSecrets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mKey.Zero()
+
+	// 缓存派生与直接派生的结果一致
+	for i := 0; i < 2; i++ {
+		for _, path := range []string{"m/44'/1'/0'/0/0", "m/44'/1'/0'/0/1", "m/44'/1'/0'/1/0", "m/44'/1'/1'/0/0", "m/45'/1'/2'/0'/0/3"} {
+			childKeyPath, _ := bip32.ParseDerivationPath(path)
+			want, err := mKey.DerivePath(childKeyPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err := wallet.deriveKey(mKey, childKeyPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(key.Key, want.Key) || !bytes.Equal(key.ChainCode, want.ChainCode) {
+				t.Fatalf("%s cached key is diff", path)
+			}
+			if n := wallet.cache.len(); n > 4 {
+				t.Fatalf("cache len=%d, want <= 4", n)
+			}
+		}
+	}
+
+	// 清空前开始的派生结果不写入缓存
+	gen := wallet.cache.generation()
+	wallet.cache.purge()
+	wallet.cache.put(bip32.DerivationPath{bip32.FirstHardenedChild}, mKey, gen)
+	if wallet.cache.len() != 0 {
+		t.Fatalf("stale node should not be cached")
+	}
+
+	// 锁定后缓存被清空且节点被清零
+	childKeyPath, _ := bip32.ParseDerivationPath("m/44'/1'/0'/0/0")
+	if _, err := wallet.deriveKey(mKey, childKeyPath); err != nil {
+		t.Fatal(err)
+	}
+	cached := make([]*bip32.Key, 0)
+	for _, elem := range wallet.cache.nodes {
+		cached = append(cached, elem.Value.(*deriveCacheNode).key)
+	}
+	if len(cached) != 2 {
+		t.Fatalf("cache len=%d, want 2", len(cached))
+	}
+	wallet.Lock()
+	if wallet.cache.len() != 0 {
+		t.Fatalf("cache should be purged after lock")
+	}
+	for _, key := range cached {
+		if !secret.IsZero(key.Key) || !secret.IsZero(key.ChainCode) {
+			t.Fatalf("cached key should be zeroed after lock")
+		}
+	}
+
+	wallet.SetDeriveCacheSize(0)
+	if err := wallet.Unlock("123456", 0); err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	if _, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api); err != nil {
+		t.Fatal(err)
+	}
+	if wallet.cache.len() != 0 {
+		t.Fatalf("cache should be disabled")
+	}
+}
+
+func BenchmarkWallet_CreateAccount(b *testing.B) {
+	for _, size := range []int{0, DefaultDeriveCacheSize} {
+		b.Run(fmt.Sprintf("cache=%d", size), func(b *testing.B) {
+			wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+			if err != nil {
+				b.Fatal(err)
+			}
+			wallet.SetDeriveCacheSize(size)
+			api := newTestChain("T1", bip32.ParseHDNum(1))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, uint32(i)%bip32.FirstHardenedChild, api); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkWallet_Sign(b *testing.B) {
+	hash := scrypt.Keccak256([]byte("hello"))
+	for _, size := range []int{0, DefaultDeriveCacheSize} {
+		b.Run(fmt.Sprintf("cache=%d", size), func(b *testing.B) {
+			wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+			if err != nil {
+				b.Fatal(err)
+			}
+			wallet.SetDeriveCacheSize(size)
+			api := newTestChain("T1", bip32.ParseHDNum(1))
+			addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := wallet.Sign(addr, keyPath, hash, api); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
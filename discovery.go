@@ -0,0 +1,223 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/chain5j/keybox/bip32"
+)
+
+// DefaultGapLimit BIP44建议的地址间隔上限
+const DefaultGapLimit uint32 = 20
+
+// HistoryFunc 判断地址在链上是否存在交易记录
+type HistoryFunc func(address string, api ChainAPI) (bool, error)
+
+// DiscoveredAddress 账户发现中找到的已使用地址
+type DiscoveredAddress struct {
+	Address      string `json:"address"`      // 地址
+	KeyPath      string `json:"keyPath"`      // 地址的路径
+	Account      uint32 `json:"account"`      // 账户空间
+	Change       uint32 `json:"change"`       // 0外部接收地址 1找零地址
+	AddressIndex uint32 `json:"addressIndex"` // 地址索引
+}
+
+// DiscoverAccounts 按BIP44的gap limit规则进行账户发现
+// 从账户0开始，依次扫描外部链及找零链，连续gapLimit个地址无交易记录时停止扫描该链；
+// 账户的外部链无任何交易记录时停止发现。所有已使用的地址都会记录到AddrLinkPubkey中并写入存储后端
+// purpose purpose=45时，才使用org
+// coinType 为0时使用链的币种类型
+// gapLimit 为0时使用DefaultGapLimit
+func (w *Wallet) DiscoverAccounts(purpose, coinType, org, gapLimit uint32, history HistoryFunc, api ChainAPI) ([]*DiscoveredAddress, error) {
+	if api == nil {
+		return nil, fmt.Errorf("wallet DiscoverAccounts chainApi is nil")
+	}
+	if history == nil {
+		return nil, fmt.Errorf("wallet DiscoverAccounts history is nil")
+	}
+	coinType = defaultCoinType(coinType, api)
+	if coinType < bip32.FirstHardenedChild {
+		return nil, fmt.Errorf("wallet DiscoverAccounts coinType should more than the %x", bip32.FirstHardenedChild)
+	}
+	if purpose == Purpose45 && org < bip32.FirstHardenedChild {
+		return nil, fmt.Errorf("wallet DiscoverAccounts org should more than the %x", bip32.FirstHardenedChild)
+	}
+	if gapLimit == 0 {
+		gapLimit = DefaultGapLimit
+	}
+	// 只读钱包使用扩展公钥派生，不需要主私钥
+	var mKey *bip32.Key
+	var password string
+	var err error
+	if w.WatchOnly {
+		password, err = w.unlockedPassword()
+	} else {
+		mKey, password, err = w.unlockedSecrets()
+		defer mKey.Zero()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	discovered := make([]*DiscoveredAddress, 0)
+	for _account := bip32.FirstHardenedChild; _account >= bip32.FirstHardenedChild; _account++ {
+		if w.WatchOnly && !w.canDeriveWatchAccount(purpose, coinType, org, _account) {
+			break
+		}
+		external, err := w.discoverChain(mKey, password, purpose, coinType, org, _account, 0, gapLimit, history, api)
+		if err != nil {
+			return nil, err
+		}
+		if len(external) == 0 {
+			break
+		}
+		internal, err := w.discoverChain(mKey, password, purpose, coinType, org, _account, 1, gapLimit, history, api)
+		if err != nil {
+			return nil, err
+		}
+		discovered = append(discovered, external...)
+		discovered = append(discovered, internal...)
+	}
+
+	if len(discovered) > 0 {
+		if err := w.save(); err != nil {
+			return nil, fmt.Errorf("wallet DiscoverAccounts storage.Put err:%v", err.Error())
+		}
+	}
+	return discovered, nil
+}
+
+// 扫描账户下的一条链，直到连续gapLimit个地址无交易记录
+func (w *Wallet) discoverChain(mKey *bip32.Key, password string, purpose, coinType, org, _account, change, gapLimit uint32, history HistoryFunc, api ChainAPI) ([]*DiscoveredAddress, error) {
+	discovered := make([]*DiscoveredAddress, 0)
+	gap := uint32(0)
+	for addressIndex := uint32(0); gap < gapLimit && addressIndex < bip32.FirstHardenedChild; addressIndex++ {
+		found, err := w.discoverAddress(mKey, password, purpose, coinType, org, _account, change, addressIndex, history, api)
+		if err != nil {
+			return nil, err
+		}
+		if found == nil {
+			gap++
+			continue
+		}
+		gap = 0
+		discovered = append(discovered, found)
+	}
+	return discovered, nil
+}
+
+// 派生地址并判断是否存在交易记录，存在时记录到钱包中
+func (w *Wallet) discoverAddress(mKey *bip32.Key, password string, purpose, coinType, org, _account, change, addressIndex uint32, history HistoryFunc, api ChainAPI) (*DiscoveredAddress, error) {
+	keyPath, err := buildChildKeyPath(purpose, coinType, org, _account, change, addressIndex)
+	if err != nil {
+		return nil, err
+	}
+	if w.WatchOnly {
+		addr, pubKey, err := w.deriveWatchAddress(keyPath, api)
+		if err != nil {
+			return nil, err
+		}
+		used, err := history(addr, api)
+		if err != nil {
+			return nil, fmt.Errorf("wallet DiscoverAccounts history err:%v", err.Error())
+		}
+		if !used {
+			return nil, nil
+		}
+		w.recordWatchAccount(purpose, coinType, org, addr, keyPath, pubKey, api)
+		return newDiscoveredAddress(addr, keyPath, _account, change, addressIndex), nil
+	}
+
+	childKeyPath, err := parseChildKeyPath(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := w.deriveKey(mKey, childKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("wallet DiscoverAccounts deriveKey err:%v", err.Error())
+	}
+	defer key.Zero()
+	pubKey, err := api.GetPubKeyFromPriKey(key.Key)
+	if err != nil {
+		return nil, fmt.Errorf("wallet DiscoverAccounts getPubKeyFromPriKey err:%v", err.Error())
+	}
+	addr, err := getAddressFromPubKey(api, pubKey, purpose)
+	if err != nil {
+		return nil, err
+	}
+	used, err := history(addr, api)
+	if err != nil {
+		return nil, fmt.Errorf("wallet DiscoverAccounts history err:%v", err.Error())
+	}
+	if !used {
+		return nil, nil
+	}
+	// 与CreateAccount一致，IsSaveSubKey时才保存子私钥
+	saveKey := key
+	if !w.IsSaveSubKey {
+		saveKey = nil
+	}
+	if err := w.recordAccount(purpose, coinType, org, addr, keyPath, saveKey, pubKey, password, api); err != nil {
+		return nil, err
+	}
+	return newDiscoveredAddress(addr, keyPath, _account, change, addressIndex), nil
+}
+
+func newDiscoveredAddress(addr, keyPath string, _account, change, addressIndex uint32) *DiscoveredAddress {
+	return &DiscoveredAddress{
+		Address:      addr,
+		KeyPath:      keyPath,
+		Account:      _account - bip32.FirstHardenedChild,
+		Change:       change,
+		AddressIndex: addressIndex,
+	}
+}
+
+// 只读钱包是否有账户对应的扩展公钥，强化派生的层级需要被扩展公钥覆盖
+func (w *Wallet) canDeriveWatchAccount(purpose, coinType, org, _account uint32) bool {
+	keyPath, err := buildChildKeyPath(purpose, coinType, org, _account, 0, 0)
+	if err != nil {
+		return false
+	}
+	childKeyPath, err := parseChildKeyPath(keyPath)
+	if err != nil {
+		return false
+	}
+	depth, _, ok := w.findXpub(childKeyPath)
+	return ok && depth >= len(childKeyPath)-2
+}
+
+// FixtureHistory 基于本地地址列表的交易记录，用于测试及离线恢复
+type FixtureHistory map[string]bool
+
+// NewFixtureHistory 使用已使用的地址列表创建FixtureHistory
+func NewFixtureHistory(addresses ...string) FixtureHistory {
+	fixture := make(FixtureHistory, len(addresses))
+	for _, addr := range addresses {
+		fixture[addr] = true
+	}
+	return fixture
+}
+
+// LoadFixtureHistory 从json文件中加载已使用的地址列表，文件内容为地址数组
+func LoadFixtureHistory(path string) (FixtureHistory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadFixtureHistory read file err:%v", err.Error())
+	}
+	var addresses []string
+	if err := json.Unmarshal(data, &addresses); err != nil {
+		return nil, fmt.Errorf("LoadFixtureHistory json.Unmarshal err:%v", err.Error())
+	}
+	return NewFixtureHistory(addresses...), nil
+}
+
+// HasHistory 地址是否在列表中，可作为HistoryFunc使用
+func (f FixtureHistory) HasHistory(address string, api ChainAPI) (bool, error) {
+	return f[address], nil
+}
@@ -0,0 +1,83 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/crypto/scrypt"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestWallet_DiscoverAccounts(t *testing.T) {
+
+	store := NewMemoryStorage()
+	wallet, err := LoadWalletFromMnemonicWithStorage(store, "w1", "123456", testMnemonic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 账户0外部链：0、3、22（间隔未超过gap limit），50超出gap limit
+	// 账户0找零链：0；账户1外部链：1；账户2无交易记录
+	fixture, err := LoadFixtureHistory("testdata/discovery_history.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	discovered, err := wallet.DiscoverAccounts(bip44.Purpose, bip32.ParseHDNum(1), 0, 0, fixture.HasHistory, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/44/1/0/0/0", "/44/1/0/0/3", "/44/1/0/0/22", "/44/1/0/1/0", "/44/1/1/0/1"}
+	if len(discovered) != len(want) {
+		t.Fatalf("discovered=%d, want %d", len(discovered), len(want))
+	}
+	for i, d := range discovered {
+		if d.KeyPath != want[i] {
+			t.Fatalf("discovered[%d]=%s, want %s", i, d.KeyPath, want[i])
+		}
+		if !fixture[d.Address] {
+			t.Fatalf("discovered address %s not in fixture", d.Address)
+		}
+	}
+
+	// 重新加载钱包，已使用的地址都已记录，并可以直接签名
+	loaded, err := LoadWalletFromStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := loaded.ListAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != len(want) {
+		t.Fatalf("accounts=%d, want %d", len(accounts), len(want))
+	}
+	// 未设置IsSaveSubKey时不保存子私钥
+	for pubKeyStr, info := range loaded.ChildKeyInfo {
+		if info.Key != nil {
+			t.Fatalf("sub key of %s should not be saved", pubKeyStr)
+		}
+	}
+	last := discovered[len(discovered)-1]
+	if _, err := loaded.Sign(last.Address, last.KeyPath, scrypt.Keccak256([]byte("hello")), api); err != nil {
+		t.Fatal(err)
+	}
+
+	// gap limit较小时，索引3之后的地址无法被发现
+	wallet2, err := LoadWalletFromMnemonicWithStorage(NewMemoryStorage(), "w2", "123456", testMnemonic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	discovered, err = wallet2.DiscoverAccounts(bip44.Purpose, bip32.ParseHDNum(1), 0, 2, fixture.HasHistory, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(discovered) != 3 || discovered[1].KeyPath != "/44/1/0/1/0" || discovered[2].KeyPath != "/44/1/1/0/1" {
+		t.Fatalf("discovered=%d with gap limit 2, want 3", len(discovered))
+	}
+}
@@ -0,0 +1,124 @@
+// https://electrum.readthedocs.io/en/latest/seedphrase.html
+package electrum
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/chain5j/keybox/bip32"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SeedType is the version of an Electrum v2 seed.
+type SeedType string
+
+const (
+	SeedTypeStandard  SeedType = "standard"
+	SeedTypeSegwit    SeedType = "segwit"
+	SeedType2FA       SeedType = "2fa"
+	SeedType2FASegwit SeedType = "2fa_segwit"
+)
+
+// seedPrefixes are the hex prefixes of HMAC-SHA512("Seed version", seed).
+var seedPrefixes = []struct {
+	seedType SeedType
+	prefix   string
+}{
+	{SeedTypeStandard, "01"},
+	{SeedTypeSegwit, "100"},
+	{SeedType2FA, "101"},
+	{SeedType2FASegwit, "102"},
+}
+
+const (
+	seedVersionKey   = "Seed version"
+	seedSaltPrefix   = "electrum"
+	seedIterations   = 2048
+	seedLength       = 64
+	segwitAccountIdx = bip32.FirstHardenedChild
+)
+
+var ErrNotElectrumSeed = errors.New("Not an electrum v2 seed")
+
+// Normalize normalizes the seed as Electrum does: NFKD, lower case, without
+// accents, single spaces and no spaces between CJK characters.
+func Normalize(seed string) string {
+	seed = strings.ToLower(norm.NFKD.String(seed))
+	seed = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, seed)
+	words := strings.Fields(seed)
+	var b strings.Builder
+	for i, w := range words {
+		if i > 0 && !(isCJK(lastRune(words[i-1])) && isCJK(firstRune(w))) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func firstRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return 0
+}
+
+func lastRune(s string) rune {
+	r := []rune(s)
+	if len(r) == 0 {
+		return 0
+	}
+	return r[len(r)-1]
+}
+
+// SeedTypeOf returns the version of the Electrum v2 seed, or
+// ErrNotElectrumSeed if the seed has no known version.
+func SeedTypeOf(seed string) (SeedType, error) {
+	normalized := Normalize(seed)
+	if normalized == "" {
+		return "", ErrNotElectrumSeed
+	}
+	mac := hmac.New(sha512.New, []byte(seedVersionKey))
+	mac.Write([]byte(normalized))
+	version := hex.EncodeToString(mac.Sum(nil))
+	for _, p := range seedPrefixes {
+		if strings.HasPrefix(version, p.prefix) {
+			return p.seedType, nil
+		}
+	}
+	return "", ErrNotElectrumSeed
+}
+
+// NewSeed derives the bip32 seed of the Electrum seed and passphrase.
+func NewSeed(seed string, passphrase string) []byte {
+	return pbkdf2.Key([]byte(Normalize(seed)), []byte(seedSaltPrefix+Normalize(passphrase)), seedIterations, seedLength, sha512.New)
+}
+
+// AccountPath returns the Electrum derivation path of the account:
+// m for standard seeds, m/0' for segwit seeds.
+func AccountPath(seedType SeedType) bip32.DerivationPath {
+	if seedType == SeedTypeSegwit || seedType == SeedType2FASegwit {
+		return bip32.DerivationPath{segwitAccountIdx}
+	}
+	return bip32.DerivationPath{}
+}
+
+// AddressPath returns the Electrum derivation path of the address,
+// change is 0 for receiving addresses and 1 for change addresses.
+func AddressPath(seedType SeedType, change, index uint32) bip32.DerivationPath {
+	return append(AccountPath(seedType), change, index)
+}
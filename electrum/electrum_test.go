@@ -0,0 +1,83 @@
+package electrum
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+)
+
+// vectors of electrum/tests/test_mnemonic.py
+func TestNewSeed(t *testing.T) {
+	for _, tc := range []struct {
+		seed       string
+		passphrase string
+		seedType   SeedType
+		bip32Seed  string
+	}{
+		{
+			seed:      "wild father tree among universe such mobile favorite target dynamic credit identify",
+			seedType:  SeedTypeSegwit,
+			bip32Seed: "aac2a6302e48577ab4b46f23dbae0774e2e62c796f797d0a1b5faeb528301e3064342dafb79069e7c4c6b8c38ae11d7a973bec0d4f70626f8cc5184a8d0b0756",
+		},
+		{
+			seed:       "wild father tree among universe such mobile favorite target dynamic credit identify",
+			passphrase: "Did you ever hear the tragedy of Darth Plagueis the Wise?",
+			seedType:   SeedTypeSegwit,
+			bip32Seed:  "4aa29f2aeb0127efb55138ab9e7be83b36750358751906f86c662b21a1ea1370f949e6d1a12fa56d3d93cadda93038c76ac8118597364e46f5156fde6183c82f",
+		},
+	} {
+		seedType, err := SeedTypeOf(tc.seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seedType != tc.seedType {
+			t.Fatalf("seed type got %s, want %s", seedType, tc.seedType)
+		}
+		if got := hex.EncodeToString(NewSeed(tc.seed, tc.passphrase)); got != tc.bip32Seed {
+			t.Fatalf("bip32 seed got %s, want %s", got, tc.bip32Seed)
+		}
+	}
+
+	if _, err := SeedTypeOf("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"); err != ErrNotElectrumSeed {
+		t.Fatal("bip39 mnemonic should not be an electrum seed")
+	}
+	if _, err := SeedTypeOf(" "); err != ErrNotElectrumSeed {
+		t.Fatal("empty seed should not be an electrum seed")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		in, out string
+	}{
+		{"  Wild\tFATHER \n tree ", "wild father tree"},
+		{"café ña", "cafe na"},
+		{"あい う　え", "あいうえ"},
+		{"漢字 abc 漢字", "漢字 abc 漢字"},
+	} {
+		if got := Normalize(tc.in); got != tc.out {
+			t.Fatalf("Normalize(%q) got %q, want %q", tc.in, got, tc.out)
+		}
+	}
+	// the version and the seed use the normalized seed
+	seed := "wild father tree among universe such mobile favorite target dynamic credit identify"
+	if seedType, err := SeedTypeOf(" Wild  FATHER tree among universe such mobile favorite target dynamic credit identify"); err != nil || seedType != SeedTypeSegwit {
+		t.Fatal("seed type should be detected after normalization")
+	}
+	if hex.EncodeToString(NewSeed("WILD "+seed[5:], "")) != hex.EncodeToString(NewSeed(seed, "")) {
+		t.Fatal("seed should be derived from the normalized mnemonic")
+	}
+}
+
+func TestAddressPath(t *testing.T) {
+	if got := AddressPath(SeedTypeStandard, 1, 5).String(); got != "m/1/5" {
+		t.Fatalf("standard address path got %s", got)
+	}
+	if got := AddressPath(SeedTypeSegwit, 0, 2); got.String() != "m/0'/0/2" || !got.IsHardened(0) {
+		t.Fatalf("segwit address path got %s", got)
+	}
+	if len(AccountPath(SeedTypeStandard)) != 0 || AccountPath(SeedType2FASegwit)[0] != bip32.FirstHardenedChild {
+		t.Fatal("account path error")
+	}
+}
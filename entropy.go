@@ -0,0 +1,143 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/chain5j/keybox/bip39"
+)
+
+// DefaultWordCount 默认的助记词单词数
+const DefaultWordCount = 12
+
+// ErrInsufficientEntropy 用户提供的投掷次数不足
+var ErrInsufficientEntropy = errors.New("insufficient entropy")
+
+// EntropyBitSize 助记词单词数对应的熵位数，单词数为12,15,18,21,24
+func EntropyBitSize(wordCount int) (int, error) {
+	switch wordCount {
+	case 12, 15, 18, 21, 24:
+		// 单词数*11 = 熵位数 + 熵位数/32
+		return wordCount * 32 / 3, nil
+	default:
+		return 0, fmt.Errorf("mnemonic word count %d is invalid, the values is 12,15,18,21,24", wordCount)
+	}
+}
+
+// EntropyFromHex 解析16进制的熵，长度需为16~32字节且为4的倍数
+func EntropyFromHex(s string) ([]byte, error) {
+	entropy, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("EntropyFromHex hex.DecodeString err:%v", err.Error())
+	}
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return nil, bip39.ErrEntropyLengthInvalid
+	}
+	return entropy, nil
+}
+
+// EntropyFromDice 使用骰子的点数生成熵，点数为1~sides
+// 为避免偏差，只使用不超过sides的最大2的幂以内的点数，每次得到log2位，其余点数丢弃
+// 如六面骰只使用1~4，每次2位；多余的点数不使用
+func EntropyFromDice(rolls []int, sides int, wordCount int) ([]byte, error) {
+	if sides < 2 {
+		return nil, fmt.Errorf("dice sides %d is invalid", sides)
+	}
+	bitSize, err := EntropyBitSize(wordCount)
+	if err != nil {
+		return nil, err
+	}
+	rollBits := bits.Len(uint(sides)) - 1
+	w := newBitWriter(bitSize)
+	for i, roll := range rolls {
+		if roll < 1 || roll > sides {
+			return nil, fmt.Errorf("dice roll %d at %d is invalid, should be 1~%d", roll, i, sides)
+		}
+		if roll > 1<<rollBits {
+			continue
+		}
+		w.write(uint(roll-1), rollBits)
+	}
+	return w.entropy()
+}
+
+// EntropyFromCoins 使用硬币的正反面生成熵，每次投掷得到1位
+// flips 由0/1或H/T（正面/反面）组成，忽略空白字符
+func EntropyFromCoins(flips string, wordCount int) ([]byte, error) {
+	bitSize, err := EntropyBitSize(wordCount)
+	if err != nil {
+		return nil, err
+	}
+	w := newBitWriter(bitSize)
+	for i, c := range strings.Join(strings.Fields(flips), "") {
+		switch c {
+		case '1', 'H', 'h':
+			w.write(1, 1)
+		case '0', 'T', 't':
+			w.write(0, 1)
+		default:
+			return nil, fmt.Errorf("coin flip %q at %d is invalid, should be 0/1 or H/T", c, i)
+		}
+	}
+	return w.entropy()
+}
+
+// 新建钱包使用的熵
+func newWalletEntropy(opts *WalletOptions) ([]byte, error) {
+	if len(opts.Entropy) == 0 {
+		wordCount := opts.WordCount
+		if wordCount == 0 {
+			wordCount = DefaultWordCount
+		}
+		bitSize, err := EntropyBitSize(wordCount)
+		if err != nil {
+			return nil, err
+		}
+		return bip39.NewEntropy(bitSize)
+	}
+	if opts.WordCount != 0 {
+		bitSize, err := EntropyBitSize(opts.WordCount)
+		if err != nil {
+			return nil, err
+		}
+		if len(opts.Entropy)*8 != bitSize {
+			return nil, fmt.Errorf("entropy has %d bits, %d words need %d bits", len(opts.Entropy)*8, opts.WordCount, bitSize)
+		}
+	}
+	return append([]byte(nil), opts.Entropy...), nil
+}
+
+// 按位写入熵，写满后忽略
+type bitWriter struct {
+	buf  []byte
+	size int // 需要的位数
+	n    int // 已写入的位数
+}
+
+func newBitWriter(bitSize int) *bitWriter {
+	return &bitWriter{buf: make([]byte, bitSize/8), size: bitSize}
+}
+
+// 写入v的低count位，高位在前
+func (w *bitWriter) write(v uint, count int) {
+	for i := count - 1; i >= 0 && w.n < w.size; i-- {
+		if (v>>uint(i))&1 == 1 {
+			w.buf[w.n/8] |= 0x80 >> uint(w.n%8)
+		}
+		w.n++
+	}
+}
+
+func (w *bitWriter) entropy() ([]byte, error) {
+	if w.n < w.size {
+		return nil, fmt.Errorf("%w: need %d bits, got %d", ErrInsufficientEntropy, w.size, w.n)
+	}
+	return w.buf, nil
+}
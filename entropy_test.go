@@ -0,0 +1,106 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEntropyFromDice(t *testing.T) {
+	// 六面骰的5、6被丢弃，1~4分别为00、01、10、11
+	rolls := make([]int, 0, 128)
+	for i := 0; i < 64; i++ {
+		rolls = append(rolls, 1, 5, 4, 6)
+	}
+	entropy, err := EntropyFromDice(rolls, 6, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(entropy, bytes.Repeat([]byte{0x33}, 16)) {
+		t.Fatalf("entropy=%x", entropy)
+	}
+	// 二十面骰使用1~16，每次4位
+	entropy, err = EntropyFromDice([]int{16, 1, 20, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1, 16, 1}, 20, 12)
+	if err != nil || !bytes.Equal(entropy, bytes.Repeat([]byte{0xf0}, 16)) {
+		t.Fatalf("entropy=%x err=%v", entropy, err)
+	}
+	if _, err := EntropyFromDice(rolls[:100], 6, 24); !errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("err=%v, want ErrInsufficientEntropy", err)
+	}
+	if _, err := EntropyFromDice([]int{7}, 6, 12); err == nil {
+		t.Fatal("roll out of range should fail")
+	}
+	if _, err := EntropyFromDice(rolls, 6, 13); err == nil {
+		t.Fatal("invalid word count should fail")
+	}
+}
+
+func TestEntropyFromCoins(t *testing.T) {
+	entropy, err := EntropyFromCoins(strings.Repeat("HTTT 0001 ", 20), 15)
+	if err != nil || !bytes.Equal(entropy, bytes.Repeat([]byte{0x81}, 20)) {
+		t.Fatalf("entropy=%x err=%v", entropy, err)
+	}
+	if _, err := EntropyFromCoins("HT", 12); !errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("err=%v, want ErrInsufficientEntropy", err)
+	}
+	if _, err := EntropyFromCoins(strings.Repeat("X", 128), 12); err == nil {
+		t.Fatal("invalid flip should fail")
+	}
+}
+
+func TestNewWalletWithOptions(t *testing.T) {
+	for _, wordCount := range []int{0, 12, 15, 18, 21, 24} {
+		wallet, err := NewWalletWithStorageAndOptions(NewMemoryStorage(), "w1", "123456", &WalletOptions{WordCount: wordCount})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := wordCount
+		if want == 0 {
+			want = DefaultWordCount
+		}
+		if got := len(strings.Fields(wallet.ExportMasterMnemonic())); got != want {
+			t.Fatalf("word count=%d, want %d", got, want)
+		}
+	}
+
+	// 使用外部提供的熵，助记词可预先计算用于确认
+	entropy, err := EntropyFromHex("0x7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mnemonic, err := MnemonicCodec(MnemonicType_English).NewMnemonic(entropy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mnemonic != "legal winner thank year wave sausage worth useful legal winner thank yellow" {
+		t.Fatalf("mnemonic=%s", mnemonic)
+	}
+	wallet, err := NewWalletWithStorageAndOptions(NewMemoryStorage(), "w1", "123456", &WalletOptions{MnemonicType: MnemonicType_English, Entropy: entropy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wallet.ExportMasterMnemonic() != mnemonic {
+		t.Fatalf("mnemonic=%s", wallet.ExportMasterMnemonic())
+	}
+	if !bytes.Equal(entropy, bytes.Repeat([]byte{0x7f}, 16)) {
+		t.Fatal("caller's entropy should not be zeroed")
+	}
+
+	for _, opts := range []*WalletOptions{
+		{WordCount: 13},
+		{WordCount: 24, Entropy: entropy},
+		{Entropy: entropy[:15]},
+	} {
+		if _, err := NewWalletWithStorageAndOptions(NewMemoryStorage(), "w1", "123456", opts); err == nil {
+			t.Fatalf("opts=%+v should be invalid", opts)
+		}
+	}
+	if _, err := EntropyFromHex("7f7f"); err == nil {
+		t.Fatal("short entropy should be invalid")
+	}
+}
@@ -14,7 +14,10 @@ require (
 	github.com/pborman/uuid v1.2.1
 	github.com/spf13/cobra v1.8.1
 	github.com/tjfoc/gmsm v1.4.1
+	go.etcd.io/bbolt v1.3.10
 	golang.org/x/crypto v0.29.0
+	golang.org/x/sys v0.27.0
+	golang.org/x/text v0.20.0
 )
 
 require (
@@ -25,5 +28,4 @@ require (
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.27.0 // indirect
 )
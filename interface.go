@@ -4,7 +4,10 @@
 // @date: 2020/8/6 0006
 package keybox
 
-import "github.com/chain5j/keybox/algorithm"
+import (
+	"github.com/chain5j/keybox/algorithm"
+	"github.com/chain5j/keybox/chain"
+)
 
 type ChainAPI interface {
 	algorithm.AlgorithmAPI
@@ -13,3 +16,34 @@ type ChainAPI interface {
 	GetAddressFromPubKey(pubKey []byte) (string, error)                    // 通过公钥获取地址
 	SignToStr(priKey []byte, hash []byte) (string, error)                  // 签名直接返回签名的string
 }
+
+// NetworkAPI 链的网络类型，为可选接口，实现后账户元数据会记录网络类型
+type NetworkAPI interface {
+	NetworkType() chain.NetworkType
+}
+
+// TxDecodeAPI 从未签名交易中解析签名内容及交易信息，为可选接口
+// 实现后可使用Wallet.SignTx，签名内容与策略校验的交易信息由同一笔交易得到
+type TxDecodeAPI interface {
+	DecodeTx(rawTx []byte) (hash []byte, tx *TxInfo, err error)
+}
+
+// CompressedAddressAPI 使用压缩公钥生成默认地址的链，为可选接口
+// 如BTC的P2PKH地址默认使用非压缩公钥，Electrum标准种子的账户使用压缩公钥
+type CompressedAddressAPI interface {
+	GetAddressFromCompressedPubKey(pubKey []byte) (string, error)
+}
+
+// PurposeAddressAPI 按路径的purpose生成不同类型地址的链，为可选接口
+// 如BTC的purpose为44'、49'、84'、86'时分别生成P2PKH、P2SH-P2WPKH、P2WPKH、P2TR地址
+type PurposeAddressAPI interface {
+	GetAddressFromPubKeyWithPurpose(pubKey []byte, purpose uint32) (string, error)
+}
+
+// 通过公钥获取地址，链实现PurposeAddressAPI时按purpose生成地址
+func getAddressFromPubKey(api ChainAPI, pubKey []byte, purpose uint32) (string, error) {
+	if p, ok := api.(PurposeAddressAPI); ok {
+		return p.GetAddressFromPubKeyWithPurpose(pubKey, purpose)
+	}
+	return api.GetAddressFromPubKey(pubKey)
+}
@@ -0,0 +1,349 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrWalletNotFound  = errors.New("wallet not found")
+	ErrAccountNotFound = errors.New("account not found")
+)
+
+// KeyringAccount 密钥环中的账户索引
+type KeyringAccount struct {
+	Address   string `json:"address"`   // 账户地址
+	ChainType uint32 `json:"chainType"` // 链类型
+	PubKey    string `json:"pubKey"`    // 公钥
+	Wallet    string `json:"wallet"`    // 所属钱包名称
+}
+
+// Keyring 管理存储后端中的多个钱包
+// 钱包可以独立解锁和锁定，账户索引在钱包锁定后依然保留，签名及导出会根据地址路由到对应的钱包
+type Keyring struct {
+	mu       sync.RWMutex
+	store    Storage
+	wallets  map[string]*Wallet         // 已打开的钱包
+	accounts map[string]*KeyringAccount // 账户索引，key为chainType+address
+	byWallet map[string]map[string]bool // 钱包对应的账户索引key
+}
+
+// NewKeyring 基于存储后端创建密钥环
+func NewKeyring(store Storage) *Keyring {
+	return &Keyring{
+		store:    store,
+		wallets:  make(map[string]*Wallet),
+		accounts: make(map[string]*KeyringAccount),
+		byWallet: make(map[string]map[string]bool),
+	}
+}
+
+// NewKeyringFromDir 基于目录创建密钥环，目录下的每个文件为一个钱包
+func NewKeyringFromDir(dir string) *Keyring {
+	return NewKeyring(NewFileStorage(dir))
+}
+
+// Storage 密钥环使用的存储后端
+func (k *Keyring) Storage() Storage {
+	return k.store
+}
+
+// ListWallets 列出存储后端中的所有钱包
+func (k *Keyring) ListWallets() ([]string, error) {
+	return k.store.List()
+}
+
+// CreateWallet 在存储后端中创建新钱包，创建后钱包处于解锁状态
+func (k *Keyring) CreateWallet(name string, password string) (*Wallet, error) {
+	if _, err := k.store.Get(name); err == nil {
+		return nil, fmt.Errorf("keyring CreateWallet wallet %s already exists", name)
+	}
+	wallet, err := NewWalletWithStorage(k.store, name, password)
+	if err != nil {
+		return nil, err
+	}
+	k.addWallet(name, wallet)
+	return wallet, nil
+}
+
+// AddWallet 将已加载的钱包加入密钥环，钱包需要使用密钥环相同的存储后端
+func (k *Keyring) AddWallet(wallet *Wallet) error {
+	if nil == wallet {
+		return fmt.Errorf("keyring AddWallet wallet is nil")
+	}
+	if wallet.storage != k.store {
+		return fmt.Errorf("keyring AddWallet wallet storage mismatch")
+	}
+	k.addWallet(wallet.name, wallet)
+	return nil
+}
+
+// Unlock 使用密码解锁钱包，并建立账户索引
+// duration>0时，超过该时长后钱包会自动锁定
+func (k *Keyring) Unlock(name string, password string, duration time.Duration) error {
+	k.mu.RLock()
+	wallet, ok := k.wallets[name]
+	k.mu.RUnlock()
+	if ok {
+		if err := wallet.Unlock(password, duration); err != nil {
+			return err
+		}
+		k.addWallet(name, wallet)
+		return nil
+	}
+	wallet, err := LoadWalletFromStorage(k.store, name, password)
+	if err != nil {
+		return err
+	}
+	wallet.relockAfter(duration)
+	k.addWallet(name, wallet)
+	return nil
+}
+
+// UnlockAll 使用同一密码解锁所有钱包，返回解锁失败的钱包及原因
+func (k *Keyring) UnlockAll(password string, duration time.Duration) map[string]error {
+	names, err := k.store.List()
+	if err != nil {
+		return map[string]error{"": err}
+	}
+	failed := make(map[string]error)
+	for _, name := range names {
+		if k.IsUnlocked(name) {
+			continue
+		}
+		if err := k.Unlock(name, password, duration); err != nil {
+			failed[name] = err
+		}
+	}
+	return failed
+}
+
+// Lock 锁定钱包，账户索引依然保留
+func (k *Keyring) Lock(name string) {
+	k.mu.RLock()
+	wallet, ok := k.wallets[name]
+	k.mu.RUnlock()
+	if ok {
+		wallet.Lock()
+	}
+}
+
+// LockAll 锁定所有钱包
+func (k *Keyring) LockAll() {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, wallet := range k.wallets {
+		wallet.Lock()
+	}
+}
+
+// IsUnlocked 钱包是否已解锁
+func (k *Keyring) IsUnlocked(name string) bool {
+	k.mu.RLock()
+	wallet, ok := k.wallets[name]
+	k.mu.RUnlock()
+	return ok && !wallet.IsLocked()
+}
+
+// Wallet 获取已打开的钱包，钱包可能处于锁定状态
+func (k *Keyring) Wallet(name string) (*Wallet, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if wallet, ok := k.wallets[name]; ok {
+		return wallet, nil
+	}
+	return nil, ErrWalletNotFound
+}
+
+// Accounts 列出所有已索引的账户，按钱包及地址排序
+// 列出前重新扫描已打开的钱包，包含直接通过钱包创建的账户
+func (k *Keyring) Accounts() []*KeyringAccount {
+	k.refresh()
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	accounts := make([]*KeyringAccount, 0, len(k.accounts))
+	for _, account := range k.accounts {
+		a := *account
+		accounts = append(accounts, &a)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Wallet != accounts[j].Wallet {
+			return accounts[i].Wallet < accounts[j].Wallet
+		}
+		return accounts[i].Address < accounts[j].Address
+	})
+	return accounts
+}
+
+// FindAccount 通过地址及链类型查找账户
+// 索引中不存在时重新扫描已打开的钱包，账户可能是直接通过钱包创建的
+func (k *Keyring) FindAccount(address string, chainType uint32) (*KeyringAccount, error) {
+	if account, ok := k.findAccount(address, chainType); ok {
+		return account, nil
+	}
+	k.refresh()
+	if account, ok := k.findAccount(address, chainType); ok {
+		return account, nil
+	}
+	return nil, ErrAccountNotFound
+}
+
+func (k *Keyring) findAccount(address string, chainType uint32) (*KeyringAccount, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	account, ok := k.accounts[keyringAccountKey(chainType, address)]
+	if !ok {
+		return nil, false
+	}
+	a := *account
+	return &a, true
+}
+
+// CreateAccount 在指定钱包中创建账户，并加入索引
+func (k *Keyring) CreateAccount(name string, purpose, coinType, org, _account, change, addressIndex uint32, api ChainAPI) (addr string, keyPath string, err error) {
+	wallet, err := k.Wallet(name)
+	if err != nil {
+		return "", "", err
+	}
+	addr, keyPath, err = wallet.CreateAccount(purpose, coinType, org, _account, change, addressIndex, api)
+	if err != nil {
+		return "", "", err
+	}
+	k.mu.Lock()
+	k.indexAccount(name, &KeyringAccount{
+		Address:   addr,
+		ChainType: api.ChainInfo().ChainType,
+		Wallet:    name,
+	})
+	k.mu.Unlock()
+	return addr, keyPath, nil
+}
+
+// Sign 根据地址路由到对应钱包进行签名
+func (k *Keyring) Sign(address, keyPath string, hash []byte, api ChainAPI) (string, error) {
+	wallet, err := k.walletOf(address, api)
+	if err != nil {
+		return "", err
+	}
+	return wallet.Sign(address, keyPath, hash, api)
+}
+
+// SignTx 根据地址路由到对应钱包，校验签名策略后签名未签名的交易
+func (k *Keyring) SignTx(address, keyPath string, rawTx []byte, api ChainAPI) (string, error) {
+	wallet, err := k.walletOf(address, api)
+	if err != nil {
+		return "", err
+	}
+	return wallet.SignTx(address, keyPath, rawTx, api)
+}
+
+// ExportRawKey 根据地址路由到对应钱包导出私钥
+func (k *Keyring) ExportRawKey(address, keyPath string, api ChainAPI) (string, error) {
+	wallet, err := k.walletOf(address, api)
+	if err != nil {
+		return "", err
+	}
+	return wallet.ExportRawKey(address, keyPath, api)
+}
+
+// ExportExtendedKey 根据地址路由到对应钱包导出扩展私钥
+func (k *Keyring) ExportExtendedKey(address, keyPath string, api ChainAPI) (string, error) {
+	wallet, err := k.walletOf(address, api)
+	if err != nil {
+		return "", err
+	}
+	return wallet.ExportExtendedKey(address, keyPath, api)
+}
+
+// ExportKeyStore 根据地址路由到对应钱包导出keystore
+func (k *Keyring) ExportKeyStore(address, keyPath string, keystorePwd string, api ChainAPI) (string, error) {
+	wallet, err := k.walletOf(address, api)
+	if err != nil {
+		return "", err
+	}
+	return wallet.ExportKeyStore(address, keyPath, keystorePwd, api)
+}
+
+func (k *Keyring) walletOf(address string, api ChainAPI) (*Wallet, error) {
+	if nil == api {
+		return nil, fmt.Errorf("keyring chainApi is nil")
+	}
+	account, err := k.FindAccount(address, api.ChainInfo().ChainType)
+	if err != nil {
+		return nil, err
+	}
+	return k.Wallet(account.Wallet)
+}
+
+func (k *Keyring) addWallet(name string, wallet *Wallet) {
+	wallet.mu.RLock()
+	accounts := make([]*KeyringAccount, 0, len(wallet.AddrLinkPubkey))
+	for addr, pubKey := range wallet.AddrLinkPubkey {
+		account := &KeyringAccount{
+			Address: addr,
+			PubKey:  pubKey,
+			Wallet:  name,
+		}
+		if info := wallet.ChildKeyInfo[pubKey]; info != nil {
+			account.ChainType = info.ChainType
+		}
+		accounts = append(accounts, account)
+	}
+	wallet.mu.RUnlock()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.wallets[name] = wallet
+	// 删除钱包原有的索引，钱包重新加载后不存在的账户不再保留
+	for key := range k.byWallet[name] {
+		if account, ok := k.accounts[key]; ok && account.Wallet == name {
+			delete(k.accounts, key)
+		}
+	}
+	k.byWallet[name] = make(map[string]bool)
+	for _, account := range accounts {
+		k.indexAccount(name, account)
+	}
+}
+
+// 根据已打开钱包的AddrLinkPubkey重建账户索引
+func (k *Keyring) refresh() {
+	k.mu.RLock()
+	wallets := make(map[string]*Wallet, len(k.wallets))
+	for name, wallet := range k.wallets {
+		wallets[name] = wallet
+	}
+	k.mu.RUnlock()
+	for name, wallet := range wallets {
+		k.addWallet(name, wallet)
+	}
+}
+
+// 需要在k.mu锁定的情况下调用
+func (k *Keyring) indexAccount(name string, account *KeyringAccount) {
+	key := keyringAccountKey(account.ChainType, account.Address)
+	if old, ok := k.accounts[key]; ok {
+		if len(account.PubKey) == 0 {
+			account.PubKey = old.PubKey
+		}
+		if old.Wallet != name {
+			delete(k.byWallet[old.Wallet], key)
+		}
+	}
+	k.accounts[key] = account
+	if _, ok := k.byWallet[name]; !ok {
+		k.byWallet[name] = make(map[string]bool)
+	}
+	k.byWallet[name][key] = true
+}
+
+func keyringAccountKey(chainType uint32, address string) string {
+	return fmt.Sprintf("%08x:%s", chainType, address)
+}
@@ -0,0 +1,161 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/crypto/scrypt"
+)
+
+func TestKeyring(t *testing.T) {
+	dir := t.TempDir()
+	keyring := NewKeyringFromDir(dir)
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+
+	w1, err := keyring.CreateWallet("w1", "pwd1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w1.SetIsSaveSubKey(true)
+	w2, err := keyring.CreateWallet("w2", "pwd2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w2.SetIsSaveSubKey(true)
+	if _, err := keyring.CreateWallet("w1", "pwd1"); err == nil {
+		t.Fatalf("create existing wallet should fail")
+	}
+
+	addr1, path1, err := keyring.CreateAccount("w1", bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr2, path2, err := keyring.CreateAccount("w2", bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr1 == addr2 {
+		t.Fatalf("different wallets should derive different addresses")
+	}
+
+	// 重新打开目录，账户索引在解锁后重建
+	keyring = NewKeyringFromDir(dir)
+	names, err := keyring.ListWallets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("wallets=%v, want 2", names)
+	}
+	if failed := keyring.UnlockAll("pwd1", 0); len(failed) != 1 || failed["w2"] == nil {
+		t.Fatalf("unlock all failed=%v, want only w2", failed)
+	}
+	if err := keyring.Unlock("w2", "pwd2", 0); err != nil {
+		t.Fatal(err)
+	}
+	if accounts := keyring.Accounts(); len(accounts) != 2 {
+		t.Fatalf("accounts=%d, want 2", len(accounts))
+	}
+	account, err := keyring.FindAccount(addr2, api.ChainInfo().ChainType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Wallet != "w2" {
+		t.Fatalf("account wallet=%s, want w2", account.Wallet)
+	}
+	if _, err := keyring.FindAccount(addr2, bip32.ParseHDNum(2)); err != ErrAccountNotFound {
+		t.Fatalf("find account on other chain err=%v, want ErrAccountNotFound", err)
+	}
+
+	hash := scrypt.Keccak256([]byte("hello"))
+	if _, err := keyring.Sign(addr1, path1, hash, api); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keyring.ExportRawKey(addr2, path2, api); err != nil {
+		t.Fatal(err)
+	}
+
+	keyring.Lock("w1")
+	if _, err := keyring.Sign(addr1, path1, hash, api); err != ErrWalletLocked {
+		t.Fatalf("sign on locked wallet err=%v, want ErrWalletLocked", err)
+	}
+	if _, err := keyring.Sign(addr2, path2, hash, api); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keyring.FindAccount(addr1, api.ChainInfo().ChainType); err != nil {
+		t.Fatalf("locked wallet accounts should stay indexed: %v", err)
+	}
+}
+
+func TestKeyring_RefreshIndex(t *testing.T) {
+	keyring := NewKeyring(NewMemoryStorage())
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	w1, err := keyring.CreateWallet("w1", "pwd1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 直接通过钱包创建的账户，查找时重新扫描钱包建立索引
+	addr, keyPath, err := w1.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := keyring.FindAccount(addr, api.ChainInfo().ChainType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Wallet != "w1" || account.PubKey == "" {
+		t.Fatalf("account=%+v", account)
+	}
+	if _, err := keyring.Sign(addr, keyPath, scrypt.Keccak256([]byte("hello")), api); err != nil {
+		t.Fatal(err)
+	}
+
+	derived, err := w1.DeriveAddresses("m/44'/1'/0'/0/*", 1, 2, true, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accounts := keyring.Accounts(); len(accounts) != 1+len(derived) {
+		t.Fatalf("accounts=%d, want %d", len(accounts), 1+len(derived))
+	}
+	if _, err := keyring.FindAccount("unknown", api.ChainInfo().ChainType); err != ErrAccountNotFound {
+		t.Fatalf("err=%v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestKeyring_ReplaceWallet(t *testing.T) {
+	store := NewMemoryStorage()
+	keyring := NewKeyring(store)
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	w1, err := keyring.CreateWallet("w1", "pwd1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := LoadWalletFromStorage(store, "w1", "pwd1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _, err := w1.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accounts := keyring.Accounts(); len(accounts) != 1 {
+		t.Fatalf("accounts=%d, want 1", len(accounts))
+	}
+
+	// 替换为不包含该账户的钱包实例后，原有的索引被删除
+	if err := keyring.AddWallet(stale); err != nil {
+		t.Fatal(err)
+	}
+	if accounts := keyring.Accounts(); len(accounts) != 0 {
+		t.Fatalf("accounts=%d after replace, want 0", len(accounts))
+	}
+	if _, err := keyring.FindAccount(addr, api.ChainInfo().ChainType); err != ErrAccountNotFound {
+		t.Fatalf("err=%v, want ErrAccountNotFound", err)
+	}
+}
@@ -0,0 +1,101 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip39"
+	"github.com/chain5j/keybox/crypto/secret"
+)
+
+// 按单词表中匹配单词最多的类型确定助记词的类型
+func guessMnemonicType(mnemonic string) MnemonicType {
+	words := strings.Fields(mnemonic)
+	best, bestCount := MnemonicType_English, 0
+	for _, t := range mnemonicTypes {
+		count := 0
+		for _, w := range words {
+			if _, ok := mnemonicCodecs[t].WordIndex(w); ok {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = t, count
+		}
+	}
+	return best
+}
+
+// SuggestMnemonicWords 返回助记词中不在单词表中的单词最相近的max个单词，key为单词的位置
+func SuggestMnemonicWords(mnemonic string, max int) map[int][]string {
+	codec := MnemonicCodec(guessMnemonicType(mnemonic))
+	suggestions := make(map[int][]string)
+	for i, w := range strings.Fields(mnemonic) {
+		if _, ok := codec.WordIndex(w); !ok && w != bip39.UnknownWord {
+			suggestions[i] = codec.SuggestWords(w, max)
+		}
+	}
+	return suggestions
+}
+
+// RecoverMnemonic 恢复输错、缺失或错位一个单词的助记词，未知的单词使用?表示
+// address为空时返回所有满足校验和的候选助记词
+// address不为空时，使用passphrase生成种子及钱包创建时的曲线curve（为空时使用p256），只返回keyPath派生地址与address相同的候选助记词
+func RecoverMnemonic(mnemonic string, passphrase string, curve bip32.Curve, keyPath string, address string, api ChainAPI) ([]string, error) {
+	candidates, err := MnemonicCodec(guessMnemonicType(mnemonic)).RecoverCandidates(mnemonic)
+	if err != nil {
+		return nil, fmt.Errorf("RecoverMnemonic err:%v", err.Error())
+	}
+	if address == "" {
+		return candidates, nil
+	}
+	if nil == api {
+		return nil, fmt.Errorf("RecoverMnemonic api is nil")
+	}
+	curve, err = bip32.ParseCurve(string(curve))
+	if err != nil {
+		return nil, fmt.Errorf("RecoverMnemonic curve err:%v", err.Error())
+	}
+	childKeyPath, err := parseChildKeyPath(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]string, 0)
+	for _, candidate := range candidates {
+		addr, err := mnemonicAddress(candidate, passphrase, curve, childKeyPath, api)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(addr, address) {
+			matched = append(matched, candidate)
+		}
+	}
+	return matched, nil
+}
+
+// 使用助记词派生childKeyPath的地址
+func mnemonicAddress(mnemonic string, passphrase string, curve bip32.Curve, childKeyPath bip32.DerivationPath, api ChainAPI) (string, error) {
+	seed := bip39.NewSeed(mnemonic, passphrase)
+	defer secret.Zero(seed)
+	mKey, err := bip32.NewMasterKeyWithCurve(seed, curve)
+	if err != nil {
+		return "", fmt.Errorf("RecoverMnemonic bip32.NewMasterKey err:%v", err.Error())
+	}
+	defer mKey.Zero()
+	key, err := mKey.DerivePath(childKeyPath)
+	if err != nil {
+		return "", fmt.Errorf("RecoverMnemonic DerivePath err:%v", err.Error())
+	}
+	defer key.Zero()
+	pubKey, err := api.GetPubKeyFromPriKey(key.Key)
+	if err != nil {
+		return "", fmt.Errorf("RecoverMnemonic getPubKeyFromPriKey err:%v", err.Error())
+	}
+	purpose, _, _ := childKeyPathProperties(childKeyPath)
+	return getAddressFromPubKey(api, pubKey, purpose)
+}
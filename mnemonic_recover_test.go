@@ -0,0 +1,50 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+)
+
+func TestRecoverMnemonic(t *testing.T) {
+	wallet, err := LoadWalletFromMnemonicWithStorage(NewMemoryStorage(), "w1", "123456", testMnemonic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lost := strings.Replace(testMnemonic, "about", "?", 1)
+	candidates, err := RecoverMnemonic(lost, "", "", "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) < 2 {
+		t.Fatalf("candidates=%d", len(candidates))
+	}
+	// 使用已知地址筛选候选助记词
+	matched, err := RecoverMnemonic(lost, "", bip32.CurveP256, keyPath, addr, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matched) != 1 || matched[0] != testMnemonic {
+		t.Fatalf("matched=%v", matched)
+	}
+	if matched, err := RecoverMnemonic(lost, "wrong", bip32.CurveP256, keyPath, addr, api); err != nil || len(matched) != 0 {
+		t.Fatalf("matched=%v err=%v", matched, err)
+	}
+
+	suggestions := SuggestMnemonicWords(strings.Replace(testMnemonic, "about", "abuot", 1), 3)
+	if len(suggestions) != 1 || suggestions[11][0] != "about" {
+		t.Fatalf("suggestions=%v", suggestions)
+	}
+}
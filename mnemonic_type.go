@@ -4,6 +4,11 @@
 // @date: 2020/8/18 0018
 package keybox
 
+import (
+	"github.com/chain5j/keybox/bip39"
+	"github.com/chain5j/keybox/bip39/wordlists"
+)
+
 const (
 	MnemonicType_Chinese_Simplified  MnemonicType = "chinese_simplified"
 	MnemonicType_Chinese_Traditional MnemonicType = "chinese_traditional"
@@ -39,3 +44,64 @@ func ParseMnemonicType(t string) MnemonicType {
 		return MnemonicType_English
 	}
 }
+
+// 助记词类型，检测助记词语言时按该顺序匹配
+var mnemonicTypes = []MnemonicType{
+	MnemonicType_English,
+	MnemonicType_Chinese_Simplified,
+	MnemonicType_Chinese_Traditional,
+	MnemonicType_French,
+	MnemonicType_Italian,
+	MnemonicType_Japanese,
+	MnemonicType_Korean,
+	MnemonicType_Spanish,
+}
+
+// 助记词类型对应的单词表，未知类型使用English
+func mnemonicWordList(mnemonicType MnemonicType) []string {
+	switch mnemonicType {
+	case MnemonicType_Chinese_Simplified:
+		return wordlists.ChineseSimplified
+	case MnemonicType_Chinese_Traditional:
+		return wordlists.ChineseTraditional
+	case MnemonicType_French:
+		return wordlists.French
+	case MnemonicType_Italian:
+		return wordlists.Italian
+	case MnemonicType_Japanese:
+		return wordlists.Japanese
+	case MnemonicType_Korean:
+		return wordlists.Korean
+	case MnemonicType_Spanish:
+		return wordlists.Spanish
+	default:
+		return wordlists.English
+	}
+}
+
+// 各类型助记词的编解码器，创建后只读，可并发使用
+var mnemonicCodecs = func() map[MnemonicType]*bip39.Mnemonic {
+	codecs := make(map[MnemonicType]*bip39.Mnemonic, len(mnemonicTypes))
+	for _, t := range mnemonicTypes {
+		codecs[t] = bip39.NewMnemonicCodec(mnemonicWordList(t))
+	}
+	return codecs
+}()
+
+// MnemonicCodec 获取助记词类型对应的编解码器，未知类型使用English
+func MnemonicCodec(mnemonicType MnemonicType) *bip39.Mnemonic {
+	if codec, ok := mnemonicCodecs[mnemonicType]; ok {
+		return codec
+	}
+	return mnemonicCodecs[MnemonicType_English]
+}
+
+// DetectMnemonicType 检测助记词的类型，按mnemonicTypes的顺序匹配单词表及校验和
+func DetectMnemonicType(mnemonic string) (MnemonicType, error) {
+	for _, t := range mnemonicTypes {
+		if _, err := mnemonicCodecs[t].EntropyFromMnemonic(mnemonic); err == nil {
+			return t, nil
+		}
+	}
+	return "", bip39.ErrInvalidMnemonic
+}
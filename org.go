@@ -0,0 +1,220 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/chain"
+)
+
+var (
+	ErrOrgNotFound     = errors.New("organization not found")
+	ErrOrgExists       = errors.New("organization already exists")
+	ErrOrgChainDenied  = errors.New("chain is not allowed for the organization")
+	ErrOrgIndexInvalid = errors.New("organization index should more than the 0x80000000")
+)
+
+// Organization Purpose45布局（m/45'/coin'/org'/account'/change/index）中的组织
+// 一个主私钥可以为多个组织（租户）派生相互隔离的子树
+type Organization struct {
+	Name       string   `json:"name"`                 // 组织名称
+	Index      uint32   `json:"index"`                // 组织在路径中的索引（强化派生）
+	Chains     []string `json:"chains,omitempty"`     // 允许使用的链名称，为空时不限制
+	CreateTime int64    `json:"createTime,omitempty"` // 创建时间
+}
+
+func (o *Organization) copy() *Organization {
+	c := *o
+	c.Chains = append([]string(nil), o.Chains...)
+	return &c
+}
+
+// 是否允许使用该链，链名称不区分大小写
+func (o *Organization) allowChain(chainName string) bool {
+	if len(o.Chains) == 0 {
+		return true
+	}
+	for _, c := range o.Chains {
+		if strings.EqualFold(c, chainName) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateOrg 创建组织
+// index 组织在路径中的索引，需大于等于0x80000000；为0时使用已有组织的最大索引+1
+// chains 允许使用的链名称，为空时不限制
+func (w *Wallet) CreateOrg(name string, index uint32, chains []string) (*Organization, error) {
+	if name == "" {
+		return nil, fmt.Errorf("wallet CreateOrg name is empty")
+	}
+	if index != 0 && index < bip32.FirstHardenedChild {
+		return nil, ErrOrgIndexInvalid
+	}
+	w.mu.Lock()
+	if _, ok := w.Orgs[name]; ok {
+		w.mu.Unlock()
+		return nil, ErrOrgExists
+	}
+	if index == 0 {
+		index = bip32.FirstHardenedChild
+		for _, o := range w.Orgs {
+			if o.Index >= index {
+				index = o.Index + 1
+			}
+		}
+		if index < bip32.FirstHardenedChild {
+			w.mu.Unlock()
+			return nil, ErrOrgIndexInvalid
+		}
+	} else if o := w.orgByIndexLocked(index); o != nil {
+		w.mu.Unlock()
+		return nil, fmt.Errorf("wallet CreateOrg index %x is used by %s", index, o.Name)
+	}
+	if w.Orgs == nil {
+		w.Orgs = make(map[string]*Organization)
+	}
+	org := &Organization{
+		Name:       name,
+		Index:      index,
+		Chains:     append([]string(nil), chains...),
+		CreateTime: time.Now().Unix(),
+	}
+	w.Orgs[name] = org
+	result := org.copy()
+	w.mu.Unlock()
+	if err := w.save(); err != nil {
+		w.mu.Lock()
+		delete(w.Orgs, name)
+		w.mu.Unlock()
+		return nil, fmt.Errorf("wallet CreateOrg storage.Put err:%v", err.Error())
+	}
+	return result, nil
+}
+
+// GetOrg 获取组织
+func (w *Wallet) GetOrg(name string) (*Organization, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	org, ok := w.Orgs[name]
+	if !ok {
+		return nil, ErrOrgNotFound
+	}
+	return org.copy(), nil
+}
+
+// ListOrgs 列出全部组织，按索引排序
+func (w *Wallet) ListOrgs() []*Organization {
+	w.mu.RLock()
+	result := make([]*Organization, 0, len(w.Orgs))
+	for _, org := range w.Orgs {
+		result = append(result, org.copy())
+	}
+	w.mu.RUnlock()
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Index < result[j].Index
+	})
+	return result
+}
+
+// SetOrgChains 设置组织允许使用的链名称，为空时不限制
+func (w *Wallet) SetOrgChains(name string, chains []string) error {
+	w.mu.Lock()
+	org, ok := w.Orgs[name]
+	if !ok {
+		w.mu.Unlock()
+		return ErrOrgNotFound
+	}
+	org.Chains = append([]string(nil), chains...)
+	w.mu.Unlock()
+	if err := w.save(); err != nil {
+		return fmt.Errorf("wallet SetOrgChains storage.Put err:%v", err.Error())
+	}
+	return nil
+}
+
+// CreateOrgAccount 在组织的子树下创建账户，m/45'/coin'/org'/account'/change/index
+func (w *Wallet) CreateOrgAccount(name string, coinType, _account, change, addressIndex uint32, api ChainAPI) (addr string, keyPath string, err error) {
+	org, err := w.orgForChain(name, api)
+	if err != nil {
+		return "", "", err
+	}
+	return w.CreateAccount(Purpose45, coinType, org.Index, _account, change, addressIndex, api)
+}
+
+// ListOrgAccounts 列出组织子树下的账户地址
+func (w *Wallet) ListOrgAccounts(name string) ([]string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	org, ok := w.Orgs[name]
+	if !ok {
+		return nil, ErrOrgNotFound
+	}
+	addresses := make([]string, 0)
+	for addr, pubKeyStr := range w.AddrLinkPubkey {
+		info := w.ChildKeyInfo[pubKeyStr]
+		if info != nil && info.Purpose == Purpose45 && info.Org == org.Index {
+			addresses = append(addresses, addr)
+		}
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+// ExportOrgExtendedKey 导出组织账户层级（m/45'/coin'/org'/account'）的扩展密钥，
+// 扩展公钥可交给组织创建只读钱包，只能派生该账户下的地址
+// 与ExportAccountExtendedKey相同，默认p256创建的钱包导出s256链的扩展密钥时返回ErrCurveMismatch
+func (w *Wallet) ExportOrgExtendedKey(name string, coinType, _account uint32, isPrivate bool, networkType chain.NetworkType, api ChainAPI) (keyPath string, extendedKey string, err error) {
+	org, err := w.orgForChain(name, api)
+	if err != nil {
+		return "", "", err
+	}
+	return w.ExportAccountExtendedKey(Purpose45, coinType, org.Index, _account, isPrivate, networkType, api)
+}
+
+// 获取组织，并校验组织是否允许使用该链
+func (w *Wallet) orgForChain(name string, api ChainAPI) (*Organization, error) {
+	if api == nil {
+		return nil, fmt.Errorf("wallet org chainApi is nil")
+	}
+	org, err := w.GetOrg(name)
+	if err != nil {
+		return nil, err
+	}
+	if !org.allowChain(api.ChainInfo().ChainName) {
+		return nil, ErrOrgChainDenied
+	}
+	return org, nil
+}
+
+// 已注册的组织按索引校验是否允许使用该链，未注册的组织不限制
+func (w *Wallet) checkOrgChain(purpose, org uint32, api ChainAPI) error {
+	if purpose != Purpose45 {
+		return nil
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if o := w.orgByIndexLocked(org); o != nil && !o.allowChain(api.ChainInfo().ChainName) {
+		return ErrOrgChainDenied
+	}
+	return nil
+}
+
+// 需要在w.mu锁定的情况下调用
+func (w *Wallet) orgByIndexLocked(index uint32) *Organization {
+	for _, o := range w.Orgs {
+		if o.Index == index {
+			return o
+		}
+	}
+	return nil
+}
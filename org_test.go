@@ -0,0 +1,162 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/chain"
+)
+
+func TestWallet_Orgs(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api1 := newTestChain("T1", bip32.ParseHDNum(1))
+	api2 := newTestChain("T2", bip32.ParseHDNum(2))
+
+	alice, err := wallet.CreateOrg("alice", 0, []string{"t1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.Index != bip32.ParseHDNum(0) {
+		t.Fatalf("alice index got %x", alice.Index)
+	}
+	bob, err := wallet.CreateOrg("bob", bip32.ParseHDNum(5), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	carol, err := wallet.CreateOrg("carol", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if carol.Index != bob.Index+1 {
+		t.Fatalf("carol index got %x", carol.Index)
+	}
+	if _, err := wallet.CreateOrg("alice", 0, nil); err != ErrOrgExists {
+		t.Fatal("duplicated org name should fail")
+	}
+	if _, err := wallet.CreateOrg("dave", bob.Index, nil); err == nil {
+		t.Fatal("duplicated org index should fail")
+	}
+	if _, err := wallet.CreateOrg("dave", 5, nil); err != ErrOrgIndexInvalid {
+		t.Fatal("not hardened org index should fail")
+	}
+	orgs := wallet.ListOrgs()
+	if len(orgs) != 3 || orgs[0].Name != "alice" || orgs[1].Name != "bob" || orgs[2].Name != "carol" {
+		t.Fatalf("orgs=%+v", orgs)
+	}
+
+	// 组织只能使用允许的链
+	addr1, keyPath1, err := wallet.CreateOrgAccount("alice", bip32.ParseHDNum(1), bip32.ParseHDNum(0), 0, 0, api1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyPath1 != "/45/1/0/0/0/0" {
+		t.Fatalf("keyPath got %s", keyPath1)
+	}
+	if _, _, err := wallet.CreateOrgAccount("alice", bip32.ParseHDNum(2), bip32.ParseHDNum(0), 0, 0, api2); err != ErrOrgChainDenied {
+		t.Fatal("chain not allowed should fail")
+	}
+	if _, _, err := wallet.CreateAccountByPath("m/45'/2'/0'/0'/0/1", api2); err != ErrOrgChainDenied {
+		t.Fatal("chain not allowed should fail by path")
+	}
+	addr2, _, err := wallet.CreateOrgAccount("bob", bip32.ParseHDNum(2), bip32.ParseHDNum(0), 0, 0, api2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := wallet.CreateOrgAccount("nobody", bip32.ParseHDNum(1), bip32.ParseHDNum(0), 0, 0, api1); err != ErrOrgNotFound {
+		t.Fatal("unknown org should fail")
+	}
+	accounts, err := wallet.ListOrgAccounts("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || accounts[0] != addr1 {
+		t.Fatalf("alice accounts=%v", accounts)
+	}
+
+	// 组织的扩展公钥创建的只读钱包派生相同的地址
+	xpubPath, xpub, err := wallet.ExportOrgExtendedKey("bob", bip32.ParseHDNum(2), bip32.ParseHDNum(0), false, chain.MainNet, api2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watch, err := NewWatchOnlyWalletWithStorage(NewMemoryStorage(), "bob", "123456", map[string]string{xpubPath: xpub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	watchAddr, _, err := watch.CreateAccount(Purpose45, bip32.ParseHDNum(2), bob.Index, bip32.ParseHDNum(0), 0, 0, api2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watchAddr != addr2 {
+		t.Fatalf("watch-only address got %s, want %s", watchAddr, addr2)
+	}
+
+	// 组织保存在钱包中，修改允许的链后生效
+	if err := wallet.SetOrgChains("alice", nil); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadWalletFromStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.ListOrgs()) != 3 {
+		t.Fatal("orgs should be saved")
+	}
+	if _, _, err := loaded.CreateOrgAccount("alice", bip32.ParseHDNum(2), bip32.ParseHDNum(0), 0, 0, api2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWallet_ExportOrgXpubDefaultCurve(t *testing.T) {
+	// 默认选项创建的钱包使用p256，可以向组织委派p256链的扩展公钥
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.CreateOrg("alice", 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	keyPath, xpub, err := wallet.ExportOrgExtendedKey("alice", 0, bip32.ParseHDNum(0), false, chain.MainNet, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watch, err := NewWatchOnlyWalletWithStorage(NewMemoryStorage(), "watch", "654321", map[string]string{keyPath: xpub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _, err := wallet.CreateOrgAccount("alice", 0, bip32.ParseHDNum(0), 0, 2, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _, err := watch.CreateAccountByPath(keyPath+"/0/2", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != want {
+		t.Fatalf("watch-only org addr=%s, want %s", addr, want)
+	}
+
+	// s256链需要使用secp256k1创建的钱包
+	s256Api := newS256TestChain("ETH", bip32.ParseHDNum(60))
+	if _, _, err := wallet.ExportOrgExtendedKey("alice", 0, bip32.ParseHDNum(0), false, chain.MainNet, s256Api); err != ErrCurveMismatch {
+		t.Fatalf("default wallet export s256 org xpub err=%v, want ErrCurveMismatch", err)
+	}
+	wallet, err = NewWalletWithStorageAndOptions(NewMemoryStorage(), "w2", "123456", &WalletOptions{Curve: bip32.CurveSecp256k1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.CreateOrg("alice", 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := wallet.ExportOrgExtendedKey("alice", 0, bip32.ParseHDNum(0), false, chain.MainNet, s256Api); err != nil {
+		t.Fatal(err)
+	}
+}
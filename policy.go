@@ -0,0 +1,435 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 策略拒绝签名的原因
+var (
+	ErrPolicyTxRequired  = errors.New("policy requires the transaction info")
+	ErrPolicyDestination = errors.New("policy denies the destination address")
+	ErrPolicyTxLimit     = errors.New("policy per-transaction limit exceeded")
+	ErrPolicyDailyLimit  = errors.New("policy daily limit exceeded")
+	ErrPolicyChain       = errors.New("policy denies the chain")
+	ErrPolicyNetwork     = errors.New("policy denies the network")
+	ErrPolicyMethod      = errors.New("policy denies the contract method")
+	ErrPolicyTimeWindow  = errors.New("policy denies signing at this time")
+)
+
+// 日限额统计的时长
+const policyDailyPeriod = 24 * time.Hour
+
+// PolicyError 策略拒绝签名的错误，可使用errors.Is判断拒绝的原因
+type PolicyError struct {
+	Address string // 签名的地址
+	Reason  error  // 拒绝的原因，为ErrPolicy*之一
+	Detail  string // 详细说明
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("address %s %s: %s", e.Address, e.Reason.Error(), e.Detail)
+}
+
+func (e *PolicyError) Unwrap() error {
+	return e.Reason
+}
+
+// TxInfo 签名对应的交易信息，由链的TxDecodeAPI从未签名交易中解析
+type TxInfo struct {
+	To      string     // 目标地址
+	Value   *big.Int   // 转账金额，使用链的最小单位（如wei、satoshi）
+	Data    []byte     // 合约调用数据，ETH的前4个字节为方法选择器
+	Outputs []TxOutput // 有多个输出的交易（如BTC），不为空时忽略To及Value
+}
+
+// TxOutput 交易的输出，转回签名地址的输出视为找零，不校验目标地址也不计入金额
+type TxOutput struct {
+	To    string   // 目标地址
+	Value *big.Int // 金额，使用链的最小单位
+}
+
+// TimeWindow 允许签名的时间段，开始时间晚于结束时间时跨越午夜，如22:00~06:00，星期按开始的日期计算
+type TimeWindow struct {
+	Weekdays []time.Weekday `json:"weekdays,omitempty"` // 星期，0为星期日，为空时不限制
+	Start    string         `json:"start,omitempty"`    // 开始时间，如09:00，为空时为00:00
+	End      string         `json:"end,omitempty"`      // 结束时间（不包含），如18:00，为空时为24:00
+	Location string         `json:"location,omitempty"` // 时区，如Asia/Shanghai，为空时为UTC
+}
+
+// AccountPolicy 账户的签名策略，为空的规则不参与校验
+type AccountPolicy struct {
+	AllowTo       []string     `json:"allowTo,omitempty"`       // 允许的目标地址
+	DenyTo        []string     `json:"denyTo,omitempty"`        // 禁止的目标地址
+	MaxTxValue    string       `json:"maxTxValue,omitempty"`    // 单笔限额，10进制的最小单位
+	DailyLimit    string       `json:"dailyLimit,omitempty"`    // 24小时滚动限额，10进制的最小单位
+	AllowChains   []string     `json:"allowChains,omitempty"`   // 允许的链名称，不区分大小写
+	AllowNetworks []string     `json:"allowNetworks,omitempty"` // 允许的网络类型，链实现NetworkAPI时校验
+	AllowMethods  []string     `json:"allowMethods,omitempty"`  // 允许的合约方法选择器，如0xa9059cbb，data为空的转账不受限制
+	TimeWindows   []TimeWindow `json:"timeWindows,omitempty"`   // 允许签名的时间段，满足其一即可
+
+	maxTxValue *big.Int
+	dailyLimit *big.Int
+	windows    []timeWindow
+}
+
+// Policy 签名策略，账户未配置时使用默认策略，默认策略为空时不限制
+type Policy struct {
+	Default  *AccountPolicy            `json:"default,omitempty"`  // 默认策略
+	Accounts map[string]*AccountPolicy `json:"accounts,omitempty"` // 地址对应的策略，地址不区分大小写
+}
+
+type timeWindow struct {
+	weekdays   []time.Weekday
+	start, end int // 一天中的分钟数
+	location   *time.Location
+}
+
+// 已签名的金额
+type policySpend struct {
+	Time  int64    `json:"time"` // unix毫秒
+	Value *big.Int `json:"value"`
+}
+
+// PolicyEngine 签名前校验策略，记录日限额的使用情况
+type PolicyEngine struct {
+	mu        sync.Mutex
+	policy    *Policy
+	statePath string                    // 日限额使用情况的文件，为空时只保存在内存中
+	spends    map[string][]*policySpend // 地址对应的已签名金额
+	now       func() time.Time
+}
+
+// LoadPolicy 从JSON文件中加载签名策略
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadPolicy read file err:%v", err.Error())
+	}
+	policy := new(Policy)
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("LoadPolicy json.Unmarshal err:%v", err.Error())
+	}
+	return policy, nil
+}
+
+// NewPolicyEngine 创建策略引擎
+// statePath 日限额使用情况的保存文件，多次运行的进程（如walletctl）需要设置，为空时只保存在内存中
+func NewPolicyEngine(policy *Policy, statePath string) (*PolicyEngine, error) {
+	if policy == nil {
+		return nil, fmt.Errorf("NewPolicyEngine policy is nil")
+	}
+	if err := policy.Default.compile(); err != nil {
+		return nil, fmt.Errorf("NewPolicyEngine default policy err:%v", err.Error())
+	}
+	accounts := make(map[string]*AccountPolicy, len(policy.Accounts))
+	for addr, p := range policy.Accounts {
+		if err := p.compile(); err != nil {
+			return nil, fmt.Errorf("NewPolicyEngine policy of %s err:%v", addr, err.Error())
+		}
+		accounts[strings.ToLower(addr)] = p
+	}
+	e := &PolicyEngine{
+		policy:    &Policy{Default: policy.Default, Accounts: accounts},
+		statePath: statePath,
+		spends:    make(map[string][]*policySpend),
+		now:       time.Now,
+	}
+	if statePath != "" {
+		data, err := os.ReadFile(statePath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("NewPolicyEngine read state err:%v", err.Error())
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &e.spends); err != nil {
+				return nil, fmt.Errorf("NewPolicyEngine state json.Unmarshal err:%v", err.Error())
+			}
+		}
+	}
+	return e, nil
+}
+
+func (p *AccountPolicy) compile() (err error) {
+	if p == nil {
+		return nil
+	}
+	if p.maxTxValue, err = parsePolicyValue(p.MaxTxValue); err != nil {
+		return fmt.Errorf("maxTxValue err:%v", err.Error())
+	}
+	if p.dailyLimit, err = parsePolicyValue(p.DailyLimit); err != nil {
+		return fmt.Errorf("dailyLimit err:%v", err.Error())
+	}
+	for _, m := range p.AllowMethods {
+		if b, err := hex.DecodeString(strings.TrimPrefix(m, "0x")); err != nil || len(b) != 4 {
+			return fmt.Errorf("method selector %s is invalid", m)
+		}
+	}
+	p.windows = make([]timeWindow, 0, len(p.TimeWindows))
+	for _, tw := range p.TimeWindows {
+		w := timeWindow{weekdays: tw.Weekdays, end: 24 * 60, location: time.UTC}
+		if tw.Start != "" {
+			if w.start, err = parseClock(tw.Start); err != nil {
+				return err
+			}
+		}
+		if tw.End != "" {
+			if w.end, err = parseClock(tw.End); err != nil {
+				return err
+			}
+		}
+		if w.start == w.end {
+			return fmt.Errorf("time window %s~%s is empty", tw.Start, tw.End)
+		}
+		if tw.Location != "" {
+			if w.location, err = time.LoadLocation(tw.Location); err != nil {
+				return fmt.Errorf("time window location err:%v", err.Error())
+			}
+		}
+		p.windows = append(p.windows, w)
+	}
+	return nil
+}
+
+func parsePolicyValue(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("value %s is invalid", s)
+	}
+	return v, nil
+}
+
+// 解析HH:MM为一天中的分钟数
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		if s == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, fmt.Errorf("time %s is invalid", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (w *timeWindow) contains(now time.Time) bool {
+	now = now.In(w.location)
+	minute := now.Hour()*60 + now.Minute()
+	weekday := now.Weekday()
+	switch {
+	case w.start < w.end:
+		if minute < w.start || minute >= w.end {
+			return false
+		}
+	case minute >= w.start:
+		// 跨越午夜的时间段，午夜前
+	case minute < w.end:
+		// 跨越午夜的时间段，午夜后属于前一天开始的时间段
+		weekday = (weekday + 6) % 7
+	default:
+		return false
+	}
+	if len(w.weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.weekdays {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// 地址对应的策略，未配置时使用默认策略
+func (e *PolicyEngine) accountPolicy(address string) *AccountPolicy {
+	if p, ok := e.policy.Accounts[strings.ToLower(address)]; ok {
+		return p
+	}
+	return e.policy.Default
+}
+
+// 校验签名请求，通过时返回撤销函数，签名失败时调用以释放占用的日限额
+func (e *PolicyEngine) authorize(address string, tx *TxInfo, api ChainAPI) (func(), error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	p := e.accountPolicy(address)
+	if p == nil {
+		return func() {}, nil
+	}
+	deny := func(reason error, format string, args ...interface{}) (func(), error) {
+		return nil, &PolicyError{Address: address, Reason: reason, Detail: fmt.Sprintf(format, args...)}
+	}
+
+	now := e.now()
+	if len(p.windows) > 0 {
+		allowed := false
+		for i := range p.windows {
+			if p.windows[i].contains(now) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return deny(ErrPolicyTimeWindow, "%s is not in the time windows", now.Format(time.RFC3339))
+		}
+	}
+	chainName := api.ChainInfo().ChainName
+	if len(p.AllowChains) > 0 && !containsFold(p.AllowChains, chainName) {
+		return deny(ErrPolicyChain, "chain %s is not allowed", chainName)
+	}
+	if len(p.AllowNetworks) > 0 {
+		n, ok := api.(NetworkAPI)
+		if !ok || !containsFold(p.AllowNetworks, string(n.NetworkType())) {
+			network := ""
+			if ok {
+				network = string(n.NetworkType())
+			}
+			return deny(ErrPolicyNetwork, "network %s is not allowed", network)
+		}
+	}
+
+	needTx := len(p.AllowTo) > 0 || len(p.DenyTo) > 0 || p.maxTxValue != nil || p.dailyLimit != nil || len(p.AllowMethods) > 0
+	if !needTx {
+		return func() {}, nil
+	}
+	if tx == nil {
+		return deny(ErrPolicyTxRequired, "use SignTx with the unsigned transaction")
+	}
+	outputs := []TxOutput{{To: tx.To, Value: tx.Value}}
+	if len(tx.Outputs) > 0 {
+		outputs = outputs[:0]
+		for _, o := range tx.Outputs {
+			if !strings.EqualFold(o.To, address) {
+				outputs = append(outputs, o)
+			}
+		}
+	}
+	for _, o := range outputs {
+		if containsFold(p.DenyTo, o.To) {
+			return deny(ErrPolicyDestination, "destination %s is in the deny list", o.To)
+		}
+		if len(p.AllowTo) > 0 && !containsFold(p.AllowTo, o.To) {
+			return deny(ErrPolicyDestination, "destination %s is not in the allow list", o.To)
+		}
+	}
+	if len(p.AllowMethods) > 0 && len(tx.Data) > 0 {
+		if len(tx.Data) < 4 {
+			return deny(ErrPolicyMethod, "data is shorter than the method selector")
+		}
+		selector := "0x" + hex.EncodeToString(tx.Data[:4])
+		if !containsFold(p.AllowMethods, selector) {
+			return deny(ErrPolicyMethod, "method %s is not allowed", selector)
+		}
+	}
+	value := new(big.Int)
+	for _, o := range outputs {
+		if o.Value == nil {
+			continue
+		}
+		if o.Value.Sign() < 0 {
+			return deny(ErrPolicyTxLimit, "value %s is negative", o.Value)
+		}
+		value.Add(value, o.Value)
+	}
+	if p.maxTxValue != nil && value.Cmp(p.maxTxValue) > 0 {
+		return deny(ErrPolicyTxLimit, "value %s exceeds %s", value, p.maxTxValue)
+	}
+	if p.dailyLimit == nil {
+		return func() {}, nil
+	}
+
+	key := strings.ToLower(address)
+	since := now.Add(-policyDailyPeriod).UnixNano() / int64(time.Millisecond)
+	spends := e.spends[key][:0]
+	spent := new(big.Int)
+	for _, s := range e.spends[key] {
+		if s.Time > since {
+			spends = append(spends, s)
+			spent.Add(spent, s.Value)
+		}
+	}
+	if total := new(big.Int).Add(spent, value); total.Cmp(p.dailyLimit) > 0 {
+		e.spends[key] = spends
+		return deny(ErrPolicyDailyLimit, "spent %s in 24h, value %s exceeds %s", spent, value, p.dailyLimit)
+	}
+	spend := &policySpend{Time: now.UnixNano() / int64(time.Millisecond), Value: value}
+	e.spends[key] = append(spends, spend)
+	if err := e.saveLocked(); err != nil {
+		e.removeSpendLocked(key, spend)
+		return nil, err
+	}
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.removeSpendLocked(key, spend)
+		e.saveLocked()
+	}, nil
+}
+
+func (e *PolicyEngine) removeSpendLocked(key string, spend *policySpend) {
+	spends := e.spends[key]
+	for i, s := range spends {
+		if s == spend {
+			e.spends[key] = append(spends[:i], spends[i+1:]...)
+			return
+		}
+	}
+}
+
+// 保存日限额使用情况，需要在e.mu锁定的情况下调用
+func (e *PolicyEngine) saveLocked() error {
+	if e.statePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(e.spends)
+	if err != nil {
+		return err
+	}
+	tmp := e.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("policy state write err:%v", err.Error())
+	}
+	if err := os.Rename(tmp, e.statePath); err != nil {
+		return fmt.Errorf("policy state rename err:%v", err.Error())
+	}
+	return nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPolicy 设置钱包的签名策略，engine为nil时不限制
+func (w *Wallet) SetPolicy(engine *PolicyEngine) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.policy = engine
+}
+
+// 校验签名策略，未设置策略时直接通过
+func (w *Wallet) authorizeSign(address string, tx *TxInfo, api ChainAPI) (func(), error) {
+	w.mu.RLock()
+	engine := w.policy
+	w.mu.RUnlock()
+	if engine == nil {
+		return func() {}, nil
+	}
+	return engine.authorize(address, tx, api)
+}
@@ -0,0 +1,237 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/chain"
+)
+
+const testPolicy = `{
+	"default": {"allowChains": ["T1"]},
+	"accounts": {
+		"%s": {
+			"allowTo": ["0xAAAA", "0xBBBB"],
+			"denyTo": ["0xBBBB"],
+			"maxTxValue": "100",
+			"dailyLimit": "150",
+			"allowNetworks": ["testnet"],
+			"allowMethods": ["0xa9059cbb"],
+			"timeWindows": [{"weekdays": [1, 2, 3, 4, 5], "start": "09:00", "end": "18:00", "location": "UTC"}]
+		}
+	}
+}`
+
+// txTestChain 使用JSON编码的TxInfo作为未签名交易，记录签名时传入的hash
+type txTestChain struct {
+	*networkTestChain
+	hash []byte
+}
+
+func (c *txTestChain) DecodeTx(rawTx []byte) ([]byte, *TxInfo, error) {
+	tx := new(TxInfo)
+	if err := json.Unmarshal(rawTx, tx); err != nil {
+		return nil, nil, err
+	}
+	hash := sha256.Sum256(rawTx)
+	return hash[:], tx, nil
+}
+
+func (c *txTestChain) SignToStr(priKey []byte, hash []byte) (string, error) {
+	c.hash = hash
+	return c.testChain.SignToStr(priKey, hash)
+}
+
+func encodeTestTx(t *testing.T, tx *TxInfo) []byte {
+	rawTx, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rawTx
+}
+
+func TestWallet_SignPolicy(t *testing.T) {
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &txTestChain{networkTestChain: &networkTestChain{testChain: newTestChain("T1", bip32.ParseHDNum(1)), network: chain.TestNet}}
+	addr1, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr2, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 1, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	policyPath := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(policyPath, []byte(fmtPolicy(addr1)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadPolicy(policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statePath := filepath.Join(dir, "policy.state")
+	engine, err := NewPolicyEngine(policy, statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2026/10/19为星期一
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return now }
+	wallet.SetPolicy(engine)
+
+	hash := bytes.Repeat([]byte{1}, 32)
+	transfer := []byte{0xa9, 0x05, 0x9c, 0xbb, 0x00}
+	// 只有hash时无法校验交易相关的规则
+	if _, err := wallet.Sign(addr1, "", hash, api); !errors.Is(err, ErrPolicyTxRequired) {
+		t.Fatalf("err=%v, want ErrPolicyTxRequired", err)
+	}
+	for _, tc := range []struct {
+		tx   *TxInfo
+		want error
+	}{
+		{&TxInfo{To: "0xcccc"}, ErrPolicyDestination},
+		{&TxInfo{To: "0xbbbb"}, ErrPolicyDestination},
+		{&TxInfo{To: "0xaaaa", Value: big.NewInt(101)}, ErrPolicyTxLimit},
+		{&TxInfo{To: "0xaaaa", Data: []byte{1, 2, 3, 4}}, ErrPolicyMethod},
+		{&TxInfo{To: "0xaaaa", Value: big.NewInt(100), Data: transfer}, nil},
+		{&TxInfo{To: "0xaaaa", Value: big.NewInt(60)}, ErrPolicyDailyLimit},
+		{&TxInfo{To: "0xaaaa", Value: big.NewInt(50)}, nil},
+	} {
+		rawTx := encodeTestTx(t, tc.tx)
+		_, err := wallet.SignTx(addr1, "", rawTx, api)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("tx=%+v err=%v, want %v", tc.tx, err, tc.want)
+		}
+		var policyErr *PolicyError
+		if tc.want != nil && (!errors.As(err, &policyErr) || policyErr.Address != addr1) {
+			t.Fatalf("err=%v should be *PolicyError", err)
+		}
+		// 签名的hash由同一笔交易计算得到
+		if want := sha256.Sum256(rawTx); tc.want == nil && !bytes.Equal(api.hash, want[:]) {
+			t.Fatalf("signed hash=%x, want %x", api.hash, want)
+		}
+	}
+	if _, err := wallet.SignTx(addr1, "", []byte("not a tx"), api); err == nil {
+		t.Fatal("SignTx should fail for an invalid tx")
+	}
+	if _, err := wallet.SignTx(addr1, "", encodeTestTx(t, &TxInfo{To: "0xaaaa"}), api.networkTestChain); err == nil {
+		t.Fatal("SignTx should fail for a chain without TxDecodeAPI")
+	}
+
+	// 日限额按24小时滚动计算，并在进程重启后保留
+	engine, err = NewPolicyEngine(policy, statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(8 * time.Hour)
+	engine.now = func() time.Time { return now }
+	wallet.SetPolicy(engine)
+	if _, err := wallet.SignTx(addr1, "", encodeTestTx(t, &TxInfo{To: "0xaaaa", Value: big.NewInt(1)}), api); !errors.Is(err, ErrPolicyTimeWindow) {
+		t.Fatalf("err=%v, want ErrPolicyTimeWindow", err)
+	}
+	now = now.Add(15 * time.Hour)
+	if _, err := wallet.SignTx(addr1, "", encodeTestTx(t, &TxInfo{To: "0xaaaa", Value: big.NewInt(1)}), api); !errors.Is(err, ErrPolicyDailyLimit) {
+		t.Fatalf("err=%v, want ErrPolicyDailyLimit", err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := wallet.SignTx(addr1, "", encodeTestTx(t, &TxInfo{To: "0xaaaa", Value: big.NewInt(100)}), api); err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他账户使用默认策略
+	if _, err := wallet.Sign(addr2, "", hash, api); err != nil {
+		t.Fatal(err)
+	}
+	api2 := &networkTestChain{testChain: newTestChain("T2", bip32.ParseHDNum(1)), network: chain.TestNet}
+	if _, err := wallet.Sign(addr2, "", hash, api2); !errors.Is(err, ErrPolicyChain) {
+		t.Fatalf("err=%v, want ErrPolicyChain", err)
+	}
+	wallet.SetPolicy(nil)
+	if _, err := wallet.Sign(addr1, "", hash, api); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewPolicyEngine_Invalid(t *testing.T) {
+	for _, p := range []*AccountPolicy{
+		{MaxTxValue: "-1"},
+		{DailyLimit: "1e3"},
+		{AllowMethods: []string{"0xa9059c"}},
+		{TimeWindows: []TimeWindow{{Start: "25:00"}}},
+		{TimeWindows: []TimeWindow{{Location: "Mars/Base"}}},
+		{TimeWindows: []TimeWindow{{Start: "09:00", End: "09:00"}}},
+	} {
+		if _, err := NewPolicyEngine(&Policy{Default: p}, ""); err == nil {
+			t.Fatalf("policy %+v should be invalid", p)
+		}
+	}
+}
+
+func TestTimeWindow_Contains(t *testing.T) {
+	p := &AccountPolicy{TimeWindows: []TimeWindow{{Weekdays: []time.Weekday{time.Friday}, Start: "22:00", End: "06:00"}}}
+	if _, err := NewPolicyEngine(&Policy{Default: p}, ""); err != nil {
+		t.Fatal(err)
+	}
+	w := &p.windows[0]
+	for _, tc := range []struct {
+		now  string
+		want bool
+	}{
+		{"2026-10-16T22:30:00Z", true},  // 星期五晚上
+		{"2026-10-17T05:59:00Z", true},  // 星期六凌晨，属于星期五开始的时间段
+		{"2026-10-17T06:00:00Z", false}, // 结束时间不包含
+		{"2026-10-16T05:00:00Z", false}, // 星期五凌晨，属于星期四开始的时间段
+		{"2026-10-17T22:30:00Z", false}, // 星期六晚上
+		{"2026-10-16T12:00:00Z", false},
+	} {
+		now, _ := time.Parse(time.RFC3339, tc.now)
+		if w.contains(now) != tc.want {
+			t.Fatalf("%s contains=%v, want %v", tc.now, !tc.want, tc.want)
+		}
+	}
+}
+
+func fmtPolicy(addr string) string {
+	return strings.Replace(testPolicy, "%s", addr, 1)
+}
+
+func TestPolicyEngine_Outputs(t *testing.T) {
+	engine, err := NewPolicyEngine(&Policy{Default: &AccountPolicy{AllowTo: []string{"to1", "to2"}, MaxTxValue: "100"}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	for _, tc := range []struct {
+		outputs []TxOutput
+		want    error
+	}{
+		// 转回签名地址的找零不校验目标地址也不计入金额
+		{[]TxOutput{{"to1", big.NewInt(60)}, {"to2", big.NewInt(40)}, {"FROM", big.NewInt(1000)}}, nil},
+		{[]TxOutput{{"to1", big.NewInt(60)}, {"to2", big.NewInt(41)}}, ErrPolicyTxLimit},
+		{[]TxOutput{{"to1", big.NewInt(60)}, {"to3", big.NewInt(1)}}, ErrPolicyDestination},
+	} {
+		_, err := engine.authorize("from", &TxInfo{To: "ignored", Outputs: tc.outputs}, api)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("outputs=%v err=%v, want %v", tc.outputs, err, tc.want)
+		}
+	}
+}
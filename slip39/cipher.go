@@ -0,0 +1,65 @@
+package slip39
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// baseIterationCount is the total number of PBKDF2 iterations for iteration exponent 0.
+	baseIterationCount = 10000
+	// roundCount is the number of rounds of the Feistel cipher.
+	roundCount = 4
+	// customizationString is the salt prefix and checksum customization of non-extendable shares.
+	customizationString = "shamir"
+	// customizationStringExtendable is the checksum customization of extendable shares.
+	customizationStringExtendable = "shamir_extendable"
+)
+
+// roundFunction is the round function of the Feistel cipher.
+func roundFunction(i int, passphrase []byte, e int, salt, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	s := append(append([]byte(nil), salt...), r...)
+	return pbkdf2.Key(password, s, (baseIterationCount<<uint(e))/roundCount, len(r), sha256.New)
+}
+
+// cipherSalt returns the salt of the Feistel cipher, extendable shares use an empty salt.
+func cipherSalt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return append([]byte(customizationString), byte(identifier>>8), byte(identifier))
+}
+
+// encrypt encrypts the master secret with the passphrase.
+func encrypt(masterSecret, passphrase []byte, e int, identifier uint16, extendable bool) []byte {
+	half := len(masterSecret) / 2
+	l := append([]byte(nil), masterSecret[:half]...)
+	r := append([]byte(nil), masterSecret[half:]...)
+	salt := cipherSalt(identifier, extendable)
+	for i := 0; i < roundCount; i++ {
+		l, r = r, xor(l, roundFunction(i, passphrase, e, salt, r))
+	}
+	return append(r, l...)
+}
+
+// decrypt decrypts the encrypted master secret with the passphrase.
+func decrypt(encryptedSecret, passphrase []byte, e int, identifier uint16, extendable bool) []byte {
+	half := len(encryptedSecret) / 2
+	l := append([]byte(nil), encryptedSecret[:half]...)
+	r := append([]byte(nil), encryptedSecret[half:]...)
+	salt := cipherSalt(identifier, extendable)
+	for i := roundCount - 1; i >= 0; i-- {
+		l, r = r, xor(l, roundFunction(i, passphrase, e, salt, r))
+	}
+	return append(r, l...)
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package slip39
+
+// rs1024Generator is the generator of the RS1024 checksum.
+var rs1024Generator = [10]uint32{
+	0xE0E040,
+	0x1C1C080,
+	0x3838100,
+	0x7070200,
+	0xE0E0009,
+	0x1C0C2412,
+	0x38086C24,
+	0x3090FC48,
+	0x21B1F890,
+	0x3F3F120,
+}
+
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xFFFFF)<<10 ^ uint32(v)
+		for i := 0; i < 10; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= rs1024Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func customizationValues(cs string) []int {
+	values := make([]int, len(cs))
+	for i := 0; i < len(cs); i++ {
+		values[i] = int(cs[i])
+	}
+	return values
+}
+
+// rs1024CreateChecksum returns the 3 checksum words of the data.
+func rs1024CreateChecksum(data []int, cs string) []int {
+	values := append(customizationValues(cs), data...)
+	values = append(values, make([]int, checksumLengthWords)...)
+	polymod := rs1024Polymod(values) ^ 1
+	checksum := make([]int, checksumLengthWords)
+	for i := range checksum {
+		checksum[i] = int(polymod>>uint(radixBits*(checksumLengthWords-1-i))) & 1023
+	}
+	return checksum
+}
+
+// rs1024VerifyChecksum verifies the checksum at the end of the data.
+func rs1024VerifyChecksum(data []int, cs string) bool {
+	return rs1024Polymod(append(customizationValues(cs), data...)) == 1
+}
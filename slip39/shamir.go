@@ -0,0 +1,158 @@
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+const (
+	// maxShareCount is the maximum number of shares in a group and of groups.
+	maxShareCount = 16
+	// digestLength is the length of the digest of the shared secret in bytes.
+	digestLength = 4
+	// digestIndex is the index of the share containing the digest of the shared secret.
+	digestIndex = 254
+	// secretIndex is the index of the share containing the shared secret.
+	secretIndex = 255
+)
+
+// expTable and logTable are the exponent and logarithm tables of GF(256)
+// with the Rijndael polynomial x^8 + x^4 + x^3 + x + 1, generator 3.
+var expTable, logTable = func() (exp [255]byte, log [256]byte) {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		log[x] = byte(i)
+		// x * 3 = x * 2 + x
+		x2 := x << 1
+		if x&0x80 != 0 {
+			x2 ^= 0x1b
+		}
+		x ^= x2
+	}
+	return
+}()
+
+type rawShare struct {
+	x     byte
+	value []byte
+}
+
+// interpolate returns f(x) of the polynomial through the given shares,
+// all shares must have distinct x and values of the same length.
+func interpolate(shares []rawShare, x byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("slip39 interpolate requires at least one share")
+	}
+	length := len(shares[0].value)
+	for i, s := range shares {
+		if len(s.value) != length {
+			return nil, fmt.Errorf("slip39 interpolate shares have different lengths")
+		}
+		for _, o := range shares[:i] {
+			if o.x == s.x {
+				return nil, fmt.Errorf("slip39 interpolate shares have duplicate x %d", s.x)
+			}
+		}
+		if s.x == x {
+			return append([]byte(nil), s.value...), nil
+		}
+	}
+
+	// Lagrange interpolation in log space, log(0) is taken as 0 like the
+	// reference implementation, it cancels out for the share itself.
+	logProd := 0
+	for _, s := range shares {
+		logProd += int(logTable[s.x^x])
+	}
+	result := make([]byte, length)
+	for _, s := range shares {
+		logBasis := logProd - int(logTable[s.x^x])
+		for _, o := range shares {
+			logBasis -= int(logTable[s.x^o.x])
+		}
+		logBasis = ((logBasis % 255) + 255) % 255
+		for i, b := range s.value {
+			if b != 0 {
+				result[i] ^= expTable[(int(logTable[b])+logBasis)%255]
+			}
+		}
+	}
+	return result, nil
+}
+
+// createDigest returns the first 4 bytes of HMAC-SHA256(randomData, secret).
+func createDigest(randomData, secret []byte) []byte {
+	mac := hmac.New(sha256.New, randomData)
+	mac.Write(secret)
+	return mac.Sum(nil)[:digestLength]
+}
+
+// splitSecret splits the secret into n shares, any t of them recover the secret.
+func splitSecret(t, n int, secret []byte) ([]rawShare, error) {
+	if t < 1 {
+		return nil, fmt.Errorf("slip39 threshold must be a positive integer")
+	}
+	if t > n {
+		return nil, fmt.Errorf("slip39 threshold must not exceed the number of shares")
+	}
+	if n > maxShareCount {
+		return nil, fmt.Errorf("slip39 the number of shares must not exceed %d", maxShareCount)
+	}
+	if t == 1 {
+		shares := make([]rawShare, n)
+		for i := range shares {
+			shares[i] = rawShare{x: byte(i), value: append([]byte(nil), secret...)}
+		}
+		return shares, nil
+	}
+	if len(secret) < digestLength {
+		return nil, fmt.Errorf("slip39 secret is too short")
+	}
+
+	shares := make([]rawShare, 0, n)
+	for i := 0; i < t-2; i++ {
+		value := make([]byte, len(secret))
+		if _, err := rand.Read(value); err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{x: byte(i), value: value})
+	}
+	randomPart := make([]byte, len(secret)-digestLength)
+	if _, err := rand.Read(randomPart); err != nil {
+		return nil, err
+	}
+	digest := append(createDigest(randomPart, secret), randomPart...)
+	base := append(append([]rawShare(nil), shares...),
+		rawShare{x: digestIndex, value: digest},
+		rawShare{x: secretIndex, value: secret})
+	for i := t - 2; i < n; i++ {
+		value, err := interpolate(base, byte(i))
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{x: byte(i), value: value})
+	}
+	return shares, nil
+}
+
+// recoverSecret recovers the secret from t shares and checks its digest.
+func recoverSecret(t int, shares []rawShare) ([]byte, error) {
+	if t == 1 {
+		return append([]byte(nil), shares[0].value...), nil
+	}
+	secret, err := interpolate(shares, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := interpolate(shares, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(digest[:digestLength], createDigest(digest[digestLength:], secret)) {
+		return nil, ErrInvalidDigest
+	}
+	return secret, nil
+}
@@ -0,0 +1,316 @@
+// https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+package slip39
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+const (
+	// radixBits is the number of bits per word.
+	radixBits = 10
+	// idLengthBits is the length of the random identifier in bits.
+	idLengthBits = 15
+	// maxIterationExponent is the maximum iteration exponent.
+	maxIterationExponent = 15
+	// checksumLengthWords is the length of the RS1024 checksum in words.
+	checksumLengthWords = 3
+	// metadataLengthWords is the number of words other than the share value.
+	metadataLengthWords = 4 + checksumLengthWords
+	// minStrengthBits is the minimum allowed length of the master secret in bits.
+	minStrengthBits = 128
+	// minMnemonicLengthWords is the minimum length of a mnemonic in words.
+	minMnemonicLengthWords = metadataLengthWords + (minStrengthBits+radixBits-1)/radixBits
+)
+
+var (
+	// ErrInvalidMnemonic is returned when a mnemonic is malformed.
+	ErrInvalidMnemonic = errors.New("invalid slip39 mnemonic")
+	// ErrInvalidChecksum is returned when the RS1024 checksum of a mnemonic is incorrect.
+	ErrInvalidChecksum = errors.New("invalid slip39 mnemonic checksum")
+	// ErrInvalidDigest is returned when the recovered secret does not match its digest.
+	ErrInvalidDigest = errors.New("invalid slip39 shared secret digest")
+	// ErrInsufficientShares is returned when there are not enough shares to recover the secret.
+	ErrInsufficientShares = errors.New("insufficient slip39 mnemonics")
+	// ErrMismatchedShares is returned when the mnemonics do not belong to the same secret.
+	ErrMismatchedShares = errors.New("slip39 mnemonics do not belong to the same secret")
+)
+
+// Share is a decoded SLIP-0039 share.
+type Share struct {
+	Identifier        uint16 // random identifier shared by all shares of a secret
+	Extendable        bool   // whether more shares can be created later with the same identifier
+	IterationExponent int    // PBKDF2 iterations are 10000 << IterationExponent
+	GroupIndex        int    // index of the group, from 0
+	GroupThreshold    int    // number of groups required to recover the secret
+	GroupCount        int    // total number of groups
+	MemberIndex       int    // index of the member in the group, from 0
+	MemberThreshold   int    // number of members required to recover the group secret
+	Value             []byte // share value
+}
+
+// MemberGroup is the configuration of a group of shares.
+type MemberGroup struct {
+	MemberThreshold int // number of members required to recover the group secret
+	MemberCount     int // total number of members
+}
+
+// GenerateMnemonics splits the master secret into mnemonic shares grouped by groups,
+// any groupThreshold groups with their member thresholds of shares recover the secret.
+// The master secret must be at least 128 bits and a multiple of 16 bits, the
+// passphrase may be empty and must consist of printable ASCII characters.
+func GenerateMnemonics(groupThreshold int, groups []MemberGroup, masterSecret, passphrase []byte, extendable bool, iterationExponent int) ([][]string, error) {
+	if len(masterSecret)*8 < minStrengthBits {
+		return nil, fmt.Errorf("slip39 the master secret must be at least %d bits", minStrengthBits)
+	}
+	if len(masterSecret)%2 != 0 {
+		return nil, fmt.Errorf("slip39 the length of the master secret must be a multiple of 16 bits")
+	}
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	if iterationExponent < 0 || iterationExponent > maxIterationExponent {
+		return nil, fmt.Errorf("slip39 the iteration exponent must be in [0, %d]", maxIterationExponent)
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) {
+		return nil, fmt.Errorf("slip39 the group threshold must be in [1, %d]", len(groups))
+	}
+	for _, g := range groups {
+		if g.MemberThreshold == 1 && g.MemberCount > 1 {
+			return nil, fmt.Errorf("slip39 a group with member threshold 1 must have exactly one member, use 1-of-1 instead")
+		}
+	}
+
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, err
+	}
+	identifier := binary.BigEndian.Uint16(idBytes[:]) & (1<<idLengthBits - 1)
+	encryptedSecret := encrypt(masterSecret, passphrase, iterationExponent, identifier, extendable)
+
+	groupShares, err := splitSecret(groupThreshold, len(groups), encryptedSecret)
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]string, len(groups))
+	for i, g := range groups {
+		memberShares, err := splitSecret(g.MemberThreshold, g.MemberCount, groupShares[i].value)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range memberShares {
+			share := &Share{
+				Identifier:        identifier,
+				Extendable:        extendable,
+				IterationExponent: iterationExponent,
+				GroupIndex:        int(groupShares[i].x),
+				GroupThreshold:    groupThreshold,
+				GroupCount:        len(groups),
+				MemberIndex:       int(m.x),
+				MemberThreshold:   g.MemberThreshold,
+				Value:             m.value,
+			}
+			mnemonic, err := share.Mnemonic()
+			if err != nil {
+				return nil, err
+			}
+			result[i] = append(result[i], mnemonic)
+		}
+	}
+	return result, nil
+}
+
+// CombineMnemonics recovers the master secret from the mnemonic shares.
+func CombineMnemonics(mnemonics []string, passphrase []byte) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, ErrInsufficientShares
+	}
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	shares := make([]*Share, 0, len(mnemonics))
+	for _, m := range mnemonics {
+		share, err := DecodeMnemonic(m)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	first := shares[0]
+	groups := make(map[int]map[int]*Share)
+	for _, s := range shares {
+		if s.Identifier != first.Identifier || s.Extendable != first.Extendable ||
+			s.IterationExponent != first.IterationExponent || s.GroupThreshold != first.GroupThreshold ||
+			s.GroupCount != first.GroupCount || len(s.Value) != len(first.Value) {
+			return nil, ErrMismatchedShares
+		}
+		members, ok := groups[s.GroupIndex]
+		if !ok {
+			members = make(map[int]*Share)
+			groups[s.GroupIndex] = members
+		}
+		for _, o := range members {
+			if o.MemberThreshold != s.MemberThreshold {
+				return nil, fmt.Errorf("%w: group %d has different member thresholds", ErrMismatchedShares, s.GroupIndex)
+			}
+			break
+		}
+		if o, ok := members[s.MemberIndex]; ok && string(o.Value) != string(s.Value) {
+			return nil, fmt.Errorf("%w: group %d member %d has different values", ErrMismatchedShares, s.GroupIndex, s.MemberIndex)
+		}
+		members[s.MemberIndex] = s
+	}
+
+	// recover the group shares of the complete groups
+	groupIndexes := make([]int, 0, len(groups))
+	for gi := range groups {
+		groupIndexes = append(groupIndexes, gi)
+	}
+	sort.Ints(groupIndexes)
+	groupShares := make([]rawShare, 0, first.GroupThreshold)
+	for _, gi := range groupIndexes {
+		if len(groupShares) == first.GroupThreshold {
+			break
+		}
+		members := groups[gi]
+		memberIndexes := make([]int, 0, len(members))
+		threshold := 0
+		for mi, s := range members {
+			memberIndexes = append(memberIndexes, mi)
+			threshold = s.MemberThreshold
+		}
+		if len(members) < threshold {
+			continue
+		}
+		sort.Ints(memberIndexes)
+		memberShares := make([]rawShare, 0, threshold)
+		for _, mi := range memberIndexes[:threshold] {
+			memberShares = append(memberShares, rawShare{x: byte(mi), value: members[mi].Value})
+		}
+		value, err := recoverSecret(threshold, memberShares)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, rawShare{x: byte(gi), value: value})
+	}
+	if len(groupShares) < first.GroupThreshold {
+		return nil, fmt.Errorf("%w: %d of %d groups are complete", ErrInsufficientShares, len(groupShares), first.GroupThreshold)
+	}
+	encryptedSecret, err := recoverSecret(first.GroupThreshold, groupShares)
+	if err != nil {
+		return nil, err
+	}
+	return decrypt(encryptedSecret, passphrase, first.IterationExponent, first.Identifier, first.Extendable), nil
+}
+
+// DecodeMnemonic decodes a mnemonic into a share and verifies its checksum.
+func DecodeMnemonic(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < minMnemonicLengthWords {
+		return nil, fmt.Errorf("%w: at least %d words are required", ErrInvalidMnemonic, minMnemonicLengthWords)
+	}
+	data := make([]int, len(words))
+	for i, w := range words {
+		idx, ok := wordMap[w]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %s", ErrInvalidMnemonic, w)
+		}
+		data[i] = idx
+	}
+	paddingLen := radixBits * (len(data) - metadataLengthWords) % 16
+	if paddingLen > 8 {
+		return nil, fmt.Errorf("%w: invalid mnemonic length", ErrInvalidMnemonic)
+	}
+
+	idExp := data[0]<<radixBits | data[1]
+	share := &Share{
+		Identifier:        uint16(idExp >> 5),
+		Extendable:        idExp>>4&1 == 1,
+		IterationExponent: idExp & 0xf,
+	}
+	if !rs1024VerifyChecksum(data, customization(share.Extendable)) {
+		return nil, ErrInvalidChecksum
+	}
+	params := data[2]<<radixBits | data[3]
+	share.GroupIndex = params >> 16
+	share.GroupThreshold = params>>12&0xf + 1
+	share.GroupCount = params>>8&0xf + 1
+	share.MemberIndex = params >> 4 & 0xf
+	share.MemberThreshold = params&0xf + 1
+	if share.GroupCount < share.GroupThreshold {
+		return nil, fmt.Errorf("%w: group threshold exceeds the number of groups", ErrInvalidMnemonic)
+	}
+
+	valueData := data[4 : len(data)-checksumLengthWords]
+	valueInt := new(big.Int)
+	for _, v := range valueData {
+		valueInt.Lsh(valueInt, radixBits)
+		valueInt.Or(valueInt, big.NewInt(int64(v)))
+	}
+	valueLen := (radixBits*len(valueData) - paddingLen) / 8
+	if valueInt.BitLen() > valueLen*8 {
+		return nil, fmt.Errorf("%w: invalid padding", ErrInvalidMnemonic)
+	}
+	share.Value = valueInt.FillBytes(make([]byte, valueLen))
+	return share, nil
+}
+
+// Mnemonic encodes the share into a mnemonic.
+func (s *Share) Mnemonic() (string, error) {
+	if s.GroupIndex < 0 || s.GroupIndex >= maxShareCount || s.MemberIndex < 0 || s.MemberIndex >= maxShareCount ||
+		s.GroupThreshold < 1 || s.GroupThreshold > maxShareCount || s.GroupCount < 1 || s.GroupCount > maxShareCount ||
+		s.MemberThreshold < 1 || s.MemberThreshold > maxShareCount ||
+		s.IterationExponent < 0 || s.IterationExponent > maxIterationExponent || s.Identifier >= 1<<idLengthBits {
+		return "", fmt.Errorf("slip39 share parameters are out of range")
+	}
+	ext := 0
+	if s.Extendable {
+		ext = 1
+	}
+	idExp := int(s.Identifier)<<5 | ext<<4 | s.IterationExponent
+	params := s.GroupIndex<<16 | (s.GroupThreshold-1)<<12 | (s.GroupCount-1)<<8 | s.MemberIndex<<4 | (s.MemberThreshold - 1)
+	data := []int{idExp >> radixBits, idExp & 1023, params >> radixBits, params & 1023}
+
+	valueWords := (len(s.Value)*8 + radixBits - 1) / radixBits
+	valueInt := new(big.Int).SetBytes(s.Value)
+	mask := big.NewInt(1023)
+	for i := valueWords - 1; i >= 0; i-- {
+		v := new(big.Int).Rsh(valueInt, uint(i*radixBits))
+		data = append(data, int(v.And(v, mask).Int64()))
+	}
+	data = append(data, rs1024CreateChecksum(data, customization(s.Extendable))...)
+
+	words := make([]string, len(data))
+	for i, v := range data {
+		words[i] = wordList[v]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// ValidateMnemonic reports whether the mnemonic is a well-formed share with a valid checksum.
+func ValidateMnemonic(mnemonic string) bool {
+	_, err := DecodeMnemonic(mnemonic)
+	return err == nil
+}
+
+func customization(extendable bool) string {
+	if extendable {
+		return customizationStringExtendable
+	}
+	return customizationString
+}
+
+func validatePassphrase(passphrase []byte) error {
+	for _, c := range passphrase {
+		if c < 32 || c > 126 {
+			return fmt.Errorf("slip39 the passphrase must contain only printable ASCII characters")
+		}
+	}
+	return nil
+}
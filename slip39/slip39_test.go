@@ -0,0 +1,165 @@
+package slip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+)
+
+// TestVectors runs testdata/vectors.json, which uses the format of
+// https://github.com/trezor/python-shamir-mnemonic/blob/master/vectors.json:
+// [description, mnemonics, master secret, xprv], the passphrase is "TREZOR".
+// Invalid cases have an empty master secret, the xprv is the secp256k1 bip32
+// master key of the master secret.
+func TestVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases [][]json.RawMessage
+	if err := json.Unmarshal(data, &cases); err != nil || len(cases) == 0 {
+		t.Fatalf("vectors=%d err=%v", len(cases), err)
+	}
+	for _, c := range cases {
+		if len(c) != 4 {
+			t.Fatalf("invalid vector: %s", c)
+		}
+		var name, secretHex, xprv string
+		var mnemonics []string
+		for i, v := range []interface{}{&name, &mnemonics, &secretHex, &xprv} {
+			if err := json.Unmarshal(c[i], v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		masterSecret, err := CombineMnemonics(mnemonics, []byte("TREZOR"))
+		if secretHex == "" {
+			if err == nil {
+				t.Fatalf("%s: expected error", name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if hex.EncodeToString(masterSecret) != secretHex {
+			t.Fatalf("%s: masterSecret=%x, want %s", name, masterSecret, secretHex)
+		}
+		key, err := bip32.NewMasterKeyWithCurve(masterSecret, bip32.CurveSecp256k1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.String() != xprv {
+			t.Fatalf("%s: xprv=%s, want %s", name, key.String(), xprv)
+		}
+	}
+}
+
+func TestDecodeMnemonic_Extendable(t *testing.T) {
+	share, err := DecodeMnemonic("enemy favorite academic acid cowboy phrase havoc level response walnut budget painting inside trash adjust froth kitchen learn tidy punish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !share.Extendable || share.MemberThreshold != 2 || len(share.Value) != 16 {
+		t.Fatalf("share=%+v", share)
+	}
+}
+
+func TestGenerateMnemonics(t *testing.T) {
+	masterSecret := bytes.Repeat([]byte{0x5a}, 32)
+	groups := []MemberGroup{{1, 1}, {2, 3}, {3, 5}}
+	for _, extendable := range []bool{false, true} {
+		mnemonics, err := GenerateMnemonics(2, groups, masterSecret, []byte("passphrase"), extendable, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, g := range groups {
+			if len(mnemonics[i]) != g.MemberCount {
+				t.Fatalf("group %d has %d mnemonics", i, len(mnemonics[i]))
+			}
+		}
+		for _, tc := range []struct {
+			shares []string
+			err    error
+		}{
+			{concat(mnemonics[0][:1], mnemonics[1][1:]), nil},
+			{concat(mnemonics[1][:2], mnemonics[2][2:]), nil},
+			{concat(mnemonics[0][:1], mnemonics[2][:3]), nil},
+			{concat(mnemonics[0][:1], mnemonics[1][:1]), ErrInsufficientShares},
+			{mnemonics[2], ErrInsufficientShares},
+		} {
+			secret, err := CombineMnemonics(tc.shares, []byte("passphrase"))
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err=%v, want %v", err, tc.err)
+			}
+			if tc.err == nil && !bytes.Equal(secret, masterSecret) {
+				t.Fatalf("secret=%x, want %x", secret, masterSecret)
+			}
+		}
+		// a wrong passphrase gives a different secret
+		secret, err := CombineMnemonics(concat(mnemonics[0][:1], mnemonics[1][1:]), []byte("wrong"))
+		if err != nil || bytes.Equal(secret, masterSecret) {
+			t.Fatalf("secret=%x err=%v", secret, err)
+		}
+	}
+
+	other, err := GenerateMnemonics(1, []MemberGroup{{2, 2}}, masterSecret, nil, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mnemonics, err := GenerateMnemonics(1, []MemberGroup{{2, 2}}, masterSecret, nil, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CombineMnemonics([]string{mnemonics[0][0], other[0][1]}, nil); !errors.Is(err, ErrMismatchedShares) {
+		t.Fatalf("err=%v, want ErrMismatchedShares", err)
+	}
+}
+
+func concat(lists ...[]string) []string {
+	var result []string
+	for _, l := range lists {
+		result = append(result, l...)
+	}
+	return result
+}
+
+func TestGenerateMnemonics_Invalid(t *testing.T) {
+	masterSecret := make([]byte, 16)
+	for _, tc := range []struct {
+		groupThreshold int
+		groups         []MemberGroup
+		masterSecret   []byte
+		passphrase     []byte
+	}{
+		{1, []MemberGroup{{1, 1}}, make([]byte, 14), nil},
+		{1, []MemberGroup{{1, 1}}, make([]byte, 17), nil},
+		{2, []MemberGroup{{1, 1}}, masterSecret, nil},
+		{1, []MemberGroup{{1, 2}}, masterSecret, nil},
+		{1, []MemberGroup{{3, 2}}, masterSecret, nil},
+		{1, []MemberGroup{{2, 17}}, masterSecret, nil},
+		{1, []MemberGroup{{1, 1}}, masterSecret, []byte("密码")},
+	} {
+		if _, err := GenerateMnemonics(tc.groupThreshold, tc.groups, tc.masterSecret, tc.passphrase, true, 0); err == nil {
+			t.Fatalf("%+v should be invalid", tc)
+		}
+	}
+}
+
+func TestWordList(t *testing.T) {
+	if len(wordList) != 1024 || !sort.StringsAreSorted(wordList) {
+		t.Fatalf("wordList length=%d sorted=%v", len(wordList), sort.StringsAreSorted(wordList))
+	}
+	prefixes := make(map[string]bool)
+	for _, w := range wordList {
+		if len(w) < 4 || len(w) > 8 || prefixes[w[:4]] {
+			t.Fatalf("word %s is invalid", w)
+		}
+		prefixes[w[:4]] = true
+	}
+}
@@ -0,0 +1,55 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrStorageNotFound        = errors.New("storage item not found")
+	ErrStorageVersionConflict = errors.New("storage item version conflict")
+	ErrStorageInvalidName     = errors.New("storage item name is invalid")
+)
+
+// StorageItem 存储后端中的一个加密钱包数据
+type StorageItem struct {
+	Name    string // 名称
+	Version uint64 // 版本号，每次写入递增，从1开始
+	Data    []byte // 加密后的钱包数据
+}
+
+// Storage 钱包存储后端
+// Put时需要传入当前版本号（新建时为0），版本不一致时返回ErrStorageVersionConflict
+type Storage interface {
+	Get(name string) (*StorageItem, error)                        // 读取钱包数据
+	Put(name string, data []byte, version uint64) (uint64, error) // 写入钱包数据，返回新的版本号
+	List() ([]string, error)                                      // 列出所有钱包名称
+	Delete(name string) error                                     // 删除钱包数据
+}
+
+// 校验名称，名称中不能包含路径分隔符
+func validateStorageName(name string) error {
+	if len(name) == 0 || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return ErrStorageInvalidName
+	}
+	return nil
+}
+
+// 将钱包文件路径拆分为文件存储及名称
+func fileStorageOf(path string) (*FileStorage, string) {
+	return NewFileStorage(filepath.Dir(path)), filepath.Base(path)
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
@@ -0,0 +1,134 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var boltWalletBucket = []byte("wallets")
+
+// BoltStorage 基于bbolt的单文件键值存储，适合在一个进程中托管大量钱包
+// value的格式为：8字节大端版本号 + 加密后的钱包数据
+type BoltStorage struct {
+	db *bolt.DB
+}
+
+// NewBoltStorage 打开或创建bbolt数据库文件
+func NewBoltStorage(path string) (*BoltStorage, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("NewBoltStorage bolt.Open err:%v", err.Error())
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltWalletBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("NewBoltStorage create bucket err:%v", err.Error())
+	}
+	return &BoltStorage{db: db}, nil
+}
+
+// Close 关闭数据库
+func (s *BoltStorage) Close() error {
+	return s.db.Close()
+}
+
+// Get 读取钱包数据
+func (s *BoltStorage) Get(name string) (*StorageItem, error) {
+	if err := validateStorageName(name); err != nil {
+		return nil, err
+	}
+	var item *StorageItem
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(boltWalletBucket).Get([]byte(name))
+		if value == nil {
+			return ErrStorageNotFound
+		}
+		version, data, err := decodeBoltValue(value)
+		if err != nil {
+			return err
+		}
+		item = &StorageItem{Name: name, Version: version, Data: copyBytes(data)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Put 写入钱包数据
+func (s *BoltStorage) Put(name string, data []byte, version uint64) (uint64, error) {
+	if err := validateStorageName(name); err != nil {
+		return 0, err
+	}
+	var newVersion uint64
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltWalletBucket)
+		current := uint64(0)
+		if value := bucket.Get([]byte(name)); value != nil {
+			v, _, err := decodeBoltValue(value)
+			if err != nil {
+				return err
+			}
+			current = v
+		}
+		if current != version {
+			return ErrStorageVersionConflict
+		}
+		newVersion = current + 1
+		value := make([]byte, 8+len(data))
+		binary.BigEndian.PutUint64(value[:8], newVersion)
+		copy(value[8:], data)
+		return bucket.Put([]byte(name), value)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return newVersion, nil
+}
+
+// List 列出所有钱包名称
+func (s *BoltStorage) List() ([]string, error) {
+	names := make([]string, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltWalletBucket).ForEach(func(k, v []byte) error {
+			names = append(names, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Delete 删除钱包数据
+func (s *BoltStorage) Delete(name string) error {
+	if err := validateStorageName(name); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltWalletBucket)
+		if bucket.Get([]byte(name)) == nil {
+			return ErrStorageNotFound
+		}
+		return bucket.Delete([]byte(name))
+	})
+}
+
+func decodeBoltValue(value []byte) (uint64, []byte, error) {
+	if len(value) < 8 {
+		return 0, nil, fmt.Errorf("BoltStorage value is corrupted")
+	}
+	return binary.BigEndian.Uint64(value[:8]), value[8:], nil
+}
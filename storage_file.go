@@ -0,0 +1,249 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	fileStorageVersionSuffix = ".version"
+	fileStorageLockSuffix    = ".lock"
+)
+
+// FileStorage 文件系统存储，每个钱包对应目录下的一个文件
+// 版本号及数据的哈希保存在同目录下的隐藏文件中，不存在版本文件的旧钱包文件版本视为1
+// 写入时先写版本文件再写数据文件，两次写入之间中断时数据与哈希不一致，读取时版本视为上一版本
+// 读写时对同目录下隐藏的锁文件加文件锁，多个进程写入同一钱包时版本校验依然有效
+type FileStorage struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStorage 创建文件系统存储
+func NewFileStorage(dir string) *FileStorage {
+	if len(dir) == 0 {
+		dir = "."
+	}
+	return &FileStorage{dir: dir}
+}
+
+// Dir 存储目录
+func (s *FileStorage) Dir() string {
+	return s.dir
+}
+
+func (s *FileStorage) dataPath(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+func (s *FileStorage) versionPath(name string) string {
+	return filepath.Join(s.dir, "."+name+fileStorageVersionSuffix)
+}
+
+func (s *FileStorage) lockPath(name string) string {
+	return filepath.Join(s.dir, "."+name+fileStorageLockSuffix)
+}
+
+// 锁定钱包，exclusive为false时为共享锁
+func (s *FileStorage) lock(name string, exclusive bool) (func(), error) {
+	unlock, err := lockFile(s.lockPath(name), exclusive)
+	if err != nil {
+		return nil, fmt.Errorf("FileStorage lockFile err:%v", err.Error())
+	}
+	return unlock, nil
+}
+
+// Get 读取钱包数据
+func (s *FileStorage) Get(name string) (*StorageItem, error) {
+	if err := validateStorageName(name); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(s.dataPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrStorageNotFound
+		}
+		return nil, fmt.Errorf("FileStorage os.Stat err:%v", err.Error())
+	}
+	unlock, err := s.lock(name, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	data, err := os.ReadFile(s.dataPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrStorageNotFound
+		}
+		return nil, fmt.Errorf("FileStorage os.ReadFile err:%v", err.Error())
+	}
+	version, err := s.readVersion(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageItem{Name: name, Version: version, Data: data}, nil
+}
+
+// Put 写入钱包数据
+func (s *FileStorage) Put(name string, data []byte, version uint64) (uint64, error) {
+	if err := validateStorageName(name); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return 0, fmt.Errorf("FileStorage os.MkdirAll err:%v", err.Error())
+	}
+	// 版本比较及写入需要在文件锁内完成，避免其他进程在比较后写入
+	unlock, err := s.lock(name, true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	current := uint64(0)
+	if old, err := os.ReadFile(s.dataPath(name)); err == nil {
+		current, err = s.readVersion(name, old)
+		if err != nil {
+			return 0, err
+		}
+	} else if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("FileStorage os.ReadFile err:%v", err.Error())
+	}
+	if current != version {
+		return 0, ErrStorageVersionConflict
+	}
+	current++
+	if err := s.writeVersion(name, current, data); err != nil {
+		return 0, err
+	}
+	if err := writeFileAtomic(s.dataPath(name), data, 0644); err != nil {
+		return 0, err
+	}
+	return current, nil
+}
+
+// List 列出所有钱包名称
+func (s *FileStorage) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("FileStorage os.ReadDir err:%v", err.Error())
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete 删除钱包数据
+func (s *FileStorage) Delete(name string) error {
+	if err := validateStorageName(name); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(s.dataPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrStorageNotFound
+		}
+		return fmt.Errorf("FileStorage os.Stat err:%v", err.Error())
+	}
+	unlock, err := s.lock(name, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := os.Remove(s.dataPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrStorageNotFound
+		}
+		return fmt.Errorf("FileStorage os.Remove err:%v", err.Error())
+	}
+	if err := os.Remove(s.versionPath(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("FileStorage os.Remove err:%v", err.Error())
+	}
+	return nil
+}
+
+// 读取数据文件的版本号，版本文件中的哈希与数据不一致时，数据文件尚未写入，版本为上一版本
+func (s *FileStorage) readVersion(name string, data []byte) (uint64, error) {
+	content, err := os.ReadFile(s.versionPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 1, nil
+		}
+		return 0, fmt.Errorf("FileStorage os.ReadFile err:%v", err.Error())
+	}
+	// 格式为"版本号 数据的sha256"，旧的版本文件只有版本号
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, fmt.Errorf("FileStorage parse version err:invalid version file")
+	}
+	version, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("FileStorage parse version err:%v", err.Error())
+	}
+	if len(fields) == 2 && fields[1] != fileStorageDataHash(data) && version > 1 {
+		version--
+	}
+	return version, nil
+}
+
+// 写入版本文件，记录版本号及即将写入的数据的哈希
+func (s *FileStorage) writeVersion(name string, version uint64, data []byte) error {
+	content := strconv.FormatUint(version, 10) + " " + fileStorageDataHash(data)
+	return writeFileAtomic(s.versionPath(name), []byte(content), 0644)
+}
+
+func fileStorageDataHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// 先写入临时文件再重命名，避免写入中断导致钱包文件损坏
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("writeFileAtomic os.CreateTemp err:%v", err.Error())
+	}
+	tmpName := tmp.Name()
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpName, path)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("writeFileAtomic err:%v", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+// 不支持文件锁的平台只使用进程内的锁，多个进程不能同时写入同一钱包
+func lockFile(path string, exclusive bool) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,81 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStorage 内存存储，数据不落盘，主要用于测试
+type MemoryStorage struct {
+	mu    sync.RWMutex
+	items map[string]*StorageItem
+}
+
+// NewMemoryStorage 创建内存存储
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		items: make(map[string]*StorageItem),
+	}
+}
+
+// Get 读取钱包数据
+func (s *MemoryStorage) Get(name string) (*StorageItem, error) {
+	if err := validateStorageName(name); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item, ok := s.items[name]
+	if !ok {
+		return nil, ErrStorageNotFound
+	}
+	return &StorageItem{Name: item.Name, Version: item.Version, Data: copyBytes(item.Data)}, nil
+}
+
+// Put 写入钱包数据
+func (s *MemoryStorage) Put(name string, data []byte, version uint64) (uint64, error) {
+	if err := validateStorageName(name); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := uint64(0)
+	if item, ok := s.items[name]; ok {
+		current = item.Version
+	}
+	if current != version {
+		return 0, ErrStorageVersionConflict
+	}
+	s.items[name] = &StorageItem{Name: name, Version: current + 1, Data: copyBytes(data)}
+	return current + 1, nil
+}
+
+// List 列出所有钱包名称
+func (s *MemoryStorage) List() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.items))
+	for name := range s.items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete 删除钱包数据
+func (s *MemoryStorage) Delete(name string) error {
+	if err := validateStorageName(name); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.items[name]; !ok {
+		return ErrStorageNotFound
+	}
+	delete(s.items, name)
+	return nil
+}
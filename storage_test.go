@@ -0,0 +1,230 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func testStorage(t *testing.T, store Storage) {
+	if _, err := store.Get("w1"); err != ErrStorageNotFound {
+		t.Fatalf("get missing item err=%v, want ErrStorageNotFound", err)
+	}
+	version, err := store.Put("w1", []byte("v1"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 1 {
+		t.Fatalf("version=%d, want 1", version)
+	}
+	if _, err := store.Put("w1", []byte("stale"), 0); err != ErrStorageVersionConflict {
+		t.Fatalf("put stale version err=%v, want ErrStorageVersionConflict", err)
+	}
+	version, err = store.Put("w1", []byte("v2"), version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err := store.Get("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Version != version || !bytes.Equal(item.Data, []byte("v2")) {
+		t.Fatalf("item=%+v, want version %d data v2", item, version)
+	}
+	if _, err := store.Put("w2", []byte("other"), 0); err != nil {
+		t.Fatal(err)
+	}
+	names, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "w1" || names[1] != "w2" {
+		t.Fatalf("names=%v, want [w1 w2]", names)
+	}
+	if err := store.Delete("w1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("w1"); err != ErrStorageNotFound {
+		t.Fatalf("delete missing item err=%v, want ErrStorageNotFound", err)
+	}
+	if _, err := store.Put("../w3", nil, 0); err != ErrStorageInvalidName {
+		t.Fatalf("put invalid name err=%v, want ErrStorageInvalidName", err)
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, NewMemoryStorage())
+}
+
+func TestFileStorage(t *testing.T) {
+	testStorage(t, NewFileStorage(t.TempDir()))
+}
+
+func TestFileStorage_LegacyFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "wallet.dat"), []byte("legacy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileStorage(dir)
+	item, err := store.Get("wallet.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Version != 1 {
+		t.Fatalf("legacy version=%d, want 1", item.Version)
+	}
+	if _, err := store.Put("wallet.dat", []byte("new"), 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 写入版本文件后、写入数据文件前中断，读取时仍为旧数据及旧版本
+func TestFileStorage_InterruptedPut(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStorage(dir)
+	version, err := store.Put("w1", []byte("v1"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.writeVersion("w1", version+1, []byte("v2")); err != nil {
+		t.Fatal(err)
+	}
+	item, err := store.Get("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Version != version || !bytes.Equal(item.Data, []byte("v1")) {
+		t.Fatalf("item=%+v, want version %d data v1", item, version)
+	}
+	if _, err := store.Put("w1", []byte("stale"), version+1); err != ErrStorageVersionConflict {
+		t.Fatalf("put unwritten version err=%v, want ErrStorageVersionConflict", err)
+	}
+	version, err = store.Put("w1", []byte("v2"), version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err = store.Get("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Version != version || !bytes.Equal(item.Data, []byte("v2")) {
+		t.Fatalf("item=%+v, want version %d data v2", item, version)
+	}
+
+	// 只有版本号的旧版本文件依然可以读取
+	if err := os.WriteFile(store.versionPath("w1"), []byte("7"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	item, err = store.Get("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Version != 7 {
+		t.Fatalf("legacy version=%d, want 7", item.Version)
+	}
+}
+
+// 多个FileStorage（模拟多个进程）并发读改写同一钱包，不会丢失更新
+func TestFileStorage_ConcurrentPut(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewFileStorage(dir).Put("w1", []byte("0"), 0); err != nil {
+		t.Fatal(err)
+	}
+	const workers, rounds = 4, 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := NewFileStorage(dir)
+			for n := 0; n < rounds; {
+				item, err := store.Get("w1")
+				if err != nil {
+					errs <- err
+					return
+				}
+				count, _ := strconv.Atoi(string(item.Data))
+				_, err = store.Put("w1", []byte(strconv.Itoa(count+1)), item.Version)
+				if err == ErrStorageVersionConflict {
+					continue
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				n++
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	item, err := NewFileStorage(dir).Get("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(item.Data) != strconv.Itoa(workers*rounds) || item.Version != workers*rounds+1 {
+		t.Fatalf("data=%s version=%d, want %d", item.Data, item.Version, workers*rounds)
+	}
+}
+
+func TestBoltStorage(t *testing.T) {
+	store, err := NewBoltStorage(filepath.Join(t.TempDir(), "wallets.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	testStorage(t, store)
+}
+
+func TestWalletWithStorage(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.DelMnemonic(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Mnemonic != "" {
+		t.Fatalf("mnemonic should be deleted")
+	}
+	if loaded.ExportMasterExtendedKey() != wallet.ExportMasterExtendedKey() {
+		t.Fatalf("master key mismatch")
+	}
+	if _, err := NewWalletWithStorage(store, "w1", "wrong"); err == nil {
+		t.Fatalf("load with wrong password should fail")
+	}
+}
+
+func TestWalletFilePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.dat")
+	wallet, err := NewWallet(path, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := NewWallet(path, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ExportMasterMnemonic() != wallet.ExportMasterMnemonic() {
+		t.Fatalf("mnemonic mismatch")
+	}
+}
@@ -6,17 +6,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/chain5j/keybox/bip32"
 	"github.com/chain5j/keybox/bip39"
-	"github.com/chain5j/keybox/bip39/wordlists"
-	"github.com/chain5j/keybox/bip44"
 	"github.com/chain5j/keybox/crypto/scrypt"
+	"github.com/chain5j/keybox/crypto/secret"
 	"github.com/chain5j/keybox/util/dateutil"
 	log "github.com/chain5j/log15"
 	"github.com/pborman/uuid"
@@ -30,6 +29,7 @@ type ChildKeyPropertyInfo struct {
 	CoinType      uint32 `json:"coinType"`
 	Time          uint32 `json:"time"`
 	Key           []byte `json:"key"`
+	KeyPath       string `json:"keyPath,omitempty"` // 账户的路径，调用方未传入keyPath时使用
 }
 
 type ExtendedKey struct {
@@ -37,13 +37,28 @@ type ExtendedKey struct {
 	ChainCode []byte `json:"chain_code"` // 32 bytes
 }
 
+var ErrKeyPathMismatch = errors.New("keyPath does not match the account")
+
 var isLog bool // 是否打印日志
 
+var defaultMnemonicType atomic.Value // 新建钱包默认使用的助记词类型
+
+func init() {
+	defaultMnemonicType.Store(MnemonicType_English)
+}
+
+// 钱包及子私钥加密使用的scrypt参数
+var (
+	scryptN = scrypt.StandardScryptN
+	scryptP = scrypt.StandardScryptP
+)
+
 // Wallet 管理钱包文件
 type Wallet struct {
 	mu             sync.RWMutex
 	Path           string                           `json:"path"`
 	Mnemonic       string                           `json:"mnemonic"`
+	SeedType       SeedType                         `json:"seedType,omitempty"` // 助记词的种子类型，为空时为bip39
 	Password       string                           `json:"password"`
 	Key            *bip32.Key                       `json:"key"`
 	Time           uint32                           `json:"time"`
@@ -52,138 +67,211 @@ type Wallet struct {
 
 	IsSaveSubKey      bool `json:"isSaveSubKey"`      // 是否保存子私钥
 	IsSaveExtendedKey bool `json:"isSaveExtendedKey"` // 是否保存扩展私钥
+
+	WatchOnly    bool              `json:"watchOnly,omitempty"`    // 是否为只读钱包
+	AccountXpubs map[string]string `json:"accountXpubs,omitempty"` // 只读钱包的扩展公钥，key为扩展公钥对应的路径
+
+	AccountMetas map[string]*AccountMeta `json:"accountMetas,omitempty"` // 账户的元数据，key为地址
+
+	Orgs map[string]*Organization `json:"orgs,omitempty"` // Purpose45布局的组织，key为组织名称
+
+	saveMu  sync.Mutex // 保证写入存储的顺序
+	storage Storage    // 存储后端
+	name    string     // 钱包在存储后端中的名称
+	version uint64     // 钱包在存储后端中的版本号
+
+	locked      bool        // 是否已锁定
+	lockGen     uint64      // 解锁的代数，用于判断超时锁定是否过期
+	relockTimer *time.Timer // 超时自动锁定
+
+	cache deriveCache // 中间节点的派生缓存，锁定时清空
+
+	auditLog   *AuditLog // 审计日志
+	auditActor string    // 审计日志中的操作人
+
+	policy *PolicyEngine // 签名策略
 }
 
-// 将wallet进行scrypt加密，并写入文件中
-func writeContentToWalletFile(wallet *Wallet, path string, password string) error {
-	if nil == wallet || len(path) == 0 {
-		return fmt.Errorf("writeContentToWalletFile parameter error")
+// 将wallet进行scrypt加密，并写入存储后端中
+func writeContentToStorage(wallet *Wallet, password string) error {
+	if nil == wallet || nil == wallet.storage || len(wallet.name) == 0 {
+		return fmt.Errorf("writeContentToStorage parameter error")
+	}
+	wallet.saveMu.Lock()
+	defer wallet.saveMu.Unlock()
+	wallet.mu.RLock()
+	if wallet.locked {
+		wallet.mu.RUnlock()
+		return ErrWalletLocked
 	}
 	data, err := json.Marshal(wallet)
+	wallet.mu.RUnlock()
 	if err != nil {
 		return fmt.Errorf("NewWallet json.Marshal err:%v", err.Error())
 	}
 	if len(password) == 0 {
-		err = ioutil.WriteFile(path, data, 0644)
-		if err != nil {
-			return fmt.Errorf("NewWallet ioutil.WriteFile err:%v", err.Error())
-		}
-		return nil
+		return wallet.putToStorage(data)
 	}
+	// 明文中包含助记词及主私钥，加密后清零
+	defer secret.Zero(data)
 
 	startTime := getLogCurrentTime()
 	// 使用scrypt加密
-	dataEnc, err := scrypt.EncryptKey(&scrypt.Key{Id: uuid.NewRandom(), PrivateKey: data}, password, scrypt.StandardScryptN, scrypt.StandardScryptP)
+	dataEnc, err := scrypt.EncryptKey(&scrypt.Key{Id: uuid.NewRandom(), PrivateKey: data}, password, scryptN, scryptP)
 	printMsg("scrypt.EncryptKey", startTime)
 	if err != nil {
 		return fmt.Errorf("NewWallet aes.AesEncrypt err:%v", err.Error())
 	}
 	// 转换为base64
 	dataBase64 := base64.StdEncoding.EncodeToString(dataEnc)
-	wallet.mu.Lock()
-	defer wallet.mu.Unlock()
-	err = ioutil.WriteFile(path, []byte(dataBase64), 0644)
+	return wallet.putToStorage([]byte(dataBase64))
+}
+
+func (w *Wallet) putToStorage(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	version, err := w.storage.Put(w.name, data, w.version)
 	if err != nil {
-		return fmt.Errorf("NewWallet ioutil.WriteFile err:%v", err.Error())
+		return fmt.Errorf("NewWallet storage.Put err:%v", err.Error())
 	}
+	w.version = version
 	return nil
 }
 
-// 读取文件内容，并使用scrypt解密
-func readContentToWalletFile(path string, password string) (wallet *Wallet, err error) {
-	if len(path) == 0 || len(password) == 0 {
-		return nil, fmt.Errorf("readContentToWalletFile parameter error")
-	}
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("readContentToWalletFile ioutil.ReadFile err:%v", err.Error())
+// 读取存储后端中的内容，并使用scrypt解密
+func readContentFromStorage(item *StorageItem, password string) (wallet *Wallet, err error) {
+	if nil == item || len(password) == 0 {
+		return nil, fmt.Errorf("readContentFromStorage parameter error")
 	}
-	walletData, err := base64.StdEncoding.DecodeString(string(data))
+	walletData, err := base64.StdEncoding.DecodeString(string(item.Data))
 	if err != nil {
-		return nil, fmt.Errorf("readContentToWalletFile base64.StdEncoding.DecodeString err:%v", err.Error())
+		return nil, fmt.Errorf("readContentFromStorage base64.StdEncoding.DecodeString err:%v", err.Error())
 	}
 	w := new(Wallet)
-	if len(password) == 0 {
-		err = json.Unmarshal(walletData, w)
-		if err != nil {
-			return nil, fmt.Errorf("readContentToWalletFile json.Unmarshal err:%v", err.Error())
-		}
-		return w, nil
-	}
 	// 使用scrypt解密
 	startTime := getLogCurrentTime()
 	key, err := scrypt.DecryptKey(walletData, password)
 	printMsg("scrypt.DecryptKey", startTime)
 	if err != nil {
-		return nil, fmt.Errorf("readContentToWalletFile aes.AesDecrypt err:%v", err.Error())
+		return nil, fmt.Errorf("readContentFromStorage aes.AesDecrypt err:%v", err.Error())
 	}
 	dataDec := key.PrivateKey
+	defer secret.Zero(dataDec)
 	err = json.Unmarshal(dataDec, w)
 	if err != nil {
-		return nil, fmt.Errorf("readContentToWalletFile json.Unmarshal err:%v", err.Error())
+		return nil, fmt.Errorf("readContentFromStorage json.Unmarshal err:%v", err.Error())
 	}
 	return w, nil
 }
 
 // ==========================主账户============================
-// 设置助记词的类型【默认是使用English助记词，如果更换，需要在最前面初始化】
+// 设置新建钱包默认使用的助记词类型【默认是使用English助记词】
+// 需要同时使用多种类型时，使用NewWalletWithMnemonicType指定
 func SetBip39MnemonicType(mnemonicType MnemonicType) {
-	var wordList []string
-	switch mnemonicType {
-	case MnemonicType_Chinese_Simplified:
-		wordList = wordlists.ChineseSimplified
-	case MnemonicType_Chinese_Traditional:
-		wordList = wordlists.ChineseTraditional
-	case MnemonicType_English:
-		wordList = wordlists.English
-	case MnemonicType_French:
-		wordList = wordlists.French
-	case MnemonicType_Italian:
-		wordList = wordlists.Italian
-	case MnemonicType_Japanese:
-		wordList = wordlists.Japanese
-	case MnemonicType_Korean:
-		wordList = wordlists.Korean
-	case MnemonicType_Spanish:
-		wordList = wordlists.Spanish
-	default:
-		wordList = wordlists.English
-	}
-	bip39.SetWordList(wordList)
-}
-
-func newWallet() *Wallet {
+	defaultMnemonicType.Store(mnemonicType)
+	bip39.SetWordList(mnemonicWordList(mnemonicType))
+}
+
+func newWallet(store Storage, name string) *Wallet {
 	return &Wallet{
 		IsSaveSubKey:      false,
 		IsSaveExtendedKey: false,
+		storage:           store,
+		name:              name,
 	}
 }
 
-// NewWallet 创建钱包文件实例
+// WalletOptions 新建钱包的选项
+// 从助记词、私钥或分片恢复钱包时只使用Curve
+type WalletOptions struct {
+	MnemonicType MnemonicType // 助记词类型，为空时使用SetBip39MnemonicType设置的类型
+	WordCount    int          // 助记词的单词数(12,15,18,21,24)，为0时根据Entropy的长度确定，默认12
+	Entropy      []byte       // 用户提供的熵（如骰子、硬币），为空时随机生成
+	Curve        bip32.Curve  // 主私钥使用的曲线(p256,secp256k1)，为空时使用p256，s256的链需要使用扩展公钥派生地址时使用secp256k1
+}
+
+// 主私钥使用的曲线，已存在的钱包会使用其创建时的曲线
+func (opts *WalletOptions) curve() (bip32.Curve, error) {
+	if nil == opts {
+		return bip32.CurveP256, nil
+	}
+	return bip32.ParseCurve(string(opts.Curve))
+}
+
+// NewWallet 创建钱包文件实例，助记词使用SetBip39MnemonicType设置的类型
 func NewWallet(path string, password string) (*Wallet, error) {
-	startTime := getLogCurrentTime()
+	return NewWalletWithOptions(path, password, nil)
+}
+
+// NewWalletWithMnemonicType 创建钱包文件实例，助记词使用指定的类型
+func NewWalletWithMnemonicType(path string, password string, mnemonicType MnemonicType) (*Wallet, error) {
+	return NewWalletWithOptions(path, password, &WalletOptions{MnemonicType: mnemonicType})
+}
+
+// NewWalletWithOptions 使用指定的选项创建钱包文件实例，钱包已存在时直接加载
+func NewWalletWithOptions(path string, password string, opts *WalletOptions) (*Wallet, error) {
 	// 参数检查
 	if len(path) == 0 {
 		return nil, fmt.Errorf("NewWallet path parameter error")
 	}
-	wallet := newWallet()
-	// 判断钱包文件是否存在
-	_, err := ioutil.ReadFile(path)
-	printMsg("ioutil.ReadFile", startTime)
+	store, name := fileStorageOf(path)
+	return newWalletWithStorage(store, name, path, password, opts)
+}
+
+// NewWalletWithStorage 在存储后端中创建钱包实例，name已存在时直接加载
+func NewWalletWithStorage(store Storage, name string, password string) (*Wallet, error) {
+	return NewWalletWithStorageAndOptions(store, name, password, nil)
+}
+
+// NewWalletWithStorageAndMnemonicType 在存储后端中创建钱包实例，助记词使用指定的类型
+func NewWalletWithStorageAndMnemonicType(store Storage, name string, password string, mnemonicType MnemonicType) (*Wallet, error) {
+	return NewWalletWithStorageAndOptions(store, name, password, &WalletOptions{MnemonicType: mnemonicType})
+}
+
+// NewWalletWithStorageAndOptions 使用指定的选项在存储后端中创建钱包实例，name已存在时直接加载
+func NewWalletWithStorageAndOptions(store Storage, name string, password string, opts *WalletOptions) (*Wallet, error) {
+	// 参数检查
+	if nil == store || len(name) == 0 {
+		return nil, fmt.Errorf("NewWallet storage parameter error")
+	}
+	return newWalletWithStorage(store, name, name, password, opts)
+}
+
+func newWalletWithStorage(store Storage, name string, path string, password string, opts *WalletOptions) (*Wallet, error) {
+	if nil == opts {
+		opts = new(WalletOptions)
+	}
+	curve, err := opts.curve()
+	if err != nil {
+		return nil, fmt.Errorf("NewWallet curve err:%v", err.Error())
+	}
+	startTime := getLogCurrentTime()
+	// 判断钱包是否存在
+	item, err := store.Get(name)
+	printMsg("storage.Get", startTime)
 	if err == nil {
-		return readWalletFromFile(path, password, wallet, err)
+		return readWalletFromStorage(store, item, path, password)
 	}
+	if err != ErrStorageNotFound {
+		return nil, fmt.Errorf("NewWallet storage.Get err:%v", err.Error())
+	}
+	wallet := newWallet(store, name)
 	// 不存在的话则创建一个钱包文件
 	// 使用bip39处理，生成助记词
 	startTime = getLogCurrentTime()
-	entropy, err := bip39.NewEntropy(128)
+	entropy, err := newWalletEntropy(opts)
 	printMsg("bip39.NewEntropy", startTime)
 	if err != nil {
 		return nil, fmt.Errorf("NewWallet bip39.NewEntropy err:%v", err.Error())
 	}
+	defer secret.Zero(entropy)
 
+	mnemonicType := opts.MnemonicType
+	if mnemonicType == "" {
+		mnemonicType = defaultMnemonicType.Load().(MnemonicType)
+	}
 	startTime = getLogCurrentTime()
-	mnemonic, err := bip39.NewMnemonic(entropy)
+	mnemonic, err := MnemonicCodec(mnemonicType).NewMnemonic(entropy)
 	printMsg("bip39.NewMnemonic", startTime)
 	if err != nil {
 		return nil, fmt.Errorf("NewWallet bip39.NewMnemonic err:%v", err.Error())
@@ -193,121 +281,204 @@ func NewWallet(path string, password string) (*Wallet, error) {
 	startTime = getLogCurrentTime()
 	seed := bip39.NewSeed(mnemonic, password)
 	printMsg("bip39.NewSeed", startTime)
+	defer secret.Zero(seed)
 	// 创建主私钥
 	startTime = getLogCurrentTime()
-	mKey, err := bip32.NewMasterKey(seed)
+	mKey, err := bip32.NewMasterKeyWithCurve(seed, curve)
 	printMsg("bip32.NewMasterKey", startTime)
 	if err != nil {
 		return nil, fmt.Errorf("NewWallet bip32.NewMasterKey err:%v", err.Error())
 	}
 	wallet.Key = mKey
-	wallet.Time = uint32(time.Now().Unix())
-	wallet.Path = path
-	wallet.Password = password
-	wallet.ChildKeyInfo = make(map[string]*ChildKeyPropertyInfo, 0)
-	wallet.AddrLinkPubkey = make(map[string]string, 0)
-	writeContentToWalletFile(wallet, path, password)
-	if err != nil {
-		return nil, err
-	}
-	return wallet, nil
+	return initWallet(wallet, path, password)
 }
 
-// 从助记词中恢复主钱包
+// 从助记词中恢复主钱包，助记词的类型自动检测
 // isUsePwdBlur 是否使用Password进行混淆
 func LoadWalletFromMnemonic(path string, password string, mnemonic string, isUsePwdBlur bool) (*Wallet, error) {
+	return LoadWalletFromMnemonicWithOptions(path, password, mnemonic, isUsePwdBlur, nil)
+}
+
+// LoadWalletFromMnemonicWithOptions 使用指定的选项从助记词中恢复主钱包
+func LoadWalletFromMnemonicWithOptions(path string, password string, mnemonic string, isUsePwdBlur bool, opts *WalletOptions) (*Wallet, error) {
 	// 参数检查
 	if len(path) == 0 {
 		return nil, fmt.Errorf("LoadWalletFromMnemonic path parameter error")
 	}
-	// 判断钱包文件是否存在
-	_, err := ioutil.ReadFile(path)
+	curve, err := opts.curve()
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromMnemonic curve err:%v", err.Error())
+	}
+	store, name := fileStorageOf(path)
+	return loadWalletFromMnemonic(store, name, path, password, mnemonic, mnemonicPassphrase(password, isUsePwdBlur), curve)
+}
+
+// LoadWalletFromMnemonicWithStorage 从助记词中恢复主钱包，并保存到存储后端中
+func LoadWalletFromMnemonicWithStorage(store Storage, name string, password string, mnemonic string, isUsePwdBlur bool) (*Wallet, error) {
+	return LoadWalletFromMnemonicWithStorageAndOptions(store, name, password, mnemonic, isUsePwdBlur, nil)
+}
+
+// LoadWalletFromMnemonicWithStorageAndOptions 使用指定的选项从助记词中恢复主钱包，并保存到存储后端中
+func LoadWalletFromMnemonicWithStorageAndOptions(store Storage, name string, password string, mnemonic string, isUsePwdBlur bool, opts *WalletOptions) (*Wallet, error) {
+	// 参数检查
+	if nil == store || len(name) == 0 {
+		return nil, fmt.Errorf("LoadWalletFromMnemonic storage parameter error")
+	}
+	curve, err := opts.curve()
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromMnemonic curve err:%v", err.Error())
+	}
+	return loadWalletFromMnemonic(store, name, name, password, mnemonic, mnemonicPassphrase(password, isUsePwdBlur), curve)
+}
+
+// isUsePwdBlur时使用password作为混淆因子，ETH，BTC都没有添加混淆因子
+func mnemonicPassphrase(password string, isUsePwdBlur bool) string {
+	if isUsePwdBlur {
+		return password
+	}
+	return ""
+}
 
-	wallet := newWallet()
+func loadWalletFromMnemonic(store Storage, name string, path string, password string, mnemonic string, passphrase string, curve bip32.Curve) (*Wallet, error) {
+	// 判断钱包是否存在
+	item, err := store.Get(name)
 	if err == nil {
-		return readWalletFromFile(path, password, wallet, err)
+		return readWalletFromStorage(store, item, path, password)
+	}
+	if err != ErrStorageNotFound {
+		return nil, fmt.Errorf("LoadWalletFromMnemonic storage.Get err:%v", err.Error())
 	}
+	wallet := newWallet(store, name)
 
 	// 助记词判断
-	if !bip39.IsMnemonicValid(mnemonic) {
+	mnemonicType, err := DetectMnemonicType(mnemonic)
+	if err != nil {
 		return nil, fmt.Errorf("LoadWalletFromMnemonic mnemonic is err")
 	}
+	codec := MnemonicCodec(mnemonicType)
 
 	startTime := getLogCurrentTime()
 	// 不存在的话则创建一个钱包文件
 	wallet.Mnemonic = mnemonic
 	// 创建主私钥
-	var seed []byte
-	if isUsePwdBlur {
-		// 使用password作为混淆因子
-		seed, err = bip39.NewSeedWithErrorChecking(mnemonic, password)
-	} else {
-		// ETH，BTC都没有添加混淆因子
-		seed, err = bip39.NewSeedWithErrorChecking(mnemonic, "")
-	}
+	seed, err := codec.NewSeedWithErrorChecking(mnemonic, passphrase)
 	printMsg("bip39.NewSeedWithErrorChecking", startTime)
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromMnemonic bip39.NewSeedWithErrorChecking err:%v", err.Error())
+	}
+	defer secret.Zero(seed)
 
 	// 创建主私钥
 	startTime = getLogCurrentTime()
-	mKey, err := bip32.NewMasterKey(seed)
+	mKey, err := bip32.NewMasterKeyWithCurve(seed, curve)
 	printMsg("bip32.NewMasterKey", startTime)
 	if err != nil {
 		return nil, fmt.Errorf("LoadWalletFromMnemonic bip32.NewMasterKey err:%v", err.Error())
 	}
 	wallet.Key = mKey
-	wallet.Time = uint32(time.Now().Unix())
-	wallet.Path = path
-	wallet.Password = password
-	wallet.ChildKeyInfo = make(map[string]*ChildKeyPropertyInfo, 0)
-	wallet.AddrLinkPubkey = make(map[string]string, 0)
-	writeContentToWalletFile(wallet, path, password)
-	if err != nil {
-		return nil, err
-	}
-	return wallet, nil
+	return initWallet(wallet, path, password)
 }
 
 // 从私钥中恢复钱包
 func LoadWalletFromPrvKey(path string, password string, prvKeyBase58 string) (*Wallet, error) {
+	return LoadWalletFromPrvKeyWithOptions(path, password, prvKeyBase58, nil)
+}
+
+// LoadWalletFromPrvKeyWithOptions 使用指定的选项从私钥中恢复钱包
+func LoadWalletFromPrvKeyWithOptions(path string, password string, prvKeyBase58 string, opts *WalletOptions) (*Wallet, error) {
 	// 参数检查
 	if len(path) == 0 {
 		return nil, fmt.Errorf("LoadWalletFromPrvKey path parameter error")
 	}
-	wallet := newWallet()
-	// 判断钱包文件是否存在
-	_, err := ioutil.ReadFile(path)
+	curve, err := opts.curve()
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromPrvKey curve err:%v", err.Error())
+	}
+	store, name := fileStorageOf(path)
+	return loadWalletFromPrvKey(store, name, path, password, prvKeyBase58, curve)
+}
+
+// LoadWalletFromPrvKeyWithStorage 从私钥中恢复钱包，并保存到存储后端中
+func LoadWalletFromPrvKeyWithStorage(store Storage, name string, password string, prvKeyBase58 string) (*Wallet, error) {
+	return LoadWalletFromPrvKeyWithStorageAndOptions(store, name, password, prvKeyBase58, nil)
+}
+
+// LoadWalletFromPrvKeyWithStorageAndOptions 使用指定的选项从私钥中恢复钱包，并保存到存储后端中
+func LoadWalletFromPrvKeyWithStorageAndOptions(store Storage, name string, password string, prvKeyBase58 string, opts *WalletOptions) (*Wallet, error) {
+	// 参数检查
+	if nil == store || len(name) == 0 {
+		return nil, fmt.Errorf("LoadWalletFromPrvKey storage parameter error")
+	}
+	curve, err := opts.curve()
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromPrvKey curve err:%v", err.Error())
+	}
+	return loadWalletFromPrvKey(store, name, name, password, prvKeyBase58, curve)
+}
+
+func loadWalletFromPrvKey(store Storage, name string, path string, password string, prvKeyBase58 string, curve bip32.Curve) (*Wallet, error) {
+	// 判断钱包是否存在
+	item, err := store.Get(name)
 	if err == nil {
-		return readWalletFromFile(path, password, wallet, err)
+		return readWalletFromStorage(store, item, path, password)
 	}
+	if err != ErrStorageNotFound {
+		return nil, fmt.Errorf("LoadWalletFromPrvKey storage.Get err:%v", err.Error())
+	}
+	wallet := newWallet(store, name)
 	if prvKeyBase58 == "" {
 		return nil, errors.New("LoadWalletFromPrvKey prvKeyBase58 is empty")
 	}
 	startTime := getLogCurrentTime()
 	// 文件不存在，解析prvKeyBase58
-	mKey, err := bip32.B58Deserialize(prvKeyBase58)
+	mKey, err := bip32.B58DeserializeWithCurve(prvKeyBase58, curve)
 	printMsg("bip32.B58Deserialize", startTime)
 	if err != nil {
 		return nil, fmt.Errorf("LoadWalletFromPrvKey bip32.Deserialize err:%v", err.Error())
 	}
+	if !mKey.IsPrivate {
+		return nil, errors.New("LoadWalletFromPrvKey extended public key should use NewWatchOnlyWallet")
+	}
 	wallet.Key = mKey
+	return initWallet(wallet, path, password)
+}
+
+// LoadWalletFromStorage 从存储后端中加载已存在的钱包
+func LoadWalletFromStorage(store Storage, name string, password string) (*Wallet, error) {
+	// 参数检查
+	if nil == store || len(name) == 0 {
+		return nil, fmt.Errorf("LoadWalletFromStorage storage parameter error")
+	}
+	item, err := store.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromStorage storage.Get err:%v", err.Error())
+	}
+	return readWalletFromStorage(store, item, name, password)
+}
+
+// 初始化新建的钱包，并写入存储后端
+func initWallet(wallet *Wallet, path string, password string) (*Wallet, error) {
 	wallet.Time = uint32(time.Now().Unix())
 	wallet.Path = path
 	wallet.Password = password
 	wallet.ChildKeyInfo = make(map[string]*ChildKeyPropertyInfo, 0)
 	wallet.AddrLinkPubkey = make(map[string]string, 0)
-	writeContentToWalletFile(wallet, path, password)
+	err := writeContentToStorage(wallet, password)
 	if err != nil {
 		return nil, err
 	}
 	return wallet, nil
 }
 
-// 从文件中读取wallet
-func readWalletFromFile(path string, password string, wallet *Wallet, err error) (*Wallet, error) {
-	wallet, err = readContentToWalletFile(path, password)
+// 从存储后端中读取wallet
+func readWalletFromStorage(store Storage, item *StorageItem, path string, password string) (*Wallet, error) {
+	wallet, err := readContentFromStorage(item, password)
 	if err != nil {
 		return nil, fmt.Errorf("LoadWalletFromMnemonic json.Unmarshal err:%v", err.Error())
 	}
+	wallet.storage = store
+	wallet.name = item.Name
+	wallet.version = item.Version
 	wallet.Path = path
 	wallet.Password = password
 	if nil == wallet.ChildKeyInfo {
@@ -318,29 +489,63 @@ func readWalletFromFile(path string, password string, wallet *Wallet, err error)
 }
 
 // ==========================主账户============================
-// 导出主账户的助记词
+// 导出主账户的助记词，钱包锁定时返回空
 func (w *Wallet) ExportMasterMnemonic() string {
-	return w.Mnemonic
+	w.mu.RLock()
+	mnemonic := w.Mnemonic
+	w.mu.RUnlock()
+	if w.audit(AuditExportMasterMnemonic, "", "", nil, exportErr(mnemonic)) != nil {
+		return ""
+	}
+	return mnemonic
 }
 
-// 导出主账户的扩展私钥
+// 导出主账户的扩展私钥，钱包锁定时返回空
 func (w *Wallet) ExportMasterExtendedKey() string {
-	return w.Key.String()
+	mKey, _, err := w.unlockedSecrets()
+	if err != nil {
+		w.audit(AuditExportMasterExtendedKey, "", "", nil, err)
+		return ""
+	}
+	defer mKey.Zero()
+	if w.audit(AuditExportMasterExtendedKey, "", "", nil, nil) != nil {
+		return ""
+	}
+	return mKey.String()
 }
 
-// 导出主账户的扩展私钥
+// 导出主账户的扩展私钥，钱包锁定时返回空
 func (w *Wallet) ExportMasterRawKey() string {
-	return hex.EncodeToString(w.Key.Key)
+	mKey, _, err := w.unlockedSecrets()
+	if err != nil {
+		w.audit(AuditExportMasterRawKey, "", "", nil, err)
+		return ""
+	}
+	defer mKey.Zero()
+	if w.audit(AuditExportMasterRawKey, "", "", nil, nil) != nil {
+		return ""
+	}
+	return hex.EncodeToString(mKey.Key)
 }
 
 // 删除助记词
 func (w *Wallet) DelMnemonic() error {
+	return w.audit(AuditDelMnemonic, "", "", nil, w.delMnemonic())
+}
+
+func (w *Wallet) delMnemonic() error {
+	w.mu.Lock()
+	if w.locked {
+		w.mu.Unlock()
+		return ErrWalletLocked
+	}
 	if w.Mnemonic == "" {
+		w.mu.Unlock()
 		return nil
 	}
 	w.Mnemonic = ""
-	err := writeContentToWalletFile(w, w.Path, w.Password)
-	return err
+	w.mu.Unlock()
+	return w.save()
 }
 
 // ==========================设置============================
@@ -387,7 +592,7 @@ func buildChildKeyPath(purpose, coinType, org, _account, change, addressIndex ui
 	}
 	path := "/" + strconv.FormatUint(uint64(purpose)-uint64(bip32.FirstHardenedChild), 10)
 	path += "/" + strconv.FormatUint(uint64(coinType)-uint64(bip32.FirstHardenedChild), 10) // 币种
-	if purpose != bip44.Purpose {
+	if purpose == Purpose45 {
 		if org < bip32.FirstHardenedChild {
 			return "", fmt.Errorf("wallet buildChildKeyPath parameter error")
 		}
@@ -400,47 +605,105 @@ func buildChildKeyPath(purpose, coinType, org, _account, change, addressIndex ui
 }
 
 // 通过路径获取相应的参数
-func parseChildKeyPath(path string) ([]uint32, error) {
+// 支持标准写法m/44'/60'/0'/0/0（'或h表示强化派生），以及兼容的/44/60/0/0/0写法（除最后两级外均为强化派生）
+// 带有强化派生标记时，开头的/等同于m/，如/44'/60'/0'/0/0
+func parseChildKeyPath(path string) (bip32.DerivationPath, error) {
+	path = strings.TrimSpace(path)
 	if len(path) == 0 {
-		return nil, fmt.Errorf("wallet getAccountTypeFromKeyPath parameter error")
+		return nil, fmt.Errorf("wallet parseChildKeyPath parameter error")
+	}
+	if !isLegacyKeyPath(path) {
+		standardPath := path
+		if strings.HasPrefix(standardPath, "/") {
+			standardPath = "m" + standardPath
+		}
+		childKeyPath, err := bip32.ParseDerivationPath(standardPath)
+		if err != nil {
+			return nil, fmt.Errorf("wallet parseChildKeyPath %s err:%v", path, err.Error())
+		}
+		return childKeyPath, nil
 	}
-	arrPath := strings.Split(path, "/")
-	arrPath = arrPath[1:]
+	arrPath := strings.Split(path[1:], "/")
 	hardenedIndexStart := len(arrPath) - 2 // 最后两位保持int数据
-	params := make([]uint32, 0)
+	childKeyPath := make(bip32.DerivationPath, 0, len(arrPath))
 	for i, p := range arrPath {
-		typeTemp, _ := strconv.ParseUint(p, 10, 32)
+		index, err := strconv.ParseUint(p, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("wallet parseChildKeyPath %s err:%v", path, err.Error())
+		}
 		if i < hardenedIndexStart {
-			typeTemp = typeTemp + uint64(bip32.FirstHardenedChild)
-			if typeTemp > (uint64(1)<<uint(32) - 1) {
-				return nil, fmt.Errorf("type beyond the limit err")
+			index += uint64(bip32.FirstHardenedChild)
+		}
+		childKeyPath = append(childKeyPath, uint32(index))
+	}
+	return childKeyPath, nil
+}
+
+// 兼容写法以/开头，且不包含强化派生的标记
+func isLegacyKeyPath(path string) bool {
+	return strings.HasPrefix(path, "/") && !strings.ContainsAny(path, "'hH")
+}
+
+// 路径的字符串形式，能用兼容写法表示的路径仍使用兼容写法，保证已有子私钥的加密密码不变
+func formatChildKeyPath(childKeyPath bip32.DerivationPath) string {
+	n := len(childKeyPath)
+	if n < 3 || childKeyPath.IsHardened(n-2) || childKeyPath.IsHardened(n-1) {
+		return childKeyPath.String()
+	}
+	path := ""
+	for i, index := range childKeyPath {
+		if i < n-2 {
+			if !childKeyPath.IsHardened(i) {
+				return childKeyPath.String()
 			}
+			index -= bip32.FirstHardenedChild
 		}
-		params = append(params, uint32(typeTemp))
+		path += "/" + strconv.FormatUint(uint64(index), 10)
+	}
+	return path
+}
+
+// 路径对应的purpose、coinType及org，不存在的层级为0
+func childKeyPathProperties(childKeyPath bip32.DerivationPath) (purpose, coinType, org uint32) {
+	if len(childKeyPath) > 0 {
+		purpose = childKeyPath[0]
+	}
+	if len(childKeyPath) > 1 {
+		coinType = childKeyPath[1]
+	}
+	if purpose == Purpose45 && len(childKeyPath) > 2 {
+		org = childKeyPath[2]
 	}
-	return params, nil
+	return purpose, coinType, org
 }
 
 // 创建账户[同一机构下，同一中签名算法，的同一用户只会保留一个私钥]
-// purpose purpose=44时，不使用org
+// purpose purpose=45时，才使用org
 // org：组织
-// coinType：币种
+// coinType：币种，为0时使用链的币种类型（SLIP-44）
 // _account：将密钥空间划分为独立的用户身份[每一个用户对应一个地址空间]
 // change：0用于外部接收地址 1用于找零地址
 // addressIndex：地址索引[官方推荐不超过20]
 func (w *Wallet) CreateAccount(purpose, coinType, org, _account, change, addressIndex uint32, api ChainAPI) (addr string, keyPath string, err error) {
 	// 参数校验
-	if nil == w.Key {
-		return "", "", fmt.Errorf("wallet CreateAccount should create wallet first")
-	}
 	if api == nil {
 		return "", "", fmt.Errorf("wallet CreateAccount chainApi is nil")
 	}
-
+	coinType = defaultCoinType(coinType, api)
+	if err := w.checkOrgChain(purpose, org, api); err != nil {
+		return "", "", err
+	}
+	if w.WatchOnly {
+		keyPath, err = buildChildKeyPath(purpose, coinType, org, _account, change, addressIndex)
+		if err != nil {
+			return "", "", err
+		}
+		return w.createWatchAccount(keyPath, purpose, coinType, org, api)
+	}
 	if coinType < bip32.FirstHardenedChild || _account < bip32.FirstHardenedChild {
 		return "", "", fmt.Errorf("wallet CreateAccount _account should more than the %x", bip32.FirstHardenedChild)
 	}
-	if purpose != bip44.Purpose {
+	if purpose == Purpose45 {
 		if api.ChainInfo().Algorithm < bip32.FirstHardenedChild {
 			return "", "", fmt.Errorf("wallet CreateAccount algorithmType should more than the %x", bip32.FirstHardenedChild)
 		}
@@ -449,137 +712,171 @@ func (w *Wallet) CreateAccount(purpose, coinType, org, _account, change, address
 		}
 	}
 
-	// Generate sub-private key from master private key
+	// 生成路径
 	startTime := getLogCurrentTime()
-	key, err := bip44.NewKeyFromMasterKeyWithOrg(w.Key, purpose, coinType, org, _account, change, addressIndex)
-	printMsg("bip44.NewKeyFromMasterKey", startTime)
+	keyPath, err = buildChildKeyPath(purpose, coinType, org, _account, change, addressIndex)
+	printMsg("buildChildKeyPath", startTime)
 	if err != nil {
-		return "", "", fmt.Errorf("wallet CreateAccount bip44.NewKeyFromMasterKey err:%v", err.Error())
+		return "", "", err
 	}
-	// Get the public key from the private key
-	startTime = getLogCurrentTime()
-	pubKey, err := api.GetPubKeyFromPriKey(key.Key)
-	printMsg("api.GetPubKeyFromPriKey", startTime)
+	childKeyPath, err := parseChildKeyPath(keyPath)
 	if err != nil {
-		return "", "", fmt.Errorf("wallet CreateAccount getPubKeyFromPriKey err:%v", err.Error())
+		return "", "", err
 	}
+	return w.createAccount(childKeyPath, api)
+}
 
-	startTime = getLogCurrentTime()
-	addr, err = api.GetAddressFromPubKey(pubKey)
-	printMsg("api.GetAddressFromPubKey", startTime)
+// CreateAccountByPath 通过任意深度的路径创建账户
+// keyPath 支持标准写法m/44'/60'/0'/0/0，以及兼容的/44/60/0/0/0写法
+// 返回的keyPath可直接用于ExportRawKey及Sign
+func (w *Wallet) CreateAccountByPath(keyPath string, api ChainAPI) (addr string, retKeyPath string, err error) {
+	if api == nil {
+		return "", "", fmt.Errorf("wallet CreateAccount chainApi is nil")
+	}
+	childKeyPath, err := parseChildKeyPath(keyPath)
 	if err != nil {
 		return "", "", err
 	}
-	if len(addr) == 0 {
-		return "", "", fmt.Errorf("wallet CreateAccount accountObj.GetAddressFromPubKey return value err")
+	if len(childKeyPath) == 0 {
+		return "", "", fmt.Errorf("wallet CreateAccount path should not be the master key")
+	}
+	purpose, coinType, org := childKeyPathProperties(childKeyPath)
+	if err := w.checkOrgChain(purpose, org, api); err != nil {
+		return "", "", err
+	}
+	if w.WatchOnly {
+		return w.createWatchAccount(formatChildKeyPath(childKeyPath), purpose, coinType, org, api)
 	}
+	return w.createAccount(childKeyPath, api)
+}
 
-	// 生成路径
-	startTime = getLogCurrentTime()
-	keyPath, err = buildChildKeyPath(purpose, coinType, org, _account, change, addressIndex)
-	printMsg("buildChildKeyPath", startTime)
+// 按路径派生子私钥并记录账户，IsSaveSubKey时保存子私钥
+func (w *Wallet) createAccount(childKeyPath bip32.DerivationPath, api ChainAPI) (addr string, keyPath string, err error) {
+	mKey, password, err := w.unlockedSecrets()
+	if err != nil {
+		return "", "", err
+	}
+	defer mKey.Zero()
+
+	addr, pubKey, key, err := w.deriveAccount(mKey, childKeyPath, api)
 	if err != nil {
 		return "", "", err
 	}
-	if w.IsSaveSubKey {
-		// Generate sub-private key propertyInfo
-		childKeyPropertyInfo := new(ChildKeyPropertyInfo)
-		childKeyPropertyInfo.Purpose = purpose
-		childKeyPropertyInfo.ChainType = api.ChainInfo().ChainType
-		childKeyPropertyInfo.AlgorithmType = api.ChainInfo().Algorithm
-		childKeyPropertyInfo.Org = org
-		childKeyPropertyInfo.CoinType = coinType
-		childKeyPropertyInfo.Time = uint32(time.Now().Unix())
+	defer key.Zero()
+
+	// 账户的路径总是被记录，IsSaveSubKey时才保存子私钥
+	keyPath = formatChildKeyPath(childKeyPath)
+	saveKey := key
+	if !w.IsSaveSubKey {
+		saveKey = nil
+	}
+	_, coinType, org := childKeyPathProperties(childKeyPath)
+	err = w.recordAccount(w.addressPurpose(childKeyPath), coinType, org, addr, keyPath, saveKey, pubKey, password, api)
+	if err != nil {
+		return "", "", err
+	}
+	err = w.save()
+	if err != nil {
+		return "", "", fmt.Errorf("wallet CreateAccount storage.Put err:%v", err.Error())
+	}
+
+	return addr, keyPath, nil
+}
+
+// 将账户的路径及加密后的子私钥记录到钱包中，key为nil时不保存子私钥，需要调用save才会写入存储后端
+func (w *Wallet) recordAccount(purpose, coinType, org uint32, addr, keyPath string, key *bip32.Key, pubKey []byte, password string, api ChainAPI) error {
+	childKeyPropertyInfo, err := w.newChildKeyInfo(purpose, coinType, org, keyPath, key, password, api)
+	if err != nil {
+		return err
+	}
+	startTime := getLogCurrentTime()
+	w.mu.Lock()
+	w.recordChildKeyInfoLocked(addr, w.getPubKey(pubKey), childKeyPropertyInfo, api)
+	w.mu.Unlock()
+	printMsg("AddrLinkPubkey", startTime)
+	return nil
+}
+
+// 生成子账户的属性信息，key不为nil时加密保存子私钥
+func (w *Wallet) newChildKeyInfo(purpose, coinType, org uint32, keyPath string, key *bip32.Key, password string, api ChainAPI) (*ChildKeyPropertyInfo, error) {
+	// Generate sub-private key propertyInfo
+	childKeyPropertyInfo := new(ChildKeyPropertyInfo)
+	childKeyPropertyInfo.Purpose = purpose
+	childKeyPropertyInfo.ChainType = api.ChainInfo().ChainType
+	childKeyPropertyInfo.AlgorithmType = api.ChainInfo().Algorithm
+	childKeyPropertyInfo.Org = org
+	childKeyPropertyInfo.CoinType = coinType
+	childKeyPropertyInfo.Time = uint32(time.Now().Unix())
+	childKeyPropertyInfo.KeyPath = keyPath
+	if key != nil {
 		// 将subKey进行加密
-		subPwd := w.getSubPwd(keyPath)
+		subPwd := getSubPwd(password, keyPath)
 
 		subPivKey := key.Key
 		if w.IsSaveExtendedKey {
+			var err error
 			subPivKey, err = key.Serialize()
 			if err != nil {
-				return "", "", err
+				return nil, err
 			}
+			defer secret.Zero(subPivKey)
 		}
 
-		startTime = getLogCurrentTime()
-		encryptKey, err := scrypt.EncryptKey(&scrypt.Key{Id: uuid.NewRandom(), Path: keyPath, PrivateKey: subPivKey}, subPwd, scrypt.StandardScryptN, scrypt.StandardScryptP)
+		startTime := getLogCurrentTime()
+		encryptKey, err := scrypt.EncryptKey(&scrypt.Key{Id: uuid.NewRandom(), Path: keyPath, PrivateKey: subPivKey}, subPwd, scryptN, scryptP)
 		printMsg("scrypt.EncryptKey sub", startTime)
 		if err != nil {
-			return "", "", err
+			return nil, err
 		}
-
 		childKeyPropertyInfo.Key = encryptKey
-
-		startTime = getLogCurrentTime()
-		pubKeyStr := w.getPubKey(pubKey)
-		w.mu.Lock()
-		w.AddrLinkPubkey[addr] = pubKeyStr
-		w.ChildKeyInfo[pubKeyStr] = childKeyPropertyInfo
-		w.mu.Unlock()
-		printMsg("AddrLinkPubkey", startTime)
-		err = writeContentToWalletFile(w, w.Path, w.Password)
-		if err != nil {
-			return "", "", fmt.Errorf("wallet CreateAccount ioutil.WriteFile err:%v", err.Error())
-		}
-	}
-
-	if nil == w.ChildKeyInfo {
-		w.ChildKeyInfo = make(map[string]*ChildKeyPropertyInfo, 0)
-		w.AddrLinkPubkey = make(map[string]string, 0)
 	}
-	return addr, keyPath, nil
+	return childKeyPropertyInfo, nil
 }
 
-// 通过路径恢复私钥
-func (w *Wallet) createAccountByPath(path string, api ChainAPI) (addr string, key *bip32.Key, err error) {
-	if api == nil {
-		return "", nil, fmt.Errorf("wallet CreateAccount chainApi is nil")
-	}
-	if path == "" {
-		return "", nil, errors.New("path is empty")
-	}
-	childKeyPath, err := parseChildKeyPath(path)
-	if err != nil {
-		return "", nil, err
-	}
-
-	org := uint32(0)
-	offset := 0
-	if len(childKeyPath) != 5 {
-		org = childKeyPath[2]
-		offset = 1
-	}
+// 将子账户的地址、公钥及属性信息记录到钱包中，调用方需要持有写锁
+func (w *Wallet) recordChildKeyInfoLocked(addr, pubKeyStr string, childKeyPropertyInfo *ChildKeyPropertyInfo, api ChainAPI) {
+	w.AddrLinkPubkey[addr] = pubKeyStr
+	w.ChildKeyInfo[pubKeyStr] = childKeyPropertyInfo
+	w.recordAccountMetaLocked(addr, childKeyPropertyInfo.KeyPath, api)
+}
 
+// 通过路径派生子私钥及地址
+func (w *Wallet) deriveAccount(mKey *bip32.Key, childKeyPath bip32.DerivationPath, api ChainAPI) (addr string, pubKey []byte, key *bip32.Key, err error) {
 	startTime := getLogCurrentTime()
 	// Generate sub-private key from master private key
-	key, err = bip44.NewKeyFromMasterKeyWithOrg(w.Key, childKeyPath[0], childKeyPath[1], org, childKeyPath[offset+2], childKeyPath[offset+3], childKeyPath[offset+4])
-	printMsg("bip44.NewKeyFromMasterKey", startTime)
+	key, err = w.deriveKey(mKey, childKeyPath)
+	printMsg("w.deriveKey", startTime)
 	if err != nil {
-		return "", nil, fmt.Errorf("wallet CreateAccount bip44.NewKeyFromMasterKey err:%v", err.Error())
+		return "", nil, nil, fmt.Errorf("wallet CreateAccount deriveKey err:%v", err.Error())
 	}
 	// Get the public key from the private key
 	startTime = getLogCurrentTime()
-	pubKey, err := api.GetPubKeyFromPriKey(key.Key)
+	pubKey, err = api.GetPubKeyFromPriKey(key.Key)
 	printMsg("api.GetPubKeyFromPriKey", startTime)
 	if err != nil {
-		return "", nil, fmt.Errorf("wallet CreateAccount getPubKeyFromPriKey err:%v", err.Error())
+		key.Zero()
+		return "", nil, nil, fmt.Errorf("wallet CreateAccount getPubKeyFromPriKey err:%v", err.Error())
 	}
 
 	startTime = getLogCurrentTime()
-	addr, err = api.GetAddressFromPubKey(pubKey)
+	addr, err = w.addressFromPubKey(api, pubKey, childKeyPath)
 	printMsg("api.GetAddressFromPubKey", startTime)
 	if err != nil {
-		return "", nil, err
+		key.Zero()
+		return "", nil, nil, err
 	}
 	if len(addr) == 0 {
-		return "", nil, fmt.Errorf("addr is empty")
+		key.Zero()
+		return "", nil, nil, fmt.Errorf("wallet CreateAccount accountObj.GetAddressFromPubKey return value err")
 	}
 
-	return addr, key, nil
+	return addr, pubKey, key, nil
 }
 
 // ListAccount list all account
 func (w *Wallet) ListAccount() ([]string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
 	if nil == w.AddrLinkPubkey {
 		return nil, nil
 	}
@@ -590,17 +887,23 @@ func (w *Wallet) ListAccount() ([]string, error) {
 	return accounts, nil
 }
 
-// 将地址对应的私钥转换成keystore
+// 将地址对应的私钥转换成keystore，keyPath为空时使用账户记录的路径
 func (w *Wallet) ExportKeyStore(address, keyPath string, keystorePwd string, api ChainAPI) (keyStore string, err error) {
+	defer func() {
+		if err = w.audit(AuditExportKeyStore, address, keyPath, nil, err); err != nil {
+			keyStore = ""
+		}
+	}()
 	// 参数校验
 	if len(address) == 0 || len(keystorePwd) == 0 {
 		return "", fmt.Errorf("wallet ExportKeyStore parameter error")
 	}
 
-	bip32Key, err := w.getRawPrivateKey(address, keyPath, api)
+	bip32Key, keyPath, err := w.getRawPrivateKey(address, keyPath, false, api)
 	if err != nil {
 		return "", err
 	}
+	defer bip32Key.Zero()
 
 	keyStoreBytes, err := scrypt.EncryptKey(
 		&scrypt.Key{
@@ -615,47 +918,143 @@ func (w *Wallet) ExportKeyStore(address, keyPath string, keystorePwd string, api
 	return string(keyStoreBytes), err
 }
 
-func (w *Wallet) getRawPrivateKey(address string, keyPath string, api ChainAPI) (bip32Key *bip32.Key, err error) {
+// 获取地址对应的私钥，keyPath为空时使用账户记录的路径
+// 钱包中保存了子私钥时直接使用解密后的子私钥，否则通过路径重新派生；extended为true时需要包含链码的扩展私钥
+func (w *Wallet) getRawPrivateKey(address string, keyPath string, extended bool, api ChainAPI) (bip32Key *bip32.Key, retKeyPath string, err error) {
+	if w.WatchOnly {
+		return nil, "", ErrWatchOnly
+	}
+	w.mu.RLock()
 	pubKeyStr := w.AddrLinkPubkey[address]
-	startTime := getLogCurrentTime()
-	if pubKeyStr != "" {
-		childKeyInfo := w.ChildKeyInfo[pubKeyStr]
-		if nil == childKeyInfo {
-			return nil, fmt.Errorf("wallet ExportKeyStore key store not exist")
-		}
-		priKeyBytes1 := childKeyInfo.Key
+	childKeyInfo := w.ChildKeyInfo[pubKeyStr]
+	w.mu.RUnlock()
+	if pubKeyStr != "" && nil == childKeyInfo {
+		return nil, "", fmt.Errorf("wallet ExportKeyStore key store not exist")
+	}
+	childKeyPath, err := resolveKeyPath(address, keyPath, childKeyInfo)
+	if err != nil {
+		return nil, "", err
+	}
+	keyPath = formatChildKeyPath(childKeyPath)
+	mKey, password, err := w.unlockedSecrets()
+	if err != nil {
+		return nil, "", err
+	}
+	defer mKey.Zero()
 
-		// 将subKeyEnc进行解密
-		// 外层的密码+addr作为
-		subPwd := w.getSubPwd(keyPath)
-		priKey, err := scrypt.DecryptKey(priKeyBytes1, subPwd)
-		printMsg("scrypt.DecryptKey sub", startTime)
+	if childKeyInfo != nil && len(childKeyInfo.Key) > 0 {
+		bip32Key, err = w.decryptSubKey(childKeyInfo, keyPath, password, extended, mKey.Curve)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
-		priKeyBytes := priKey.PrivateKey
-		if len(priKeyBytes) != 32 {
-			bip32Key, err = bip32.Deserialize(priKeyBytes)
-			if err != nil {
-				return nil, err
+		if bip32Key != nil {
+			// 校验子私钥与地址记录的公钥一致
+			pubKey, err := api.GetPubKeyFromPriKey(bip32Key.Key)
+			if err != nil || w.getPubKey(pubKey) != pubKeyStr {
+				bip32Key.Zero()
+				return nil, "", errors.New("address is diff")
 			}
+			w.recordLegacyKeyPath(childKeyInfo, keyPath)
+			return bip32Key, keyPath, nil
 		}
 	}
-	startTime = getLogCurrentTime()
-	addr, prvKey, err := w.createAccountByPath(keyPath, api)
-	printMsg("createAccountByPath", startTime)
+
+	startTime := getLogCurrentTime()
+	addr, _, prvKey, err := w.deriveAccount(mKey, childKeyPath, api)
+	printMsg("deriveAccount", startTime)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if addr != address {
-		return nil, errors.New("address is diff")
+		prvKey.Zero()
+		return nil, "", errors.New("address is diff")
+	}
+	w.recordLegacyKeyPath(childKeyInfo, keyPath)
+	return prvKey, keyPath, nil
+}
+
+// 旧版本钱包的账户未记录路径，路径校验通过后补充记录，之后可以不传入keyPath
+// 保存失败时不影响本次使用，下次仍可通过keyPath使用
+func (w *Wallet) recordLegacyKeyPath(childKeyInfo *ChildKeyPropertyInfo, keyPath string) {
+	if childKeyInfo == nil {
+		return
+	}
+	w.mu.Lock()
+	if childKeyInfo.KeyPath != "" {
+		w.mu.Unlock()
+		return
+	}
+	childKeyInfo.KeyPath = keyPath
+	w.mu.Unlock()
+	if err := w.save(); err != nil {
+		w.mu.Lock()
+		childKeyInfo.KeyPath = ""
+		w.mu.Unlock()
+	}
+}
+
+// 解析地址使用的路径，keyPath为空时使用账户记录的路径，不为空时需要与记录的路径一致
+// 旧版本钱包的账户未记录路径，依次使用子私钥中保存的路径及传入的keyPath
+func resolveKeyPath(address, keyPath string, childKeyInfo *ChildKeyPropertyInfo) (bip32.DerivationPath, error) {
+	recorded := ""
+	if childKeyInfo != nil {
+		recorded = childKeyInfo.KeyPath
+		if recorded == "" && len(childKeyInfo.Key) > 0 {
+			recorded = scrypt.KeyPath(childKeyInfo.Key)
+		}
+	}
+	if keyPath == "" {
+		if recorded == "" {
+			return nil, fmt.Errorf("wallet keyPath of address %s is required", address)
+		}
+		keyPath = recorded
+	}
+	childKeyPath, err := parseChildKeyPath(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if recorded != "" && formatChildKeyPath(childKeyPath) != recorded {
+		return nil, ErrKeyPathMismatch
+	}
+	return childKeyPath, nil
+}
+
+// 解密钱包中保存的子私钥，保存的不是扩展私钥且需要扩展私钥时返回nil
+func (w *Wallet) decryptSubKey(childKeyInfo *ChildKeyPropertyInfo, keyPath, password string, extended bool, curve bip32.Curve) (*bip32.Key, error) {
+	// 将subKeyEnc进行解密
+	// 外层的密码+addr作为
+	startTime := getLogCurrentTime()
+	subPwd := getSubPwd(password, keyPath)
+	priKey, err := scrypt.DecryptKey(childKeyInfo.Key, subPwd)
+	printMsg("scrypt.DecryptKey sub", startTime)
+	if err != nil {
+		return nil, err
+	}
+	priKeyBytes := priKey.PrivateKey
+	defer secret.Zero(priKeyBytes)
+	if len(priKeyBytes) == 32 {
+		if extended {
+			return nil, nil
+		}
+		return &bip32.Key{Key: secret.Copy(priKeyBytes), IsPrivate: true, Curve: curve}, nil
 	}
-	bip32Key = prvKey
-	return
+	key, err := bip32.Deserialize(priKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	key = key.Copy()
+	key.Curve = curve
+	return key, nil
 }
 
 // 将地址对应的私钥直接输出
+// keyPath 支持标准写法m/44'/60'/0'/0/0，以及兼容的/44/60/0/0/0写法，为空时使用账户记录的路径
 func (w *Wallet) ExportRawKey(address, keyPath string, api ChainAPI) (key string, err error) {
+	defer func() {
+		if err = w.audit(AuditExportRawKey, address, keyPath, nil, err); err != nil {
+			key = ""
+		}
+	}()
 	// 参数校验
 	if len(address) == 0 {
 		return "", fmt.Errorf("wallet ExportKeyStore parameter error")
@@ -664,23 +1063,30 @@ func (w *Wallet) ExportRawKey(address, keyPath string, api ChainAPI) (key string
 		return "", fmt.Errorf("wallet ExportKeyStore key store not exist")
 	}
 
-	bip32Key, err := w.getRawPrivateKey(address, keyPath, api)
+	bip32Key, keyPath, err := w.getRawPrivateKey(address, keyPath, false, api)
 	if err != nil {
 		return "", err
 	}
+	defer bip32Key.Zero()
 	return api.ExportPrivateKey(bip32Key.Key, false)
 }
 
-// 导出扩展私钥
+// 导出扩展私钥，keyPath为空时使用账户记录的路径
 func (w *Wallet) ExportExtendedKey(address, keyPath string, api ChainAPI) (extendedKey string, err error) {
+	defer func() {
+		if err = w.audit(AuditExportExtendedKey, address, keyPath, nil, err); err != nil {
+			extendedKey = ""
+		}
+	}()
 	// 参数校验
 	if len(address) == 0 {
 		return "", fmt.Errorf("wallet ExportKeyStore parameter error")
 	}
-	bip32Key, err := w.getRawPrivateKey(address, keyPath, api)
+	bip32Key, keyPath, err := w.getRawPrivateKey(address, keyPath, true, api)
 	if err != nil {
 		return "", err
 	}
+	defer bip32Key.Zero()
 	return bip32Key.String(), nil
 }
 
@@ -694,9 +1100,9 @@ func (w *Wallet) ImportRawKey(key string, password string) (address string, err
 	return "", fmt.Errorf("not support")
 }
 
-func (w *Wallet) getSubPwd(keyPath string) string {
+func getSubPwd(password string, keyPath string) string {
 	// 外层的密码+addr作为
-	keccak256Hash := scrypt.Keccak256([]byte(w.Password + keyPath))
+	keccak256Hash := scrypt.Keccak256([]byte(password + keyPath))
 	subPwd := hex.EncodeToString(keccak256Hash)
 	return subPwd
 }
@@ -708,17 +1114,56 @@ func (w *Wallet) getPubKey(pubKey []byte) string {
 	return pubKeyStr
 }
 
-// GetPriKeyFromAddress 获取某个地址的私钥
-func (w *Wallet) GetPriKeyFromAddress(address, keyPath string, api ChainAPI) ([]byte, error) {
-	strKey, err := w.ExportRawKey(address, keyPath, api)
+// GetPriKeyFromAddress 获取某个地址的私钥，调用方使用完毕后需要调用secret.Zero清零
+func (w *Wallet) GetPriKeyFromAddress(address, keyPath string, api ChainAPI) (priKey []byte, err error) {
+	defer func() {
+		if err = w.audit(AuditExportPriKey, address, keyPath, nil, err); err != nil {
+			secret.Zero(priKey)
+			priKey = nil
+		}
+	}()
+	if len(address) == 0 {
+		return nil, fmt.Errorf("wallet GetPriKeyFromAddress parameter error")
+	}
+	bip32Key, keyPath, err := w.getRawPrivateKey(address, keyPath, false, api)
 	if err != nil {
 		return nil, fmt.Errorf("wallet GetPriKeyFromAddress err:%v", err.Error())
 	}
-	return hex.DecodeString(strKey)
+	defer bip32Key.Zero()
+	return secret.Copy(bip32Key.Key), nil
+}
+
+// Sign 使用地址对应的私钥签名
+// keyPath 支持标准写法m/44'/60'/0'/0/0，以及兼容的/44/60/0/0/0写法，为空时使用账户记录的路径
+// 策略包含目标地址、金额或合约方法的规则时无法校验hash对应的交易，需要使用SignTx
+func (w *Wallet) Sign(address, keyPath string, hash []byte, api ChainAPI) (sign string, err error) {
+	return w.sign(address, keyPath, hash, nil, api)
 }
 
-// Sign
-func (w *Wallet) Sign(address, keyPath string, hash []byte, api ChainAPI) (string, error) {
+// SignTx 签名未签名的交易，签名内容及交易信息由链的TxDecodeAPI从rawTx中解析，策略拒绝时返回*PolicyError
+// rawTx 未签名交易的编码，如ETH为EIP-155的rlp编码
+func (w *Wallet) SignTx(address, keyPath string, rawTx []byte, api ChainAPI) (sign string, err error) {
+	if api == nil {
+		return "", fmt.Errorf("wallet SignTx chainApi is nil")
+	}
+	decoder, ok := api.(TxDecodeAPI)
+	if !ok {
+		return "", fmt.Errorf("wallet SignTx chain %s does not support decoding transactions", api.ChainInfo().ChainName)
+	}
+	hash, tx, err := decoder.DecodeTx(rawTx)
+	if err != nil {
+		return "", fmt.Errorf("wallet SignTx decode tx err:%v", err.Error())
+	}
+	return w.sign(address, keyPath, hash, tx, api)
+}
+
+// 校验签名策略后签名，tx为nil时只校验与交易无关的规则
+func (w *Wallet) sign(address, keyPath string, hash []byte, tx *TxInfo, api ChainAPI) (sign string, err error) {
+	defer func() {
+		if err = w.audit(AuditSign, address, keyPath, hash, err); err != nil {
+			sign = ""
+		}
+	}()
 	// 参数校验
 	if len(address) == 0 {
 		return "", fmt.Errorf("wallet ExportKeyStore parameter error")
@@ -727,11 +1172,28 @@ func (w *Wallet) Sign(address, keyPath string, hash []byte, api ChainAPI) (strin
 		return "", fmt.Errorf("wallet ExportKeyStore key store not exist")
 	}
 
+	if api == nil {
+		return "", fmt.Errorf("wallet Sign chainApi is nil")
+	}
+	release, err := w.authorizeSign(address, tx, api)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
 	startTime := getLogCurrentTime()
-	bip32Key, err := w.getRawPrivateKey(address, keyPath, api)
+	bip32Key, keyPath, err := w.getRawPrivateKey(address, keyPath, false, api)
 	printMsg("w.getRawPrivateKey(address, keyPath, api)", startTime)
+	if err != nil {
+		return "", err
+	}
+	defer bip32Key.Zero()
 	startTime = getLogCurrentTime()
-	sign, err := api.SignToStr(bip32Key.Key, hash)
+	sign, err = api.SignToStr(bip32Key.Key, hash)
 	printMsg("api.SignToStr", startTime)
 	if err != nil {
 		return "", err
@@ -0,0 +1,110 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip85"
+	"github.com/chain5j/keybox/crypto/secret"
+)
+
+// 助记词类型对应的BIP85语言
+func bip85Language(mnemonicType MnemonicType) (bip85.Language, error) {
+	switch mnemonicType {
+	case MnemonicType_English:
+		return bip85.English, nil
+	case MnemonicType_Japanese:
+		return bip85.Japanese, nil
+	case MnemonicType_Korean:
+		return bip85.Korean, nil
+	case MnemonicType_Spanish:
+		return bip85.Spanish, nil
+	case MnemonicType_Chinese_Simplified:
+		return bip85.ChineseSimplified, nil
+	case MnemonicType_Chinese_Traditional:
+		return bip85.ChineseTraditional, nil
+	case MnemonicType_French:
+		return bip85.French, nil
+	case MnemonicType_Italian:
+		return bip85.Italian, nil
+	}
+	return 0, fmt.Errorf("BIP85 mnemonic type %s is not supported", mnemonicType)
+}
+
+// 使用主私钥派生BIP85的子密钥，并记录审计日志
+// BIP85只定义了secp256k1，主私钥为其他曲线时返回ErrCurveMismatch
+func (w *Wallet) deriveBIP85(path bip32.DerivationPath, derive func(mKey *bip32.Key) (string, error)) (value string, err error) {
+	defer func() {
+		if err = w.audit(AuditDeriveBIP85, "", path.String(), nil, err); err != nil {
+			value = ""
+		}
+	}()
+	mKey, _, err := w.unlockedSecrets()
+	if err != nil {
+		return "", err
+	}
+	defer mKey.Zero()
+	if keyCurve(mKey) != bip32.CurveSecp256k1 {
+		return "", ErrCurveMismatch
+	}
+	return derive(mKey)
+}
+
+// DeriveBIP85Mnemonic 派生子助记词，words为12,18,24
+func (w *Wallet) DeriveBIP85Mnemonic(mnemonicType MnemonicType, words int, index uint32) (string, error) {
+	language, err := bip85Language(mnemonicType)
+	if err != nil {
+		return "", err
+	}
+	return w.deriveBIP85(bip85.Path(bip85.AppBIP39, uint32(language), uint32(words), index), func(mKey *bip32.Key) (string, error) {
+		return bip85.NewMnemonic(mKey, language, words, index)
+	})
+}
+
+// DeriveBIP85WIF 派生WIF格式的私钥
+func (w *Wallet) DeriveBIP85WIF(index uint32) (string, error) {
+	return w.deriveBIP85(bip85.Path(bip85.AppHDSeedWIF, index), func(mKey *bip32.Key) (string, error) {
+		return bip85.NewWIF(mKey, index)
+	})
+}
+
+// DeriveBIP85XPRV 派生secp256k1的主扩展私钥
+func (w *Wallet) DeriveBIP85XPRV(index uint32) (string, error) {
+	return w.deriveBIP85(bip85.Path(bip85.AppXPRV, index), func(mKey *bip32.Key) (string, error) {
+		key, err := bip85.NewXPRV(mKey, index)
+		if err != nil {
+			return "", err
+		}
+		defer key.Zero()
+		return key.String(), nil
+	})
+}
+
+// DeriveBIP85Hex 派生16进制的熵，numBytes为16~64
+func (w *Wallet) DeriveBIP85Hex(numBytes int, index uint32) (string, error) {
+	return w.deriveBIP85(bip85.Path(bip85.AppHex, uint32(numBytes), index), func(mKey *bip32.Key) (string, error) {
+		entropy, err := bip85.NewHex(mKey, numBytes, index)
+		if err != nil {
+			return "", err
+		}
+		defer secret.Zero(entropy)
+		return hex.EncodeToString(entropy), nil
+	})
+}
+
+// DeriveBIP85Password 派生密码，base85为false时使用base64，长度为20~86；为true时使用base85，长度为10~80
+func (w *Wallet) DeriveBIP85Password(base85 bool, length int, index uint32) (string, error) {
+	if base85 {
+		return w.deriveBIP85(bip85.Path(bip85.AppPasswordB85, uint32(length), index), func(mKey *bip32.Key) (string, error) {
+			return bip85.NewBase85Password(mKey, length, index)
+		})
+	}
+	return w.deriveBIP85(bip85.Path(bip85.AppPasswordB64, uint32(length), index), func(mKey *bip32.Key) (string, error) {
+		return bip85.NewBase64Password(mKey, length, index)
+	})
+}
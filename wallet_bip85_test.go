@@ -0,0 +1,63 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+)
+
+func TestWallet_DeriveBIP85(t *testing.T) {
+	// BIP85的测试向量
+	wallet, err := LoadWalletFromPrvKeyWithStorageAndOptions(NewMemoryStorage(), "w1", "123456", "xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb", &WalletOptions{Curve: bip32.CurveSecp256k1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name   string
+		derive func() (string, error)
+		want   string
+	}{
+		{"mnemonic", func() (string, error) { return wallet.DeriveBIP85Mnemonic(MnemonicType_English, 12, 0) }, "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose"},
+		{"wif", func() (string, error) { return wallet.DeriveBIP85WIF(0) }, "Kzyv4uF39d4Jrw2W7UryTHwZr1zQVNk4dAFyqE6BuMrMh1Za7uhp"},
+		{"xprv", func() (string, error) { return wallet.DeriveBIP85XPRV(0) }, "xprv9s21ZrQH143K2srSbCSg4m4kLvPMzcWydgmKEnMmoZUurYuBuYG46c6P71UGXMzmriLzCCBvKQWBUv3vPB3m1SATMhp3uEjXHJ42jFg7myX"},
+		{"hex", func() (string, error) { return wallet.DeriveBIP85Hex(64, 0) }, "492db4698cf3b73a5a24998aa3e9d7fa96275d85724a91e71aa2d645442f878555d078fd1f1f67e368976f04137b1f7a0d19232136ca50c44614af72b5582a5c"},
+		{"base64", func() (string, error) { return wallet.DeriveBIP85Password(false, 21, 0) }, "dKLoepugzdVJvdL56ogNV"},
+		{"base85", func() (string, error) { return wallet.DeriveBIP85Password(true, 12, 0) }, "_s`{TW89)i4`"},
+	} {
+		if got, err := tc.derive(); err != nil || got != tc.want {
+			t.Fatalf("%s: got=%s err=%v", tc.name, got, err)
+		}
+	}
+
+	// 子助记词可以恢复独立的钱包
+	mnemonic, err := wallet.DeriveBIP85Mnemonic(MnemonicType_Japanese, 24, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWalletFromMnemonicWithStorage(NewMemoryStorage(), "w2", "123456", mnemonic, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.DeriveBIP85Mnemonic(MnemonicType("czech"), 12, 0); err == nil {
+		t.Fatal("unsupported mnemonic type should fail")
+	}
+
+	wallet.Lock()
+	if _, err := wallet.DeriveBIP85WIF(0); err != ErrWalletLocked {
+		t.Fatalf("err=%v, want ErrWalletLocked", err)
+	}
+}
+
+func TestWallet_DeriveBIP85CurveMismatch(t *testing.T) {
+	// p256的主私钥派生的结果无法使用其他BIP85实现恢复
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.DeriveBIP85Mnemonic(MnemonicType_English, 12, 0); err != ErrCurveMismatch {
+		t.Fatalf("err=%v, want ErrCurveMismatch", err)
+	}
+}
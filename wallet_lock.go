@@ -0,0 +1,159 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chain5j/keybox/bip32"
+)
+
+var ErrWalletLocked = errors.New("wallet is locked")
+
+// IsLocked 钱包是否已锁定
+func (w *Wallet) IsLocked() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.locked
+}
+
+// Lock 锁定钱包，主私钥、助记词及密码会从内存中清除
+// 锁定后只能进行账户列表等不涉及私钥的操作
+func (w *Wallet) Lock() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lockLocked()
+}
+
+// 需要在w.mu锁定的情况下调用
+func (w *Wallet) lockLocked() {
+	if w.relockTimer != nil {
+		w.relockTimer.Stop()
+		w.relockTimer = nil
+	}
+	w.lockGen++
+	w.Key.Zero()
+	w.Key = nil
+	w.Mnemonic = ""
+	w.Password = ""
+	w.cache.purge()
+	w.locked = true
+}
+
+// Unlock 使用密码解锁钱包
+// duration>0时，超过该时长后钱包会自动锁定；duration=0时不自动锁定
+func (w *Wallet) Unlock(password string, duration time.Duration) error {
+	err := w.unlock(password, duration)
+	if auditErr := w.audit(AuditUnlock, "", "", nil, err); auditErr != nil && err == nil {
+		// 审计失败时不允许使用钱包
+		w.Lock()
+		return auditErr
+	}
+	return err
+}
+
+func (w *Wallet) unlock(password string, duration time.Duration) error {
+	if nil == w.storage {
+		return fmt.Errorf("wallet Unlock storage is nil")
+	}
+	item, err := w.storage.Get(w.name)
+	if err != nil {
+		return fmt.Errorf("wallet Unlock storage.Get err:%v", err.Error())
+	}
+	stored, err := readContentFromStorage(item, password)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Key.Zero()
+	w.cache.purge()
+	w.Key = stored.Key
+	w.Mnemonic = stored.Mnemonic
+	w.SeedType = stored.SeedType
+	w.Password = password
+	w.Time = stored.Time
+	w.AddrLinkPubkey = stored.AddrLinkPubkey
+	w.ChildKeyInfo = stored.ChildKeyInfo
+	w.IsSaveSubKey = stored.IsSaveSubKey
+	w.IsSaveExtendedKey = stored.IsSaveExtendedKey
+	w.WatchOnly = stored.WatchOnly
+	w.AccountXpubs = stored.AccountXpubs
+	w.AccountMetas = stored.AccountMetas
+	w.Orgs = stored.Orgs
+	if nil == w.ChildKeyInfo {
+		w.ChildKeyInfo = make(map[string]*ChildKeyPropertyInfo, 0)
+		w.AddrLinkPubkey = make(map[string]string, 0)
+	}
+	w.version = item.Version
+	w.locked = false
+	w.lockGen++
+	w.relockAfterLocked(duration)
+	return nil
+}
+
+// 设置超时自动锁定
+func (w *Wallet) relockAfter(duration time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.relockAfterLocked(duration)
+}
+
+// 需要在w.mu锁定的情况下调用
+func (w *Wallet) relockAfterLocked(duration time.Duration) {
+	if w.relockTimer != nil {
+		w.relockTimer.Stop()
+		w.relockTimer = nil
+	}
+	if duration <= 0 || w.locked {
+		return
+	}
+	gen := w.lockGen
+	w.relockTimer = time.AfterFunc(duration, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.lockGen == gen && !w.locked {
+			w.lockLocked()
+		}
+	})
+}
+
+// 获取主私钥副本及密码，调用方使用完后需要对私钥进行清零
+func (w *Wallet) unlockedSecrets() (*bip32.Key, string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.locked {
+		return nil, "", ErrWalletLocked
+	}
+	if w.WatchOnly {
+		return nil, "", ErrWatchOnly
+	}
+	if nil == w.Key {
+		return nil, "", fmt.Errorf("wallet should create wallet first")
+	}
+	return w.Key.Copy(), w.Password, nil
+}
+
+// 获取钱包密码
+func (w *Wallet) unlockedPassword() (string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.locked {
+		return "", ErrWalletLocked
+	}
+	return w.Password, nil
+}
+
+// 将钱包写入存储后端，钱包锁定时无法写入
+func (w *Wallet) save() error {
+	password, err := w.unlockedPassword()
+	if err != nil {
+		return err
+	}
+	return writeContentToStorage(w, password)
+}
@@ -0,0 +1,132 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/crypto/scrypt"
+)
+
+func TestWallet_LockUnlock(t *testing.T) {
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetIsSaveSubKey(true)
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mnemonic := wallet.ExportMasterMnemonic()
+	masterKey := wallet.Key
+
+	wallet.Lock()
+	if !wallet.IsLocked() {
+		t.Fatalf("wallet should be locked")
+	}
+	for _, b := range masterKey.Key {
+		if b != 0 {
+			t.Fatalf("master key should be zeroed after lock")
+		}
+	}
+	if wallet.ExportMasterMnemonic() != "" || wallet.ExportMasterExtendedKey() != "" {
+		t.Fatalf("locked wallet should not export master secrets")
+	}
+	// 锁定后仍可以查询账户
+	accounts, err := wallet.ListAccount()
+	if err != nil || len(accounts) != 1 || accounts[0] != addr {
+		t.Fatalf("accounts=%v err=%v, want [%s]", accounts, err, addr)
+	}
+	hash := scrypt.Keccak256([]byte("hello"))
+	if _, err := wallet.Sign(addr, keyPath, hash, api); err != ErrWalletLocked {
+		t.Fatalf("sign err=%v, want ErrWalletLocked", err)
+	}
+	if _, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 1, api); err != ErrWalletLocked {
+		t.Fatalf("create account err=%v, want ErrWalletLocked", err)
+	}
+	if err := wallet.DelMnemonic(); err != ErrWalletLocked {
+		t.Fatalf("del mnemonic err=%v, want ErrWalletLocked", err)
+	}
+
+	if err := wallet.Unlock("wrong", 0); err == nil {
+		t.Fatalf("unlock with wrong password should fail")
+	}
+	if err := wallet.Unlock("123456", 0); err != nil {
+		t.Fatal(err)
+	}
+	if wallet.ExportMasterMnemonic() != mnemonic {
+		t.Fatalf("mnemonic should be restored after unlock")
+	}
+	if _, err := wallet.Sign(addr, keyPath, hash, api); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWallet_UnlockTimeout(t *testing.T) {
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.Lock()
+	if err := wallet.Unlock("123456", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if wallet.IsLocked() {
+		t.Fatalf("wallet should be unlocked")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !wallet.IsLocked() {
+		if time.Now().After(deadline) {
+			t.Fatalf("wallet should relock after timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 重新解锁后，旧的超时不再生效
+	if err := wallet.Unlock("123456", 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if wallet.IsLocked() {
+		t.Fatalf("wallet unlocked without timeout should stay unlocked")
+	}
+}
+
+func TestWallet_LockConcurrent(t *testing.T) {
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	hash := scrypt.Keccak256([]byte("hello"))
+	addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if i%4 == 0 {
+					wallet.Lock()
+					continue
+				}
+				_, err := wallet.Sign(addr, keyPath, hash, api)
+				if err != nil && err != ErrWalletLocked {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
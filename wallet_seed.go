@@ -0,0 +1,217 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/crypto/secret"
+	"github.com/chain5j/keybox/electrum"
+)
+
+// SeedType 导入助记词的种子类型
+type SeedType string
+
+const (
+	SeedType_BIP39            SeedType = "bip39"
+	SeedType_ElectrumStandard SeedType = "electrum_standard" // Electrum v2标准种子，地址路径m/{change}/{index}
+	SeedType_ElectrumSegwit   SeedType = "electrum_segwit"   // Electrum v2隔离见证种子，地址路径m/0'/{change}/{index}
+)
+
+var (
+	ErrUnknownSeed   = errors.New("unknown seed, not a bip39 mnemonic or an electrum v2 seed")
+	ErrAmbiguousSeed = errors.New("seed is both a bip39 mnemonic and an electrum v2 seed, the seed type should be given")
+	ErrMoneroSeed    = errors.New("monero style seed (25 words with a checksum word) is not supported")
+)
+
+// Monero风格种子的单词数，24个单词加1个校验单词
+const moneroSeedWords = 25
+
+// 种子类型对应的Electrum种子版本
+func (t SeedType) electrumSeedType() (electrum.SeedType, bool) {
+	switch t {
+	case SeedType_ElectrumStandard:
+		return electrum.SeedTypeStandard, true
+	case SeedType_ElectrumSegwit:
+		return electrum.SeedTypeSegwit, true
+	}
+	return "", false
+}
+
+// DetectSeedType 检测助记词的种子类型
+// 同时满足bip39校验和及Electrum种子版本时返回ErrAmbiguousSeed，需由调用方指定类型
+// Electrum的2FA种子及旧版（v1）种子不支持，Monero风格的25个单词的种子返回ErrMoneroSeed
+func DetectSeedType(mnemonic string) (SeedType, error) {
+	_, bip39Err := DetectMnemonicType(mnemonic)
+	version, electrumErr := electrum.SeedTypeOf(mnemonic)
+	var seedType SeedType
+	switch version {
+	case electrum.SeedTypeStandard:
+		seedType = SeedType_ElectrumStandard
+	case electrum.SeedTypeSegwit:
+		seedType = SeedType_ElectrumSegwit
+	case electrum.SeedType2FA, electrum.SeedType2FASegwit:
+		if bip39Err != nil {
+			return "", fmt.Errorf("electrum %s seed is not supported", version)
+		}
+		electrumErr = electrum.ErrNotElectrumSeed
+	}
+	switch {
+	case bip39Err == nil && electrumErr == nil:
+		return "", ErrAmbiguousSeed
+	case bip39Err == nil:
+		return SeedType_BIP39, nil
+	case electrumErr == nil:
+		return seedType, nil
+	case len(strings.Fields(mnemonic)) == moneroSeedWords:
+		return "", ErrMoneroSeed
+	}
+	return "", ErrUnknownSeed
+}
+
+// ImportSeed 使用bip39助记词或Electrum种子恢复主钱包
+// passphrase 助记词的密码（Electrum中为种子扩展词），可以为空
+// seedType 为空时自动检测
+func ImportSeed(path string, password string, mnemonic string, passphrase string, seedType SeedType) (*Wallet, error) {
+	return ImportSeedWithOptions(path, password, mnemonic, passphrase, seedType, nil)
+}
+
+// ImportSeedWithOptions 使用指定的选项恢复主钱包，Curve只用于bip39助记词，Electrum种子总是使用secp256k1
+func ImportSeedWithOptions(path string, password string, mnemonic string, passphrase string, seedType SeedType, opts *WalletOptions) (*Wallet, error) {
+	// 参数检查
+	if len(path) == 0 {
+		return nil, fmt.Errorf("ImportSeed path parameter error")
+	}
+	store, name := fileStorageOf(path)
+	return importSeed(store, name, path, password, mnemonic, passphrase, seedType, opts)
+}
+
+// ImportSeedWithStorage 使用bip39助记词或Electrum种子恢复主钱包，并保存到存储后端中
+func ImportSeedWithStorage(store Storage, name string, password string, mnemonic string, passphrase string, seedType SeedType) (*Wallet, error) {
+	return ImportSeedWithStorageAndOptions(store, name, password, mnemonic, passphrase, seedType, nil)
+}
+
+// ImportSeedWithStorageAndOptions 使用指定的选项恢复主钱包，并保存到存储后端中
+func ImportSeedWithStorageAndOptions(store Storage, name string, password string, mnemonic string, passphrase string, seedType SeedType, opts *WalletOptions) (*Wallet, error) {
+	// 参数检查
+	if nil == store || len(name) == 0 {
+		return nil, fmt.Errorf("ImportSeed storage parameter error")
+	}
+	return importSeed(store, name, name, password, mnemonic, passphrase, seedType, opts)
+}
+
+func importSeed(store Storage, name string, path string, password string, mnemonic string, passphrase string, seedType SeedType, opts *WalletOptions) (*Wallet, error) {
+	curve, err := opts.curve()
+	if err != nil {
+		return nil, fmt.Errorf("ImportSeed curve err:%v", err.Error())
+	}
+	if seedType == "" {
+		seedType, err = DetectSeedType(mnemonic)
+		if err != nil {
+			return nil, fmt.Errorf("ImportSeed err:%v", err.Error())
+		}
+	}
+	if seedType == SeedType_BIP39 {
+		return loadWalletFromMnemonic(store, name, path, password, mnemonic, passphrase, curve)
+	}
+	version, ok := seedType.electrumSeedType()
+	if !ok {
+		return nil, fmt.Errorf("ImportSeed seed type %s is not supported", seedType)
+	}
+
+	// 判断钱包是否存在
+	item, err := store.Get(name)
+	if err == nil {
+		return readWalletFromStorage(store, item, path, password)
+	}
+	if err != ErrStorageNotFound {
+		return nil, fmt.Errorf("ImportSeed storage.Get err:%v", err.Error())
+	}
+	wallet := newWallet(store, name)
+
+	// 种子版本校验
+	actual, err := electrum.SeedTypeOf(mnemonic)
+	if err != nil {
+		return nil, fmt.Errorf("ImportSeed err:%v", err.Error())
+	}
+	if actual != version {
+		return nil, fmt.Errorf("ImportSeed seed version is %s, not %s", actual, version)
+	}
+
+	startTime := getLogCurrentTime()
+	seed := electrum.NewSeed(mnemonic, passphrase)
+	printMsg("electrum.NewSeed", startTime)
+	defer secret.Zero(seed)
+
+	// 创建主私钥，Electrum只使用secp256k1
+	startTime = getLogCurrentTime()
+	mKey, err := bip32.NewMasterKeyWithCurve(seed, bip32.CurveSecp256k1)
+	printMsg("bip32.NewMasterKey", startTime)
+	if err != nil {
+		return nil, fmt.Errorf("ImportSeed bip32.NewMasterKey err:%v", err.Error())
+	}
+	wallet.Mnemonic = mnemonic
+	wallet.SeedType = seedType
+	wallet.Key = mKey
+	return initWallet(wallet, path, password)
+}
+
+// ElectrumKeyPath 返回Electrum钱包地址的路径
+// change 0用于外部接收地址 1用于找零地址
+func (w *Wallet) ElectrumKeyPath(change, addressIndex uint32) (string, error) {
+	w.mu.RLock()
+	seedType := w.SeedType
+	w.mu.RUnlock()
+	version, ok := seedType.electrumSeedType()
+	if !ok {
+		return "", fmt.Errorf("wallet ElectrumKeyPath wallet is not imported from an electrum seed")
+	}
+	if change > 1 || addressIndex >= bip32.FirstHardenedChild {
+		return "", fmt.Errorf("wallet ElectrumKeyPath parameter error")
+	}
+	return formatChildKeyPath(electrum.AddressPath(version, change, addressIndex)), nil
+}
+
+// CreateElectrumAccount 按Electrum的路径创建账户，地址与Electrum中同一种子的地址相同
+// 隔离见证种子的账户为P2WPKH地址（与Purpose84相同）；
+// 标准种子的账户在链实现CompressedAddressAPI时使用压缩公钥，如BTC为压缩公钥的P2PKH地址
+func (w *Wallet) CreateElectrumAccount(change, addressIndex uint32, api ChainAPI) (addr string, keyPath string, err error) {
+	keyPath, err = w.ElectrumKeyPath(change, addressIndex)
+	if err != nil {
+		return "", "", err
+	}
+	return w.CreateAccountByPath(keyPath, api)
+}
+
+// 从公钥获取地址，Electrum标准种子的账户（m/change/index）使用压缩公钥，与Electrum相同
+func (w *Wallet) addressFromPubKey(api ChainAPI, pubKey []byte, childKeyPath bip32.DerivationPath) (string, error) {
+	w.mu.RLock()
+	seedType := w.SeedType
+	w.mu.RUnlock()
+	if seedType == SeedType_ElectrumStandard && len(childKeyPath) == 2 && childKeyPath[0] <= 1 {
+		if c, ok := api.(CompressedAddressAPI); ok {
+			return c.GetAddressFromCompressedPubKey(pubKey)
+		}
+	}
+	return getAddressFromPubKey(api, pubKey, w.addressPurpose(childKeyPath))
+}
+
+// 派生地址使用的purpose，Electrum隔离见证种子的账户（m/0'/change/index）为P2WPKH地址，与Purpose84相同
+func (w *Wallet) addressPurpose(childKeyPath bip32.DerivationPath) uint32 {
+	purpose, _, _ := childKeyPathProperties(childKeyPath)
+	w.mu.RLock()
+	seedType := w.SeedType
+	w.mu.RUnlock()
+	if seedType == SeedType_ElectrumSegwit {
+		account := electrum.AccountPath(electrum.SeedTypeSegwit)
+		if len(childKeyPath) == len(account)+2 && childKeyPath[0] == account[0] {
+			return Purpose84
+		}
+	}
+	return purpose
+}
@@ -0,0 +1,111 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/electrum"
+)
+
+const testElectrumSeed = "wild father tree among universe such mobile favorite target dynamic credit identify"
+
+func TestDetectSeedType(t *testing.T) {
+	seedType, err := DetectSeedType(testElectrumSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seedType != SeedType_ElectrumSegwit {
+		t.Fatalf("seed type got %s, want %s", seedType, SeedType_ElectrumSegwit)
+	}
+	seedType, err = DetectSeedType(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seedType != SeedType_BIP39 {
+		t.Fatalf("seed type got %s, want %s", seedType, SeedType_BIP39)
+	}
+	if _, err := DetectSeedType("wild father tree"); err != ErrUnknownSeed {
+		t.Fatal("unknown seed should fail")
+	}
+	// Monero风格的种子：24个单词加1个校验单词
+	monero := strings.TrimSpace(strings.Repeat("abbey ", 24) + "acumen")
+	if _, err := DetectSeedType(monero); err != ErrMoneroSeed {
+		t.Fatalf("err=%v, want ErrMoneroSeed", err)
+	}
+	if _, err := ImportSeedWithStorage(NewMemoryStorage(), "w1", "123456", monero, "", ""); err == nil {
+		t.Fatal("import monero seed should fail")
+	}
+}
+
+func TestImportSeed(t *testing.T) {
+	passphrase := "Did you ever hear the tragedy of Darth Plagueis the Wise?"
+	mKey, err := bip32.NewMasterKeyWithCurve(electrum.NewSeed(testElectrumSeed, passphrase), bip32.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewMemoryStorage()
+	if _, err := ImportSeedWithStorage(store, "w1", "123456", testElectrumSeed, passphrase, SeedType_ElectrumStandard); err == nil {
+		t.Fatal("import seed with wrong seed type should fail")
+	}
+	wallet, err := ImportSeedWithStorage(store, "w1", "123456", testElectrumSeed, passphrase, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wallet.ExportMasterExtendedKey() != mKey.String() {
+		t.Fatal("master key should be derived from the electrum seed")
+	}
+	if wallet.ExportMasterMnemonic() != testElectrumSeed {
+		t.Fatal("wallet should keep the electrum seed")
+	}
+
+	api := newS256TestChain("T1", bip32.ParseHDNum(1))
+	addr, keyPath, err := wallet.CreateElectrumAccount(1, 3, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := mKey.DerivePath(electrum.AddressPath(electrum.SeedTypeSegwit, 1, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := api.GetPubKeyFromPriKey(key.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := api.GetAddressFromPubKey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != want {
+		t.Fatalf("electrum account got %s, want %s", addr, want)
+	}
+	if _, err := wallet.ExportRawKey(addr, keyPath, api); err != nil {
+		t.Fatal(err)
+	}
+
+	// 锁定后解锁保留种子类型
+	wallet.Lock()
+	if err := wallet.Unlock("123456", 0); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := wallet.ElectrumKeyPath(0, 0); err != nil || path != formatChildKeyPath(electrum.AddressPath(electrum.SeedTypeSegwit, 0, 0)) {
+		t.Fatalf("electrum key path got %s, err:%v", path, err)
+	}
+
+	// bip39助记词的钱包不能使用Electrum路径
+	bip39Wallet, err := ImportSeedWithStorage(store, "w2", "123456", testMnemonic, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bip39Wallet.SeedType != "" {
+		t.Fatal("bip39 wallet should have no seed type")
+	}
+	if _, _, err := bip39Wallet.CreateElectrumAccount(0, 0, api); err == nil {
+		t.Fatal("bip39 wallet should not create electrum account")
+	}
+}
@@ -0,0 +1,82 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"fmt"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/crypto/secret"
+	"github.com/chain5j/keybox/slip39"
+)
+
+// LoadWalletFromShares 使用SLIP-0039分片助记词恢复主钱包，主密钥作为BIP32的种子
+// shares 满足组门限及成员门限的分片助记词
+// passphrase SLIP-0039的密码，可以为空
+func LoadWalletFromShares(path string, password string, shares []string, passphrase string) (*Wallet, error) {
+	return LoadWalletFromSharesWithOptions(path, password, shares, passphrase, nil)
+}
+
+// LoadWalletFromSharesWithOptions 使用指定的选项从SLIP-0039分片助记词恢复主钱包
+func LoadWalletFromSharesWithOptions(path string, password string, shares []string, passphrase string, opts *WalletOptions) (*Wallet, error) {
+	// 参数检查
+	if len(path) == 0 {
+		return nil, fmt.Errorf("LoadWalletFromShares path parameter error")
+	}
+	curve, err := opts.curve()
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromShares curve err:%v", err.Error())
+	}
+	store, name := fileStorageOf(path)
+	return loadWalletFromShares(store, name, path, password, shares, passphrase, curve)
+}
+
+// LoadWalletFromSharesWithStorage 使用SLIP-0039分片助记词恢复主钱包，并保存到存储后端中
+func LoadWalletFromSharesWithStorage(store Storage, name string, password string, shares []string, passphrase string) (*Wallet, error) {
+	return LoadWalletFromSharesWithStorageAndOptions(store, name, password, shares, passphrase, nil)
+}
+
+// LoadWalletFromSharesWithStorageAndOptions 使用指定的选项从SLIP-0039分片助记词恢复主钱包，并保存到存储后端中
+func LoadWalletFromSharesWithStorageAndOptions(store Storage, name string, password string, shares []string, passphrase string, opts *WalletOptions) (*Wallet, error) {
+	// 参数检查
+	if nil == store || len(name) == 0 {
+		return nil, fmt.Errorf("LoadWalletFromShares storage parameter error")
+	}
+	curve, err := opts.curve()
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromShares curve err:%v", err.Error())
+	}
+	return loadWalletFromShares(store, name, name, password, shares, passphrase, curve)
+}
+
+func loadWalletFromShares(store Storage, name string, path string, password string, shares []string, passphrase string, curve bip32.Curve) (*Wallet, error) {
+	// 判断钱包是否存在
+	item, err := store.Get(name)
+	if err == nil {
+		return readWalletFromStorage(store, item, path, password)
+	}
+	if err != ErrStorageNotFound {
+		return nil, fmt.Errorf("LoadWalletFromShares storage.Get err:%v", err.Error())
+	}
+	wallet := newWallet(store, name)
+
+	startTime := getLogCurrentTime()
+	masterSecret, err := slip39.CombineMnemonics(shares, []byte(passphrase))
+	printMsg("slip39.CombineMnemonics", startTime)
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromShares slip39.CombineMnemonics err:%v", err.Error())
+	}
+	defer secret.Zero(masterSecret)
+
+	// 创建主私钥
+	startTime = getLogCurrentTime()
+	mKey, err := bip32.NewMasterKeyWithCurve(masterSecret, curve)
+	printMsg("bip32.NewMasterKey", startTime)
+	if err != nil {
+		return nil, fmt.Errorf("LoadWalletFromShares bip32.NewMasterKey err:%v", err.Error())
+	}
+	wallet.Key = mKey
+	return initWallet(wallet, path, password)
+}
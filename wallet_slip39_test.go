@@ -0,0 +1,48 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/slip39"
+)
+
+func TestLoadWalletFromShares(t *testing.T) {
+	masterSecret := bytes.Repeat([]byte{0x11}, 16)
+	shares, err := slip39.GenerateMnemonics(1, []slip39.MemberGroup{{MemberThreshold: 2, MemberCount: 3}}, masterSecret, []byte("TREZOR"), true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mKey, err := bip32.NewMasterKeyWithCurve(masterSecret, bip32.CurveP256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewMemoryStorage()
+	if _, err := LoadWalletFromSharesWithStorage(store, "w1", "123456", shares[0][:1], "TREZOR"); err == nil {
+		t.Fatal("load wallet with insufficient shares should fail")
+	}
+	wallet, err := LoadWalletFromSharesWithStorage(store, "w1", "123456", shares[0][1:], "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wallet.ExportMasterExtendedKey() != mKey.String() {
+		t.Fatal("master key should be derived from the master secret")
+	}
+	if wallet.ExportMasterMnemonic() != "" {
+		t.Fatal("wallet from shares has no bip39 mnemonic")
+	}
+	// 钱包已存在时直接加载
+	loaded, err := LoadWalletFromSharesWithStorage(store, "w1", "123456", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ExportMasterExtendedKey() != mKey.String() {
+		t.Fatal("loaded wallet should keep the master key")
+	}
+}
@@ -5,10 +5,29 @@
 package keybox
 
 import (
+	"encoding/hex"
 	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+
+	"github.com/chain5j/keybox/algorithm/p256"
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/chain"
+	"github.com/chain5j/keybox/crypto/address"
+	"github.com/chain5j/keybox/crypto/scrypt"
+	"github.com/chain5j/keybox/crypto/secret"
 )
 
+func TestMain(m *testing.M) {
+	// 测试中使用轻量的scrypt参数
+	scryptN = scrypt.LightScryptN
+	scryptP = scrypt.LightScryptP
+	os.Exit(m.Run())
+}
+
 func TestParseChildKeyPath(t *testing.T) {
 	childKeyPath, _ := buildChildKeyPath(Purpose45, 0x80000200, 0x80000003, 0x80000000, 0, 0)
 	fmt.Println("childKeyPath", childKeyPath)
@@ -17,4 +36,577 @@ func TestParseChildKeyPath(t *testing.T) {
 		panic(err)
 	}
 	fmt.Println(keyPath)
+	if keyPath.String() != "m/45'/512'/3'/0'/0/0" || formatChildKeyPath(keyPath) != childKeyPath {
+		t.Fatalf("keyPath=%s", keyPath.String())
+	}
+
+	// 标准写法与兼容写法
+	for in, want := range map[string]string{
+		"/44/60/0/0/0":       "/44/60/0/0/0",
+		"m/44'/60'/0'/0/0":   "/44/60/0/0/0",
+		"m/44h/60h/0h/1/5":   "/44/60/0/1/5",
+		"m/44'/60'/0'/0/0'":  "m/44'/60'/0'/0/0'",
+		"m/44'/60'/0'/0/0/7": "m/44'/60'/0'/0/0/7",
+		"/44'/60'/0'/0/0":    "/44/60/0/0/0",
+		"/44h/60h/0h/0/0'":   "m/44'/60'/0'/0/0'",
+	} {
+		keyPath, err := parseChildKeyPath(in)
+		if err != nil {
+			t.Fatalf("%s err:%v", in, err)
+		}
+		if formatChildKeyPath(keyPath) != want {
+			t.Fatalf("%s => %s, want %s", in, formatChildKeyPath(keyPath), want)
+		}
+	}
+	for _, in := range []string{"", "/44/x/0/0/0", "/44/60/0/0/2147483648", "m/44'/60'//0"} {
+		if _, err := parseChildKeyPath(in); err == nil {
+			t.Fatalf("%s should be invalid", in)
+		}
+	}
+}
+
+// testChain 测试使用的链，使用p256算法
+type testChain struct {
+	p256.Algorithm
+	chainInfo *ChainInfo
+}
+
+func newTestChain(name string, chainType uint32) *testChain {
+	return &testChain{
+		chainInfo: &ChainInfo{
+			ChainName:     name,
+			ChainType:     chainType,
+			AlgorithmName: "P256",
+			Algorithm:     0x80000100,
+		},
+	}
+}
+
+func (c *testChain) ChainInfo() *ChainInfo {
+	return c.chainInfo
+}
+
+func (c *testChain) ExportPrivateKey(priKey []byte, isCompressPubKey bool) (string, error) {
+	return hex.EncodeToString(priKey), nil
+}
+
+func (c *testChain) GetAddressFromPubKey(pubKey []byte) (string, error) {
+	if len(pubKey) == 0 {
+		return "", fmt.Errorf("pubKey is empty")
+	}
+	return c.chainInfo.ChainName + "-" + hex.EncodeToString(address.Ripemd160Hash(pubKey)), nil
+}
+
+func (c *testChain) SignToStr(priKey []byte, hash []byte) (string, error) {
+	signature, err := c.Sign(priKey, hash)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signature.VRight()), nil
+}
+
+func TestWallet_CreateAccountAndSign(t *testing.T) {
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetIsSaveSubKey(true)
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := scrypt.Keccak256([]byte("hello"))
+	sign, err := wallet.Sign(addr, keyPath, hash, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sign) != 130 {
+		t.Fatalf("sign=%s, want 65 bytes hex", sign)
+	}
+}
+
+// captureChain 记录签名时传入的私钥，用于校验私钥在签名后被清零
+type captureChain struct {
+	*testChain
+	priKey []byte
+}
+
+func (c *captureChain) SignToStr(priKey []byte, hash []byte) (string, error) {
+	c.priKey = priKey
+	return c.testChain.SignToStr(priKey, hash)
+}
+
+func TestWallet_SignZeroesKey(t *testing.T) {
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &captureChain{testChain: newTestChain("T1", bip32.ParseHDNum(1))}
+	addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.Sign(addr, keyPath, scrypt.Keccak256([]byte("hello")), api); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.priKey) != 32 {
+		t.Fatalf("priKey len=%d, want 32", len(api.priKey))
+	}
+	if !secret.IsZero(api.priKey) {
+		t.Fatalf("priKey should be zeroed after sign")
+	}
+
+	priKey, err := wallet.GetPriKeyFromAddress(addr, keyPath, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.IsZero(priKey) {
+		t.Fatalf("exported priKey should not be zero")
+	}
+	secret.Zero(priKey)
+	if !secret.IsZero(priKey) {
+		t.Fatalf("priKey should be zeroed")
+	}
+
+	master := wallet.Key
+	wallet.Lock()
+	if !secret.IsZero(master.Key) || !secret.IsZero(master.ChainCode) {
+		t.Fatalf("master key should be zeroed after lock")
+	}
+}
+
+func TestWallet_CreateAccountByPath(t *testing.T) {
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 标准写法与兼容写法指向同一个私钥
+	hash := scrypt.Keccak256([]byte("hello"))
+	sign1, err := wallet.Sign(addr, keyPath, hash, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign2, err := wallet.Sign(addr, "m/44'/1'/0'/0/0", hash, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sign1 != sign2 {
+		t.Fatalf("sign should be same")
+	}
+	addr2, keyPath2, err := wallet.CreateAccountByPath("m/44h/1h/0h/0/0", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr2 != addr || keyPath2 != keyPath {
+		t.Fatalf("addr=%s keyPath=%s, want %s %s", addr2, keyPath2, addr, keyPath)
+	}
+
+	// 任意深度的路径
+	addr3, keyPath3, err := wallet.CreateAccountByPath("m/44'/1'/0'/0/0/7", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyPath3 != "m/44'/1'/0'/0/0/7" || addr3 == addr {
+		t.Fatalf("addr=%s keyPath=%s", addr3, keyPath3)
+	}
+	if _, err := wallet.ExportRawKey(addr3, keyPath3, api); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.ExportRawKey(addr3, "m/44'/1'/0'/0/0/8", api); err == nil {
+		t.Fatalf("export with wrong path should fail")
+	}
+	if _, _, err := wallet.CreateAccountByPath("m/44'/1'/x", api); err == nil {
+		t.Fatalf("invalid path should fail")
+	}
+}
+
+func TestWallet_DeriveAddresses(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	derived, err := wallet.DeriveAddresses("m/44'/1'/0'/0/*", 3, 10, true, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(derived) != 10 {
+		t.Fatalf("derived len=%d, want 10", len(derived))
+	}
+	// 未设置IsSaveSubKey时不保存子私钥
+	for pubKeyStr, info := range wallet.ChildKeyInfo {
+		if info.Key != nil {
+			t.Fatalf("sub key of %s should not be saved", pubKeyStr)
+		}
+	}
+	legacy, err := wallet.DeriveAddresses("/44/1/0/0/*", 3, 10, false, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range derived {
+		addr, keyPath, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, uint32(3+i), api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Address != addr || d.KeyPath != keyPath || legacy[i].Address != addr {
+			t.Fatalf("derived[%d]=%s %s, want %s %s", i, d.Address, d.KeyPath, addr, keyPath)
+		}
+	}
+
+	// 记录的地址在重新加载后依然可以签名
+	loaded, err := LoadWalletFromStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts, _ := loaded.ListAccount()
+	if len(accounts) != 10 {
+		t.Fatalf("accounts=%d, want 10", len(accounts))
+	}
+	if _, err := loaded.Sign(derived[9].Address, derived[9].KeyPath, scrypt.Keccak256([]byte("hello")), api); err != nil {
+		t.Fatal(err)
+	}
+
+	hardened, err := wallet.DeriveAddresses("m/44'/1'/*'/0/0", 0, 2, false, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hardened[1].KeyPath != "/44/1/1/0/0" {
+		t.Fatalf("keyPath=%s", hardened[1].KeyPath)
+	}
+	for _, template := range []string{"m/44'/1'/0'/0/0", "m/44'/1'/*/0/*", "m/44'/1'/0'/0/*x"} {
+		if _, err := wallet.DeriveAddresses(template, 0, 1, false, api); err == nil {
+			t.Fatalf("template %s should be invalid", template)
+		}
+	}
+	if _, err := wallet.DeriveAddresses("m/44'/1'/0'/0/*", bip32.FirstHardenedChild-1, 2, false, api); err == nil {
+		t.Fatalf("index beyond the limit should be invalid")
+	}
+}
+
+// failTestChain 第fail次获取地址时返回错误
+type failTestChain struct {
+	*testChain
+	calls int32
+	fail  int32
+}
+
+func (c *failTestChain) GetAddressFromPubKey(pubKey []byte) (string, error) {
+	if atomic.AddInt32(&c.calls, 1) == c.fail {
+		return "", fmt.Errorf("get address failed")
+	}
+	return c.testChain.GetAddressFromPubKey(pubKey)
+}
+
+func TestWallet_DeriveAddressesFailed(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	derived, err := wallet.DeriveAddresses("m/44'/1'/0'/0/*", 0, 3, true, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.SetAccountLabel(derived[0].Address, "hot"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 部分地址派生失败时不记录任何地址
+	failApi := &failTestChain{testChain: api, fail: 5}
+	if _, err := wallet.DeriveAddresses("m/44'/1'/0'/0/*", 0, 10, true, failApi); err == nil {
+		t.Fatal("derive should fail")
+	}
+	accounts, _ := wallet.ListAccount()
+	if len(accounts) != 3 {
+		t.Fatalf("accounts=%d after derive failed, want 3", len(accounts))
+	}
+
+	// 写入存储失败时撤销记录，已有账户的元数据保持不变
+	item, err := store.Get("w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put("w1", item.Data, item.Version); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.DeriveAddresses("m/44'/1'/0'/0/*", 0, 10, true, api); err == nil {
+		t.Fatal("derive should fail on version conflict")
+	}
+	accounts, _ = wallet.ListAccount()
+	if len(accounts) != 3 || len(wallet.ChildKeyInfo) != 3 || len(wallet.AccountMetas) != 3 {
+		t.Fatalf("accounts=%d infos=%d metas=%d after save failed, want 3", len(accounts), len(wallet.ChildKeyInfo), len(wallet.AccountMetas))
+	}
+	meta, err := wallet.GetAccountMeta(derived[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Label != "hot" || meta.KeyPath != derived[0].KeyPath {
+		t.Fatalf("meta=%+v after save failed", meta)
+	}
+}
+
+func TestWallet_OptionalKeyPath(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	hash := scrypt.Keccak256([]byte("hello"))
+
+	// 未保存子私钥的账户同样记录路径
+	addr1, keyPath1, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetIsSaveSubKey(true)
+	addr2, keyPath2, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 1, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadWalletFromStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ addr, keyPath string }{{addr1, keyPath1}, {addr2, keyPath2}} {
+		want, err := loaded.Sign(tc.addr, tc.keyPath, hash, api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sign, err := loaded.Sign(tc.addr, "", hash, api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sign != want {
+			t.Fatalf("sign without keyPath is diff")
+		}
+		if _, err := loaded.ExportRawKey(tc.addr, "", api); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loaded.ExportExtendedKey(tc.addr, "", api); err != nil {
+			t.Fatal(err)
+		}
+		keyStore, err := loaded.ExportKeyStore(tc.addr, "", "654321", api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := scrypt.DecryptKey([]byte(keyStore), "654321"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 传入的路径需要与记录的路径一致
+	if _, err := loaded.Sign(addr2, "m/44'/1'/0'/0/1", hash, api); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loaded.Sign(addr2, keyPath1, hash, api); err != ErrKeyPathMismatch {
+		t.Fatalf("err=%v, want ErrKeyPathMismatch", err)
+	}
+	if _, err := loaded.Sign("unknown", "", hash, api); err == nil {
+		t.Fatalf("unknown address without keyPath should fail")
+	}
+
+	// 保存了子私钥时不再重新派生
+	loaded, err = LoadWalletFromStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loaded.ExportRawKey(addr2, "", api); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.cache.len() != 0 {
+		t.Fatalf("saved subKey should not be derived again")
+	}
+}
+
+func TestWallet_LegacyKeyPath(t *testing.T) {
+	store := NewMemoryStorage()
+	wallet, err := NewWalletWithStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetIsSaveSubKey(true)
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	hash := scrypt.Keccak256([]byte("hello"))
+	addr0, keyPath0, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr1, _, err := wallet.CreateAccount(bip44.Purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 1, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟旧版本钱包：账户未记录路径，addr0的子私钥中也未保存路径
+	info0 := wallet.ChildKeyInfo[wallet.AddrLinkPubkey[addr0]]
+	key, err := scrypt.DecryptKey(info0.Key, getSubPwd("123456", keyPath0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.Path = ""
+	if info0.Key, err = scrypt.EncryptKey(key, getSubPwd("123456", keyPath0), scryptN, scryptP); err != nil {
+		t.Fatal(err)
+	}
+	info0.KeyPath = ""
+	wallet.ChildKeyInfo[wallet.AddrLinkPubkey[addr1]].KeyPath = ""
+	if err := wallet.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadWalletFromStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 使用子私钥中保存的路径
+	if _, err := loaded.Sign(addr1, "", hash, api); err != nil {
+		t.Fatal(err)
+	}
+	// 没有任何记录时需要传入keyPath，校验通过后补充记录
+	if _, err := loaded.Sign(addr0, "", hash, api); err == nil {
+		t.Fatalf("sign without any keyPath should fail")
+	}
+	if _, err := loaded.Sign(addr0, "m/44'/1'/0'/0/0", hash, api); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = LoadWalletFromStorage(store, "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyPath := loaded.ChildKeyInfo[loaded.AddrLinkPubkey[addr0]].KeyPath; keyPath != keyPath0 {
+		t.Fatalf("keyPath=%s, want %s", keyPath, keyPath0)
+	}
+	if _, err := loaded.Sign(addr0, "", hash, api); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWalletOptions_Curve(t *testing.T) {
+	// 曲线为钱包创建时的选项，同一进程中的钱包可以使用不同的曲线
+	s256, err := LoadWalletFromMnemonicWithStorageAndOptions(NewMemoryStorage(), "w1", "123456", testMnemonic, false, &WalletOptions{Curve: bip32.CurveSecp256k1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256, err := LoadWalletFromMnemonicWithStorage(NewMemoryStorage(), "w2", "123456", testMnemonic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// BIP32的根扩展私钥
+	want := "xprv9s21ZrQH143K3GJpoapnV8SFfukcVBSfeCficPSGfubmSFDxo1kuHnLisriDvSnRRuL2Qrg5ggqHKNVpxR86QEC8w35uxmGoggxtQTPvfUu"
+	if got := s256.ExportMasterExtendedKey(); got != want {
+		t.Fatalf("secp256k1 xprv=%s, want %s", got, want)
+	}
+	if got := p256.ExportMasterExtendedKey(); got == want {
+		t.Fatalf("p256 wallet should not use secp256k1")
+	}
+	if _, err := NewWalletWithStorageAndOptions(NewMemoryStorage(), "w3", "123456", &WalletOptions{Curve: "ed25519"}); err == nil {
+		t.Fatalf("unknown curve should fail")
+	}
+}
+
+func TestWallet_MnemonicType(t *testing.T) {
+	types := []MnemonicType{MnemonicType_English, MnemonicType_Japanese, MnemonicType_Korean, MnemonicType_Spanish}
+	var wg sync.WaitGroup
+	errs := make([]error, len(types))
+	for i, mnemonicType := range types {
+		wg.Add(1)
+		go func(i int, mnemonicType MnemonicType) {
+			defer wg.Done()
+			store := NewMemoryStorage()
+			wallet, err := NewWalletWithStorageAndMnemonicType(store, "w1", "123456", mnemonicType)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if got, err := DetectMnemonicType(wallet.ExportMasterMnemonic()); err != nil || got != mnemonicType {
+				errs[i] = fmt.Errorf("got=%s err=%v, want %s", got, err, mnemonicType)
+				return
+			}
+			// 恢复时自动检测助记词类型
+			loaded, err := LoadWalletFromMnemonicWithStorage(NewMemoryStorage(), "w2", "123456", wallet.ExportMasterMnemonic(), true)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if loaded.ExportMasterExtendedKey() != wallet.ExportMasterExtendedKey() {
+				errs[i] = fmt.Errorf("%s wallet should be restored from mnemonic", mnemonicType)
+			}
+		}(i, mnemonicType)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := LoadWalletFromMnemonicWithStorage(NewMemoryStorage(), "w1", "123456", "unknown words", false); err == nil {
+		t.Fatal("load wallet with invalid mnemonic should fail")
+	}
+}
+
+// purposeTestChain 地址带有purpose前缀的测试链
+type purposeTestChain struct {
+	*testChain
+}
+
+func (c *purposeTestChain) GetAddressFromPubKeyWithPurpose(pubKey []byte, purpose uint32) (string, error) {
+	addr, err := c.GetAddressFromPubKey(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%s", purpose-bip32.FirstHardenedChild, addr), nil
+}
+
+func TestWallet_CreateAccountWithPurpose(t *testing.T) {
+	wallet, err := LoadWalletFromMnemonicWithStorage(NewMemoryStorage(), "w1", "123456", testMnemonic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.SetIsSaveSubKey(true)
+	api := &purposeTestChain{newTestChain("T1", bip32.ParseHDNum(1))}
+	for _, purpose := range []uint32{Purpose44, Purpose49, Purpose84, Purpose86} {
+		addr, keyPath, err := wallet.CreateAccount(purpose, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), 0, 0, api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prefix := fmt.Sprintf("%d-", purpose-bip32.FirstHardenedChild)
+		if addr[:len(prefix)] != prefix {
+			t.Fatalf("purpose %s address got %s", prefix, addr)
+		}
+		if keyPath != fmt.Sprintf("/%d/1/0/0/0", purpose-bip32.FirstHardenedChild) {
+			t.Fatalf("keyPath got %s", keyPath)
+		}
+		if _, err := wallet.Sign(addr, "", scrypt.Keccak256([]byte("hello")), api); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 只读钱包按purpose生成相同的地址
+	xpubPath, xpub, err := wallet.ExportAccountExtendedKey(Purpose84, bip32.ParseHDNum(1), 0, bip32.ParseHDNum(0), false, chain.MainNet, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watch, err := NewWatchOnlyWalletWithStorage(NewMemoryStorage(), "w2", "123456", map[string]string{xpubPath: xpub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _, err := wallet.CreateAccountByPath("m/84'/1'/0'/0/1", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watchAddr, _, err := watch.CreateAccountByPath("m/84'/1'/0'/0/1", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watchAddr != addr {
+		t.Fatalf("watch-only address got %s, want %s", watchAddr, addr)
+	}
 }
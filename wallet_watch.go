@@ -0,0 +1,389 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/chain"
+)
+
+var (
+	ErrWatchOnly     = errors.New("watch-only wallet holds no private key")
+	ErrXpubNotFound  = errors.New("extended public key not found for path")
+	ErrCurveMismatch = errors.New("wallet curve does not match the chain algorithm, s256 chains need a wallet created with the secp256k1 curve")
+)
+
+// NewWatchOnlyWallet 使用扩展公钥创建只读钱包，钱包已存在时直接加载
+// xpubs key为扩展公钥对应的路径，如账户扩展公钥使用/44/60/0，主扩展公钥使用空字符串
+func NewWatchOnlyWallet(path string, password string, xpubs map[string]string) (*Wallet, error) {
+	// 参数检查
+	if len(path) == 0 {
+		return nil, fmt.Errorf("NewWatchOnlyWallet path parameter error")
+	}
+	store, name := fileStorageOf(path)
+	return newWatchOnlyWallet(store, name, path, password, xpubs)
+}
+
+// NewWatchOnlyWalletWithStorage 在存储后端中创建只读钱包，name已存在时直接加载
+func NewWatchOnlyWalletWithStorage(store Storage, name string, password string, xpubs map[string]string) (*Wallet, error) {
+	// 参数检查
+	if nil == store || len(name) == 0 {
+		return nil, fmt.Errorf("NewWatchOnlyWallet storage parameter error")
+	}
+	return newWatchOnlyWallet(store, name, name, password, xpubs)
+}
+
+func newWatchOnlyWallet(store Storage, name string, path string, password string, xpubs map[string]string) (*Wallet, error) {
+	// 判断钱包是否存在
+	item, err := store.Get(name)
+	if err == nil {
+		return readWalletFromStorage(store, item, path, password)
+	}
+	if err != ErrStorageNotFound {
+		return nil, fmt.Errorf("NewWatchOnlyWallet storage.Get err:%v", err.Error())
+	}
+	if len(xpubs) == 0 {
+		return nil, fmt.Errorf("NewWatchOnlyWallet xpubs is empty")
+	}
+	wallet := newWallet(store, name)
+	wallet.WatchOnly = true
+	wallet.AccountXpubs = make(map[string]string, len(xpubs))
+	for keyPath, xpub := range xpubs {
+		keyPath, err := normalizeXpubPath(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkXpub(xpub); err != nil {
+			return nil, err
+		}
+		wallet.AccountXpubs[keyPath] = xpub
+	}
+	return initWallet(wallet, path, password)
+}
+
+// IsWatchOnly 是否为只读钱包
+func (w *Wallet) IsWatchOnly() bool {
+	return w.WatchOnly
+}
+
+// AddAccountXpub 为只读钱包添加扩展公钥
+func (w *Wallet) AddAccountXpub(keyPath string, xpub string) error {
+	if !w.WatchOnly {
+		return fmt.Errorf("wallet AddAccountXpub only for watch-only wallet")
+	}
+	keyPath, err := normalizeXpubPath(keyPath)
+	if err != nil {
+		return err
+	}
+	if err := checkXpub(xpub); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	if w.locked {
+		w.mu.Unlock()
+		return ErrWalletLocked
+	}
+	w.AccountXpubs[keyPath] = xpub
+	w.mu.Unlock()
+	return w.save()
+}
+
+// ExportAccountXpub 导出账户的扩展公钥，用于在线服务器创建只读钱包
+// 钱包的曲线需要与链的算法一致，否则扩展公钥派生的地址与私钥不对应
+// 默认p256创建的钱包导出s256链（ETH、BTC）的扩展公钥时返回ErrCurveMismatch，需要使用secp256k1创建钱包
+func (w *Wallet) ExportAccountXpub(purpose, coinType, org, _account uint32, api ChainAPI) (keyPath string, xpub string, err error) {
+	keyPath, key, err := w.accountKey(purpose, coinType, org, _account, api)
+	if err != nil {
+		return "", "", err
+	}
+	defer key.Zero()
+	if !key.IsPrivate {
+		// 只读钱包直接返回保存的扩展公钥
+		w.mu.RLock()
+		defer w.mu.RUnlock()
+		return keyPath, w.AccountXpubs[keyPath], nil
+	}
+	return keyPath, key.PublicKey().String(), nil
+}
+
+// ExportAccountExtendedKey 导出账户层级（m/purpose'/coin'/account'）的扩展密钥
+// 版本号按SLIP-0132根据purpose及网络类型选择，如purpose=84时主网导出zpub/zprv，测试网导出vpub/vprv
+// coinType 为0时使用链的币种类型
+// isPrivate 是否导出扩展私钥，只读钱包只能导出扩展公钥
+func (w *Wallet) ExportAccountExtendedKey(purpose, coinType, org, _account uint32, isPrivate bool, networkType chain.NetworkType, api ChainAPI) (keyPath string, extendedKey string, err error) {
+	if isPrivate {
+		defer func() {
+			if err = w.audit(AuditExportAccountKey, "", keyPath, nil, err); err != nil {
+				extendedKey = ""
+			}
+		}()
+	}
+	keyPath, key, err := w.accountKey(purpose, coinType, org, _account, api)
+	if err != nil {
+		return "", "", err
+	}
+	defer key.Zero()
+	if isPrivate && !key.IsPrivate {
+		return "", "", ErrWatchOnly
+	}
+	if !isPrivate {
+		key = key.PublicKey()
+	}
+	key, err = key.WithVersion(bip32.ScriptTypeForPurpose(purpose), networkType != chain.MainNet)
+	if err != nil {
+		return "", "", err
+	}
+	defer key.Zero()
+	return keyPath, key.String(), nil
+}
+
+// 获取账户层级的密钥，只读钱包返回保存的扩展公钥，调用方使用完后需要清零
+func (w *Wallet) accountKey(purpose, coinType, org, _account uint32, api ChainAPI) (string, *bip32.Key, error) {
+	if api == nil {
+		return "", nil, fmt.Errorf("wallet ExportAccountXpub chainApi is nil")
+	}
+	coinType = defaultCoinType(coinType, api)
+	keyPath, err := buildAccountKeyPath(purpose, coinType, org, _account)
+	if err != nil {
+		return "", nil, err
+	}
+	curve, err := chainCurve(api)
+	if err != nil {
+		return "", nil, err
+	}
+	if w.WatchOnly {
+		w.mu.RLock()
+		xpub, ok := w.AccountXpubs[keyPath]
+		w.mu.RUnlock()
+		if !ok {
+			return "", nil, ErrXpubNotFound
+		}
+		key, err := bip32.B58DeserializeWithCurve(xpub, curve)
+		if err != nil {
+			return "", nil, err
+		}
+		return keyPath, key, nil
+	}
+
+	mKey, _, err := w.unlockedSecrets()
+	if err != nil {
+		return "", nil, err
+	}
+	defer mKey.Zero()
+	if keyCurve(mKey) != curve {
+		return "", nil, ErrCurveMismatch
+	}
+	key, err := bip44.NewAccountKeyFromMasterKeyWithOrg(mKey, purpose, coinType, org, _account)
+	if err != nil {
+		return "", nil, fmt.Errorf("wallet ExportAccountXpub bip44.NewAccountKeyFromMasterKey err:%v", err.Error())
+	}
+	return keyPath, key, nil
+}
+
+// 只读钱包通过扩展公钥派生地址并记录
+func (w *Wallet) createWatchAccount(keyPath string, purpose, coinType, org uint32, api ChainAPI) (addr string, retKeyPath string, err error) {
+	if _, err := w.unlockedPassword(); err != nil {
+		return "", "", err
+	}
+	addr, pubKey, err := w.deriveWatchAddress(keyPath, api)
+	if err != nil {
+		return "", "", err
+	}
+	w.recordWatchAccount(purpose, coinType, org, addr, keyPath, pubKey, api)
+	if err := w.save(); err != nil {
+		return "", "", fmt.Errorf("wallet CreateAccount storage.Put err:%v", err.Error())
+	}
+	return addr, keyPath, nil
+}
+
+// 通过扩展公钥派生路径对应的地址及公钥
+func (w *Wallet) deriveWatchAddress(keyPath string, api ChainAPI) (addr string, pubKey []byte, err error) {
+	key, err := w.derivePublicKey(keyPath, api)
+	if err != nil {
+		return "", nil, err
+	}
+	pubKey, err = key.UncompressedPublicKey()
+	if err != nil {
+		return "", nil, err
+	}
+	childKeyPath, err := parseChildKeyPath(keyPath)
+	if err != nil {
+		return "", nil, err
+	}
+	purpose, _, _ := childKeyPathProperties(childKeyPath)
+	addr, err = getAddressFromPubKey(api, pubKey, purpose)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(addr) == 0 {
+		return "", nil, fmt.Errorf("addr is empty")
+	}
+	return addr, pubKey, nil
+}
+
+// 只读钱包只记录地址与公钥，不包含子私钥
+func (w *Wallet) recordWatchAccount(purpose, coinType, org uint32, addr, keyPath string, pubKey []byte, api ChainAPI) {
+	childKeyPropertyInfo := &ChildKeyPropertyInfo{
+		Purpose:       purpose,
+		ChainType:     api.ChainInfo().ChainType,
+		AlgorithmType: api.ChainInfo().Algorithm,
+		Org:           org,
+		CoinType:      coinType,
+		Time:          uint32(time.Now().Unix()),
+		KeyPath:       keyPath,
+	}
+	w.mu.Lock()
+	w.recordChildKeyInfoLocked(addr, w.getPubKey(pubKey), childKeyPropertyInfo, api)
+	w.mu.Unlock()
+}
+
+// 使用路径最长匹配的扩展公钥派生子公钥，剩余路径只能是非强化派生
+func (w *Wallet) derivePublicKey(keyPath string, api ChainAPI) (*bip32.Key, error) {
+	curve, err := chainCurve(api)
+	if err != nil {
+		return nil, err
+	}
+	childKeyPath, err := parseChildKeyPath(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	depth, xpub, ok := w.findXpub(childKeyPath)
+	if !ok {
+		return nil, ErrXpubNotFound
+	}
+	key, err := bip32.B58DeserializeWithCurve(xpub, curve)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range childKeyPath[depth:] {
+		key, err = key.NewChildKey(index)
+		if err != nil {
+			return nil, fmt.Errorf("wallet derivePublicKey %s err:%v", keyPath, err.Error())
+		}
+	}
+	return key, nil
+}
+
+// 查找路径最长匹配的扩展公钥，depth为扩展公钥所在的层级
+func (w *Wallet) findXpub(childKeyPath bip32.DerivationPath) (depth int, xpub string, ok bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for p, x := range w.AccountXpubs {
+		prefix, err := xpubDerivationPath(p)
+		if err != nil || !hasPathPrefix(childKeyPath, prefix) {
+			continue
+		}
+		if !ok || len(prefix) > depth {
+			depth, xpub, ok = len(prefix), x, true
+		}
+	}
+	return depth, xpub, ok
+}
+
+func hasPathPrefix(path, prefix bip32.DerivationPath) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// 账户层级的路径，如/44/60/0
+func buildAccountKeyPath(purpose, coinType, org, _account uint32) (string, error) {
+	keyPath, err := buildChildKeyPath(purpose, coinType, org, _account, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(keyPath, "/0/0"), nil
+}
+
+// 格式化扩展公钥的路径，主扩展公钥的路径为空
+// 扩展公钥的路径均为强化派生，支持/44/60/0及m/44'/60'/0'写法，统一格式化为/44/60/0
+func normalizeXpubPath(keyPath string) (string, error) {
+	keyPath = strings.TrimSpace(keyPath)
+	if strings.ContainsAny(keyPath, "'hH") {
+		childKeyPath, err := bip32.ParseDerivationPath(keyPath)
+		if err != nil {
+			return "", fmt.Errorf("wallet xpub path %s is invalid", keyPath)
+		}
+		path := ""
+		for i, index := range childKeyPath {
+			if !childKeyPath.IsHardened(i) {
+				return "", fmt.Errorf("wallet xpub path %s should be hardened", keyPath)
+			}
+			path += "/" + strconv.FormatUint(uint64(index-bip32.FirstHardenedChild), 10)
+		}
+		return path, nil
+	}
+	keyPath = strings.TrimSuffix(strings.TrimPrefix(keyPath, "m"), "/")
+	if keyPath == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(keyPath, "/") {
+		return "", fmt.Errorf("wallet xpub path %s is invalid", keyPath)
+	}
+	if _, err := xpubDerivationPath(keyPath); err != nil {
+		return "", fmt.Errorf("wallet xpub path %s is invalid", keyPath)
+	}
+	return keyPath, nil
+}
+
+// 扩展公钥路径对应的强化派生路径
+func xpubDerivationPath(keyPath string) (bip32.DerivationPath, error) {
+	if keyPath == "" {
+		return bip32.DerivationPath{}, nil
+	}
+	arrPath := strings.Split(strings.TrimPrefix(keyPath, "/"), "/")
+	childKeyPath := make(bip32.DerivationPath, 0, len(arrPath))
+	for _, p := range arrPath {
+		index, err := strconv.ParseUint(p, 10, 31)
+		if err != nil {
+			return nil, err
+		}
+		childKeyPath = append(childKeyPath, uint32(index)+bip32.FirstHardenedChild)
+	}
+	return childKeyPath, nil
+}
+
+func checkXpub(xpub string) error {
+	key, err := bip32.B58Deserialize(xpub)
+	if err != nil {
+		return fmt.Errorf("wallet xpub is invalid err:%v", err.Error())
+	}
+	if key.IsPrivate {
+		return fmt.Errorf("wallet xpub should be an extended public key")
+	}
+	return nil
+}
+
+// 链算法对应的bip32曲线
+func chainCurve(api ChainAPI) (bip32.Curve, error) {
+	switch strings.ToUpper(api.ChainInfo().AlgorithmName) {
+	case "S256":
+		return bip32.CurveSecp256k1, nil
+	case "P256":
+		return bip32.CurveP256, nil
+	}
+	return "", fmt.Errorf("chain algorithm %s does not support extended public key", api.ChainInfo().AlgorithmName)
+}
+
+func keyCurve(key *bip32.Key) bip32.Curve {
+	curve, err := bip32.ParseCurve(string(key.Curve))
+	if err != nil {
+		return key.Curve
+	}
+	return curve
+}
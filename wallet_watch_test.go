@@ -0,0 +1,242 @@
+// description: keybox
+//
+// @author: xwc1125
+// @date: 2026/10/19 0019
+package keybox
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/chain5j/keybox/algorithm/s256"
+	"github.com/chain5j/keybox/bip32"
+	"github.com/chain5j/keybox/bip44"
+	"github.com/chain5j/keybox/chain"
+	"github.com/chain5j/keybox/crypto/scrypt"
+)
+
+// s256TestChain 测试使用的链，使用s256算法
+type s256TestChain struct {
+	s256.Algorithm
+	*testChain
+}
+
+func newS256TestChain(name string, chainType uint32) *s256TestChain {
+	c := &s256TestChain{testChain: newTestChain(name, chainType)}
+	c.chainInfo.AlgorithmName = "S256"
+	c.chainInfo.Algorithm = 0x80000200
+	return c
+}
+
+func (c *s256TestChain) GetPubKeyFromPriKey(priKey []byte) ([]byte, error) {
+	return c.Algorithm.GetPubKeyFromPriKey(priKey)
+}
+
+func (c *s256TestChain) SignToStr(priKey []byte, hash []byte) (string, error) {
+	signature, err := c.Algorithm.Sign(priKey, hash)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signature.VRight()), nil
+}
+
+func TestWatchOnlyWallet(t *testing.T) {
+	for _, tc := range []struct {
+		curve bip32.Curve
+		api   ChainAPI
+	}{
+		{bip32.CurveP256, newTestChain("T1", bip32.ParseHDNum(1))},
+		{bip32.CurveSecp256k1, newS256TestChain("T2", bip32.ParseHDNum(2))},
+	} {
+		api := tc.api
+		coinType := api.ChainInfo().ChainType
+		wallet, err := NewWalletWithStorageAndOptions(NewMemoryStorage(), "w1", "123456", &WalletOptions{Curve: tc.curve})
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyPath, xpub, err := wallet.ExportAccountXpub(bip44.Purpose, coinType, 0, bip32.ParseHDNum(0), api)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		store := NewMemoryStorage()
+		watch, err := NewWatchOnlyWalletWithStorage(store, "watch", "654321", map[string]string{keyPath: xpub})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !watch.IsWatchOnly() {
+			t.Fatalf("wallet should be watch-only")
+		}
+		for _, change := range []uint32{0, 1} {
+			want, _, err := wallet.CreateAccount(bip44.Purpose, coinType, 0, bip32.ParseHDNum(0), change, 5, api)
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr, childPath, err := watch.CreateAccount(bip44.Purpose, coinType, 0, bip32.ParseHDNum(0), change, 5, api)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr != want {
+				t.Fatalf("curve %s watch-only addr=%s, want %s", tc.curve, addr, want)
+			}
+			if _, err := watch.Sign(addr, childPath, scrypt.Keccak256([]byte("hello")), api); err != ErrWatchOnly {
+				t.Fatalf("watch-only sign err=%v, want ErrWatchOnly", err)
+			}
+			if _, err := watch.ExportRawKey(addr, childPath, api); err != ErrWatchOnly {
+				t.Fatalf("watch-only export err=%v, want ErrWatchOnly", err)
+			}
+		}
+		// 标准写法的扩展公钥路径，以及任意深度的路径
+		accountPath := fmt.Sprintf("m/44'/%d'/0'", coinType-bip32.FirstHardenedChild)
+		watch2, err := NewWatchOnlyWalletWithStorage(NewMemoryStorage(), "watch2", "654321", map[string]string{accountPath: xpub})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _, err := wallet.CreateAccountByPath(accountPath+"/0/5/3", api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, _, err := watch2.CreateAccountByPath(accountPath+"/0/5/3", api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != want {
+			t.Fatalf("curve %s watch-only addr=%s, want %s", tc.curve, addr, want)
+		}
+		if _, _, err := watch2.CreateAccountByPath(accountPath+"/0/5'", api); err == nil {
+			t.Fatalf("watch-only wallet should refuse hardened derivation")
+		}
+		wantDerived, err := wallet.DeriveAddresses(accountPath+"/1/*", 0, 3, false, api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		derived, err := watch2.DeriveAddresses(accountPath+"/1/*", 0, 3, true, api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range derived {
+			if *derived[i] != *wantDerived[i] {
+				t.Fatalf("curve %s watch-only derived=%v, want %v", tc.curve, derived[i], wantDerived[i])
+			}
+		}
+		if _, _, err := watch.CreateAccount(bip44.Purpose, coinType, 0, bip32.ParseHDNum(1), 0, 0, api); err != ErrXpubNotFound {
+			t.Fatalf("create account without xpub err=%v, want ErrXpubNotFound", err)
+		}
+		if watch.ExportMasterExtendedKey() != "" {
+			t.Fatalf("watch-only wallet should not export master key")
+		}
+
+		// 重新加载后账户及扩展公钥依然存在
+		loaded, err := LoadWalletFromStorage(store, "watch", "654321")
+		if err != nil {
+			t.Fatal(err)
+		}
+		accounts, _ := loaded.ListAccount()
+		if !loaded.IsWatchOnly() || len(accounts) != 2 {
+			t.Fatalf("loaded watch-only=%v accounts=%d, want 2", loaded.IsWatchOnly(), len(accounts))
+		}
+	}
+
+	// p256的钱包无法导出s256链使用的扩展公钥
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w2", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := wallet.ExportAccountXpub(bip44.Purpose, bip32.ParseHDNum(2), 0, bip32.ParseHDNum(0), newS256TestChain("T2", bip32.ParseHDNum(2))); err != ErrCurveMismatch {
+		t.Fatalf("export xpub err=%v, want ErrCurveMismatch", err)
+	}
+	if _, err := NewWatchOnlyWalletWithStorage(NewMemoryStorage(), "w3", "123456", map[string]string{"": wallet.ExportMasterExtendedKey()}); err == nil {
+		t.Fatalf("watch-only wallet should refuse extended private key")
+	}
+}
+
+func TestWallet_ExportXpubDefaultCurve(t *testing.T) {
+	// 默认选项创建的钱包使用p256，可以导出p256链的扩展公钥
+	wallet, err := NewWalletWithStorage(NewMemoryStorage(), "w1", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newTestChain("T1", bip32.ParseHDNum(1))
+	keyPath, xpub, err := wallet.ExportAccountXpub(bip44.Purpose, 0, 0, bip32.ParseHDNum(0), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watch, err := NewWatchOnlyWalletWithStorage(NewMemoryStorage(), "watch", "654321", map[string]string{keyPath: xpub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _, err := wallet.CreateAccount(bip44.Purpose, 0, 0, bip32.ParseHDNum(0), 0, 1, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _, err := watch.CreateAccount(bip44.Purpose, 0, 0, bip32.ParseHDNum(0), 0, 1, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != want {
+		t.Fatalf("watch-only addr=%s, want %s", addr, want)
+	}
+
+	// s256链（ETH、BTC）需要使用secp256k1创建的钱包
+	s256Api := newS256TestChain("ETH", bip32.ParseHDNum(60))
+	if _, _, err := wallet.ExportAccountXpub(bip44.Purpose, 0, 0, bip32.ParseHDNum(0), s256Api); err != ErrCurveMismatch {
+		t.Fatalf("default wallet export s256 xpub err=%v, want ErrCurveMismatch", err)
+	}
+	wallet, err = NewWalletWithStorageAndOptions(NewMemoryStorage(), "w2", "123456", &WalletOptions{Curve: bip32.CurveSecp256k1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, xpub, err = wallet.ExportAccountXpub(bip44.Purpose, 0, 0, bip32.ParseHDNum(0), s256Api); err != nil || xpub[:4] != "xpub" {
+		t.Fatalf("secp256k1 wallet export s256 xpub=%s err=%v", xpub, err)
+	}
+}
+
+func TestWallet_ExportAccountExtendedKey(t *testing.T) {
+	wallet, err := LoadWalletFromMnemonicWithStorageAndOptions(NewMemoryStorage(), "w1", "123456", testMnemonic, false, &WalletOptions{Curve: bip32.CurveSecp256k1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newS256TestChain("BTC", bip32.ParseHDNum(0))
+	// BIP84及BIP49中的测试向量
+	for _, tc := range []struct {
+		purpose     uint32
+		coinType    uint32
+		isPrivate   bool
+		networkType chain.NetworkType
+		want        string
+	}{
+		{84, 0, false, chain.MainNet, "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"},
+		{84, 0, true, chain.MainNet, "zprvAdG4iTXWBoARxkkzNpNh8r6Qag3irQB8PzEMkAFeTRXxHpbF9z4QgEvBRmfvqWvGp42t42nvgGpNgYSJA9iefm1yYNZKEm7z6qUWCroSQnE"},
+		{49, 1, false, chain.TestNet, "upub5EFU65HtV5TeiSHmZZm7FUffBGy8UKeqp7vw43jYbvZPpoVsgU93oac7Wk3u6moKegAEWtGNF8DehrnHtv21XXEMYRUocHqguyjknFHYfgY"},
+		{49, 1, true, chain.TestNet, "uprv91G7gZkzehuMVxDJTYE6tLivdF8e4rvzSu1LFfKw3b2Qx1Aj8vpoFnHdfUZ3hmi9jsvPifmZ24RTN2KhwB8BfMLTVqaBReibyaFFcTP1s9n"},
+	} {
+		keyPath, extendedKey, err := wallet.ExportAccountExtendedKey(bip32.ParseHDNum(tc.purpose), bip32.ParseHDNum(tc.coinType), 0, bip32.ParseHDNum(0), tc.isPrivate, tc.networkType, api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if extendedKey != tc.want {
+			t.Fatalf("%s extendedKey=%s, want %s", keyPath, extendedKey, tc.want)
+		}
+	}
+
+	// 只读钱包可以使用zpub创建，并导出其他版本的扩展公钥
+	keyPath, zpub, err := wallet.ExportAccountExtendedKey(bip32.ParseHDNum(84), bip32.ParseHDNum(0), 0, bip32.ParseHDNum(0), false, chain.MainNet, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watch, err := NewWatchOnlyWalletWithStorage(NewMemoryStorage(), "watch", "123456", map[string]string{keyPath: zpub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, vpub, err := watch.ExportAccountExtendedKey(bip32.ParseHDNum(84), bip32.ParseHDNum(0), 0, bip32.ParseHDNum(0), false, chain.TestNet, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpub[:4] != "vpub" {
+		t.Fatalf("testnet extendedKey=%s, want vpub", vpub)
+	}
+	if _, _, err := watch.ExportAccountExtendedKey(bip32.ParseHDNum(84), bip32.ParseHDNum(0), 0, bip32.ParseHDNum(0), true, chain.MainNet, api); err != ErrWatchOnly {
+		t.Fatalf("watch-only export private err=%v, want ErrWatchOnly", err)
+	}
+}
@@ -5,15 +5,24 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/chain5j/chain5j-pkg/util/hexutil"
 	"github.com/chain5j/keybox"
 	"github.com/chain5j/keybox/bip32"
 	"github.com/chain5j/keybox/chain"
-	"github.com/chain5j/keybox/chain/btc"
-	"github.com/chain5j/keybox/chain/eth"
+	_ "github.com/chain5j/keybox/chain/btc" // 注册BTC
+	_ "github.com/chain5j/keybox/chain/eth" // 注册ETH
+	"github.com/chain5j/keybox/slip39"
 	"github.com/spf13/cobra"
 )
 
@@ -34,18 +43,84 @@ var (
 		Short: "generate the child account",
 		Run:   runGenChild,
 	}
+	// 批量生成子账户地址
+	cmdDeriveAddresses = &cobra.Command{
+		Use:   "deriveAddresses",
+		Short: "derive the child addresses in bulk, output csv or json",
+		Run:   runDeriveAddresses,
+	}
+	// 列出账户
+	cmdListAccounts = &cobra.Command{
+		Use:   "list",
+		Short: "list the accounts, filter by label, tags, chain, network, keyPath and extra",
+		Run:   runListAccounts,
+	}
 	// 导出子账户
 	cmdExportChild = &cobra.Command{
 		Use:   "exportChild",
 		Short: "export the child account",
 		Run:   runExportChild,
 	}
+	// 导出账户扩展公钥
+	cmdExportXpub = &cobra.Command{
+		Use:   "exportXpub",
+		Short: "export the account extended key, the version is chosen by purpose and networkType",
+		Run:   runExportXpub,
+	}
 	// 使用子账户进行签名
 	cmdSign = &cobra.Command{
 		Use:   "sign",
 		Short: "use the childAccount to sign",
 		Run:   runSign,
 	}
+	// 备份钱包
+	cmdBackup = &cobra.Command{
+		Use:   "backup",
+		Short: "backup the wallet to an encrypted bundle file",
+		Run:   runBackup,
+	}
+	// 恢复钱包
+	cmdRestore = &cobra.Command{
+		Use:   "restore",
+		Short: "restore the wallet from an encrypted bundle file",
+		Run:   runRestore,
+	}
+	// 生成SLIP-0039分片助记词
+	cmdSplit = &cobra.Command{
+		Use:   "split",
+		Short: "split the master secret into SLIP-0039 shares, and create the wallet from the shares",
+		Run:   runSplit,
+	}
+	// 使用SLIP-0039分片助记词恢复钱包
+	cmdCombine = &cobra.Command{
+		Use:   "combine",
+		Short: "combine the SLIP-0039 shares to restore the wallet",
+		Run:   runCombine,
+	}
+	// BIP85派生子密钥
+	cmdBIP85 = &cobra.Command{
+		Use:   "bip85",
+		Short: "derive the BIP85 child mnemonic, wif, xprv, hex or password from the master key",
+		Run:   runBIP85,
+	}
+	// 恢复输错或缺失单词的助记词
+	cmdRecoverMnemonic = &cobra.Command{
+		Use:   "recoverMnemonic",
+		Short: "recover the mnemonic with one mistyped, missing or misplaced word, use ? for the unknown word",
+		Run:   runRecoverMnemonic,
+	}
+	// 组织管理
+	cmdOrg = &cobra.Command{
+		Use:   "org",
+		Short: "create or list the organizations of the purpose 45 layout",
+		Run:   runOrg,
+	}
+	// 校验审计日志
+	cmdAuditVerify = &cobra.Command{
+		Use:   "auditVerify",
+		Short: "verify the audit log, detect tampering or truncation",
+		Run:   runAuditVerify,
+	}
 )
 
 var (
@@ -58,28 +133,85 @@ var (
 	mnemonicType      string // 助记词类型(zh-cn简体中文,zh-tw繁体中文,en English,fr French,it Italian,ja Japanese,ko Korean,es Spanish)
 	mnemonic          string // 助记词
 	isUsePwdBlur      bool   // 是否使用Password进行混淆
+	seedType          string // 助记词的种子类型(bip39,electrum_standard,electrum_segwit,auto)，为空时为bip39
 	prvKeyBase58      string // 私钥Base58
 	networkType       string // 网络类型
+	curve             string // 新建钱包使用的曲线（p256,secp256k1）
+	xpub              string // 扩展公钥，用于创建只读钱包
+	xpubPath          string // 扩展公钥对应的路径
+	wordCount         int    // 新建钱包的助记词单词数(12,15,18,21,24)
+	entropyHex        string // 新建钱包使用的16进制熵
+	diceRolls         string // 新建钱包使用的骰子点数
+	diceSides         int    // 骰子的面数
+	coinFlips         string // 新建钱包使用的硬币正反面(0/1或H/T)
+	confirmMnemonic   bool   // 使用外部熵时跳过助记词的确认
 	// 导出主账户信息
 	exportMasterMn          bool // 导出主账户助记词
 	exportMasterRawKey      bool // 导出主账户基本私钥
 	exportMasterExtendedKey bool // 导出主账户扩展私钥
 	// 子账户部分
-	purposeType  uint32 // 生成类型（44,45）
+	purposeType  uint32 // 生成类型（44,45,49,84,86）
 	org          uint32 // purpose=45时，才使用
+	orgName      string // 组织名称，设置后使用purpose=45及组织的索引
 	coinType     uint32 // 币种类型
 	account      uint32 // 用户空间
 	addressIndex uint32 // 子账户的索引
 	chainType    string // 链类型（eth,btc）
 	childAddress string // 子账户地址
 	childKeyPath string // 子账户路径
+	// 批量生成地址
+	pathTemplate string // 路径模板，*表示地址索引
+	start        uint32 // 起始索引
+	count        uint32 // 生成数量
+	persist      bool   // 是否将地址记录到钱包中
+	outputFormat string // 输出格式（csv,json）
+	outputFile   string // 输出文件，为空时输出到标准输出
+	// 账户元数据
+	label               string            // 账户标签
+	tags                []string          // 账户分类
+	filterLabel         string            // 按标签过滤
+	filterTags          []string          // 按分类过滤
+	filterChain         string            // 按链名称过滤
+	filterNetwork       string            // 按网络类型过滤
+	filterKeyPathPrefix string            // 按路径前缀过滤
+	filterExtra         map[string]string // 按自定义键值过滤
 	// 子账户导出
 	exportChildRawKey      bool   // 导出子账户的基础私钥
 	exportChildExtendedKey bool   // 导出子账户的扩展私钥
 	exportChildKeystore    bool   // 导出子账户的keystore
 	childKeystorePwd       string // 子账户导出keystore的加密密码
+	// 账户扩展密钥导出
+	exportAccountPrivate bool // 导出账户扩展私钥
 	// 子账户签名
 	signHash string // 交易体Hash
+	rawTx    string // 未签名的交易，签名内容及策略校验的交易信息由其解析
+	// 签名策略
+	policyFile string // 签名策略文件
+	// 备份及恢复
+	backupFile string // 备份文件
+	backupPwd  string // 备份文件的加密密码
+	// SLIP-0039分片
+	groupThreshold    int      // 恢复需要的组数
+	shareGroups       []string // 组的配置，成员门限/成员数量，如2/3
+	masterSecret      string   // 16进制的主密钥，为空时随机生成
+	strength          int      // 随机生成主密钥的位数（128,256）
+	sharePassphrase   string   // SLIP-0039的密码
+	extendable        bool     // 是否可扩展
+	iterationExponent int      // PBKDF2迭代次数的指数
+	shares            []string // 分片助记词
+	// 助记词恢复
+	maxSuggestions int // 每个输错单词的建议数量
+	// BIP85
+	bip85App    string // 派生的类型（mnemonic,wif,xprv,hex,base64,base85）
+	bip85Length int    // 助记词的单词数、熵的字节数或密码的长度
+	bip85Index  uint32 // 派生的索引
+	// 组织管理
+	orgIndex  int      // 新建组织的索引，小于0时自动分配
+	orgChains []string // 组织允许使用的链名称
+	// 审计日志
+	auditLog string // 审计日志路径，为空时不记录
+	auditKey string // 审计日志的HMAC密钥（16进制）
+	actor    string // 操作人
 )
 
 func init() {
@@ -94,8 +226,21 @@ func init() {
 			"the mnemonic type, the values is :en[English],zh-cn[简体中文],zh-tw[繁体中文],fr[French],it[Italian],ja[Japanese],ko[Korean],es[Spanish](the default is en) ")
 		cmd.Flags().StringVarP(&mnemonic, "mnemonic", "m", "", "if load wallet by mnemonic,please write mnemonic. Words are separated by spaces")
 		cmd.Flags().BoolVar(&isUsePwdBlur, "isUsePwdBlur", false, "whether use password to blur the seed.(the default is false)")
+		cmd.Flags().StringVar(&seedType, "seedType", "", "the seed type of mnemonic, the values is: bip39,electrum_standard,electrum_segwit,auto. (the default is bip39)")
 		cmd.Flags().StringVarP(&prvKeyBase58, "prvKeyBase58", "k", "", "if load wallet by prvKeyBase58,please write prvKeyBase58")
 		cmd.Flags().StringVarP(&networkType, "networkType", "n", "mainnet", "network type,the values is: mainnet,testnet,devnet. (the default is mainnet)")
+		cmd.Flags().StringVar(&curve, "curve", "p256", "the curve of new wallet, the values is: p256,secp256k1, exportXpub of eth,btc needs secp256k1. (the default is p256)")
+		cmd.Flags().StringVar(&xpub, "xpub", "", "if create watch-only wallet,please write the extended public key")
+		cmd.Flags().StringVar(&xpubPath, "xpubPath", "", "the path of the extended public key, such as /44/60/0 (the default is master)")
+		cmd.Flags().IntVar(&wordCount, "words", 0, "the word count of new wallet mnemonic, the values is: 12,15,18,21,24. (the default is 12 or the length of entropy)")
+		cmd.Flags().StringVar(&entropyHex, "entropy", "", "the hex entropy of new wallet, 16~32 bytes")
+		cmd.Flags().StringVar(&diceRolls, "dice", "", "the dice rolls of new wallet, such as 3152645... or 12,3,20,... for dice with more than 9 sides")
+		cmd.Flags().IntVar(&diceSides, "diceSides", 6, "the sides of the dice (the default is 6)")
+		cmd.Flags().StringVar(&coinFlips, "coins", "", "the coin flips of new wallet, 0/1 or H/T")
+		cmd.Flags().BoolVarP(&confirmMnemonic, "yes", "y", false, "confirm the mnemonic derived from the entropy without prompt")
+		cmd.Flags().StringVar(&policyFile, "policy", "", "the signing policy file, the daily limit state is saved to <policy>.state (the default is no policy)")
+		addAuditFlags(cmd)
+		cmd.Flags().StringVar(&actor, "actor", "", "the operator recorded in the audit log")
 	}
 
 	// 主账户导出
@@ -107,35 +252,148 @@ func init() {
 	}
 	// 子账户生成
 	{
-		cmdGenChild.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is eth,btc(the default is eth)")
-		cmdGenChild.Flags().Uint32Var(&purposeType, "purposeType", uint32(44), "choose the purpose type.The values is 44,45(the default is 44)")
+		cmdGenChild.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is "+registeredChainNames()+"(the default is eth)")
+		cmdGenChild.Flags().Uint32Var(&purposeType, "purposeType", uint32(44), "choose the purpose type.The values is 44,45,49,84,86(the default is 44)")
 		cmdGenChild.Flags().Uint32Var(&org, "org", 0, "if purpose=45,this value is org(the default is 0)")
-		cmdGenChild.Flags().Uint32Var(&coinType, "coinType", 0, "coinType(the default is 0)")
+		cmdGenChild.Flags().StringVar(&orgName, "orgName", "", "the organization name, if set use purpose 45 and the org index of the organization")
+		cmdGenChild.Flags().Uint32Var(&coinType, "coinType", 0, "coinType(the default is the SLIP-44 coin type of the chain)")
 		cmdGenChild.Flags().Uint32Var(&account, "account", 0, "the account space(the default is 0)")
 		cmdGenChild.Flags().Uint32Var(&addressIndex, "addressIndex", 0, "the address index(the default is 0)")
+		cmdGenChild.Flags().StringVar(&label, "label", "", "the account label, need isSaveSubKey")
+		cmdGenChild.Flags().StringSliceVar(&tags, "tags", nil, "the account tags, separated by commas, need isSaveSubKey")
 		addFlags(cmdGenChild, "geneChild")
 	}
+	// 批量生成地址
+	{
+		cmdDeriveAddresses.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is "+registeredChainNames()+"(the default is eth)")
+		cmdDeriveAddresses.Flags().Uint32Var(&purposeType, "purposeType", uint32(44), "if pathTemplate is empty, choose the purpose type.The values is 44,45,49,84,86(the default is 44)")
+		cmdDeriveAddresses.Flags().Uint32Var(&org, "org", 0, "if purpose=45,this value is org(the default is 0)")
+		cmdDeriveAddresses.Flags().Uint32Var(&coinType, "coinType", 0, "coinType(the default is the SLIP-44 coin type of the chain)")
+		cmdDeriveAddresses.Flags().Uint32Var(&account, "account", 0, "the account space(the default is 0)")
+		cmdDeriveAddresses.Flags().StringVar(&pathTemplate, "pathTemplate", "", "the path template, * is the address index, such as m/44'/60'/0'/0/* (the default is built from purposeType,coinType,org,account)")
+		cmdDeriveAddresses.Flags().Uint32Var(&start, "start", 0, "the start address index(the default is 0)")
+		cmdDeriveAddresses.Flags().Uint32Var(&count, "count", 20, "the number of addresses(the default is 20)")
+		cmdDeriveAddresses.Flags().BoolVar(&persist, "persist", false, "whether save the addresses to the wallet (the default is false)")
+		cmdDeriveAddresses.Flags().StringVar(&outputFormat, "format", "csv", "the output format, the values is: csv,json(the default is csv)")
+		cmdDeriveAddresses.Flags().StringVarP(&outputFile, "output", "o", "", "the output file (the default is stdout)")
+		addFlags(cmdDeriveAddresses, "deriveAddresses")
+	}
+	// 列出账户
+	{
+		cmdListAccounts.Flags().StringVar(&filterLabel, "label", "", "filter by the label contains")
+		cmdListAccounts.Flags().StringSliceVar(&filterTags, "tag", nil, "filter by the tags, all tags are required")
+		cmdListAccounts.Flags().StringVar(&filterChain, "chain", "", "filter by the chain name, such as eth,btc")
+		cmdListAccounts.Flags().StringVar(&filterNetwork, "network", "", "filter by the network, such as mainnet,testnet")
+		cmdListAccounts.Flags().StringVar(&filterKeyPathPrefix, "keyPathPrefix", "", "filter by the keyPath prefix, such as /44/60/0")
+		cmdListAccounts.Flags().StringToStringVar(&filterExtra, "extra", nil, "filter by the extra key=value")
+		cmdListAccounts.Flags().StringVar(&outputFormat, "format", "csv", "the output format, the values is: csv,json(the default is csv)")
+		cmdListAccounts.Flags().StringVarP(&outputFile, "output", "o", "", "the output file (the default is stdout)")
+		addFlags(cmdListAccounts, "list")
+	}
 	// 导出子账户
 	{
-		cmdExportChild.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is eth,btc(the default is eth)")
+		cmdExportChild.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is "+registeredChainNames()+"(the default is eth)")
 		cmdExportChild.Flags().StringVarP(&childAddress, "childAddress", "a", "", "the child address")
-		cmdExportChild.Flags().StringVar(&childKeyPath, "childKeyPath", "", "the child account path")
+		cmdExportChild.Flags().StringVar(&childKeyPath, "childKeyPath", "", "the child account path, if empty use the path recorded in the wallet")
 		cmdExportChild.Flags().BoolVar(&exportChildRawKey, "exportChildRawKey", false, "whether export the child rawKey (the default is false) ")
 		cmdExportChild.Flags().BoolVar(&exportChildExtendedKey, "exportChildExtendedKey", false, "whether export the child extendedKey (the default is false) ")
 		cmdExportChild.Flags().BoolVar(&exportChildKeystore, "exportChildKeystore", false, "whether export the child keystore (the default is false) ")
 		cmdExportChild.Flags().StringVar(&childKeystorePwd, "childKeystorePwd", "", "if export the keystore, will use childKeystorePwd to encrypt the privateKey")
 		addFlags(cmdExportChild, "exportChild")
 	}
+	// 导出账户扩展公钥
+	{
+		cmdExportXpub.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is "+registeredChainNames()+"(the default is eth)")
+		cmdExportXpub.Flags().Uint32Var(&purposeType, "purposeType", uint32(44), "choose the purpose type.The values is 44,45,49,84,86(the default is 44)")
+		cmdExportXpub.Flags().Uint32Var(&org, "org", 0, "if purpose=45,this value is org(the default is 0)")
+		cmdExportXpub.Flags().StringVar(&orgName, "orgName", "", "the organization name, if set export the account key of the organization")
+		cmdExportXpub.Flags().Uint32Var(&coinType, "coinType", 0, "coinType(the default is the SLIP-44 coin type of the chain)")
+		cmdExportXpub.Flags().Uint32Var(&account, "account", 0, "the account space(the default is 0)")
+		cmdExportXpub.Flags().BoolVar(&exportAccountPrivate, "exportPrivate", false, "whether export the account extended private key (the default is false) ")
+		addFlags(cmdExportXpub, "exportXpub")
+	}
+	// 组织管理
+	{
+		cmdOrg.Flags().StringVar(&orgName, "orgName", "", "the name of the organization to create, if empty only list the organizations")
+		cmdOrg.Flags().IntVar(&orgIndex, "orgIndex", -1, "the org index of the new organization (the default is the max index + 1)")
+		cmdOrg.Flags().StringSliceVar(&orgChains, "chains", nil, "the chain names allowed for the organization, separated by commas (the default is all chains)")
+		cmdOrg.Flags().StringVar(&outputFormat, "format", "csv", "the output format, the values is: csv,json(the default is csv)")
+		cmdOrg.Flags().StringVarP(&outputFile, "output", "o", "", "the output file (the default is stdout)")
+		addFlags(cmdOrg, "org")
+	}
 	// 签名
 	{
-		cmdSign.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is eth,btc(the default is eth)")
+		cmdSign.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is "+registeredChainNames()+"(the default is eth)")
 		cmdSign.Flags().StringVarP(&childAddress, "childAddress", "a", "", "the child address")
-		cmdSign.Flags().StringVar(&childKeyPath, "childKeyPath", "", "the child account path")
+		cmdSign.Flags().StringVar(&childKeyPath, "childKeyPath", "", "the child account path, if empty use the path recorded in the wallet")
 		cmdSign.Flags().StringVar(&signHash, "signHash", "", "the hash from transaction is need to sign")
+		cmdSign.Flags().StringVar(&rawTx, "rawTx", "", "the hex unsigned transaction, the hash and the info checked by the policy are decoded from it")
 		addFlags(cmdSign, "sign")
 	}
+	// 备份钱包
+	{
+		cmdBackup.Flags().StringVarP(&backupFile, "backupFile", "o", "./wallet.backup", "the backup bundle file")
+		cmdBackup.Flags().StringVar(&backupPwd, "backupPwd", "", "password to encrypt the backup bundle")
+		addFlags(cmdBackup, "backup")
+	}
+	// 恢复钱包
+	{
+		cmdRestore.Flags().StringVarP(&path, "path", "f", "./wallet.dat", "the restored wallet file path, should not exist")
+		cmdRestore.Flags().StringVarP(&password, "password", "p", "", "password of the restored wallet (the default is the password of the backup wallet)")
+		cmdRestore.Flags().StringVarP(&backupFile, "backupFile", "i", "./wallet.backup", "the backup bundle file")
+		cmdRestore.Flags().StringVar(&backupPwd, "backupPwd", "", "password to decrypt the backup bundle")
+	}
+	// 生成SLIP-0039分片助记词
+	{
+		cmdSplit.Flags().StringVarP(&path, "path", "f", "", "the wallet file path, if not empty create the wallet from the shares")
+		cmdSplit.Flags().StringVarP(&password, "password", "p", "", "password to encrypt & decrypt wallet")
+		cmdSplit.Flags().StringVar(&curve, "curve", "p256", "the curve of new wallet, the values is: p256,secp256k1. (the default is p256)")
+		cmdSplit.Flags().IntVar(&groupThreshold, "groupThreshold", 1, "the number of groups required to restore (the default is 1)")
+		cmdSplit.Flags().StringSliceVar(&shareGroups, "groups", []string{"2/3"}, "the groups, memberThreshold/memberCount separated by commas, such as 1/1,2/3 (the default is 2/3)")
+		cmdSplit.Flags().StringVar(&masterSecret, "masterSecret", "", "the hex master secret (the default is random)")
+		cmdSplit.Flags().IntVar(&strength, "strength", 128, "the bits of the random master secret, the values is: 128,256 (the default is 128)")
+		cmdSplit.Flags().StringVar(&sharePassphrase, "passphrase", "", "the SLIP-0039 passphrase")
+		cmdSplit.Flags().BoolVar(&extendable, "extendable", true, "whether the shares are extendable (the default is true)")
+		cmdSplit.Flags().IntVar(&iterationExponent, "iterationExponent", 1, "the PBKDF2 iteration exponent, iterations is 10000<<iterationExponent (the default is 1)")
+	}
+	// 使用SLIP-0039分片助记词恢复钱包
+	{
+		cmdCombine.Flags().StringVarP(&path, "path", "f", "./wallet.dat", "the wallet file path")
+		cmdCombine.Flags().StringVarP(&password, "password", "p", "", "password to encrypt & decrypt wallet")
+		cmdCombine.Flags().StringVar(&curve, "curve", "p256", "the curve of new wallet, the values is: p256,secp256k1. (the default is p256)")
+		cmdCombine.Flags().StringArrayVarP(&shares, "share", "s", nil, "the SLIP-0039 share, repeat for each share")
+		cmdCombine.Flags().StringVar(&sharePassphrase, "passphrase", "", "the SLIP-0039 passphrase")
+	}
+	// BIP85派生子密钥
+	{
+		cmdBIP85.Flags().StringVar(&bip85App, "app", "mnemonic", "the BIP85 application, the values is: mnemonic,wif,xprv,hex,base64,base85 (the default is mnemonic)")
+		cmdBIP85.Flags().IntVar(&bip85Length, "length", 0, "the mnemonic words(12,18,24), the hex bytes(16~64) or the password length (the default is 12,32,21,12)")
+		cmdBIP85.Flags().Uint32Var(&bip85Index, "index", 0, "the BIP85 index (the default is 0)")
+		addFlags(cmdBIP85, "bip85")
+	}
+	// 恢复输错或缺失单词的助记词
+	{
+		cmdRecoverMnemonic.Flags().StringVarP(&mnemonic, "mnemonic", "m", "", "the mnemonic to recover, use ? for the unknown word")
+		cmdRecoverMnemonic.Flags().StringVarP(&password, "password", "p", "", "the password, used to blur the seed if isUsePwdBlur")
+		cmdRecoverMnemonic.Flags().BoolVar(&isUsePwdBlur, "isUsePwdBlur", false, "whether use password to blur the seed.(the default is false)")
+		cmdRecoverMnemonic.Flags().StringVar(&curve, "curve", "p256", "the curve of the wallet, the values is: p256,secp256k1. (the default is p256)")
+		cmdRecoverMnemonic.Flags().StringVarP(&childAddress, "childAddress", "a", "", "the known child address to check the candidates (the default is not check)")
+		cmdRecoverMnemonic.Flags().StringVar(&childKeyPath, "childKeyPath", "", "the child account path of the known address")
+		cmdRecoverMnemonic.Flags().StringVarP(&chainType, "chainType", "t", "eth", "choose the chain type.The values is "+registeredChainNames()+"(the default is eth)")
+		cmdRecoverMnemonic.Flags().StringVarP(&networkType, "networkType", "n", "mainnet", "network type,the values is: mainnet,testnet,devnet. (the default is mainnet)")
+		cmdRecoverMnemonic.Flags().IntVar(&maxSuggestions, "max", 5, "the number of suggestions for each mistyped word (the default is 5)")
+	}
+	// 校验审计日志
+	{
+		addAuditFlags(cmdAuditVerify)
+	}
 
-	cmd.AddCommand(cmdOprMaster, cmdGenChild, cmdExportChild, cmdSign)
+	cmd.AddCommand(cmdOprMaster, cmdGenChild, cmdDeriveAddresses, cmdListAccounts, cmdExportChild, cmdExportXpub, cmdSign, cmdBackup, cmdRestore, cmdSplit, cmdCombine, cmdBIP85, cmdRecoverMnemonic, cmdOrg, cmdAuditVerify)
+}
+
+func addAuditFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&auditLog, "auditLog", "", "the audit log file path (the default is not audit)")
+	cmd.Flags().StringVar(&auditKey, "auditKey", "", "the 0x-prefixed hex HMAC key to sign the audit log (the default is not sign)")
 }
 
 // 操作主账户
@@ -167,17 +425,127 @@ func runGenChild(cmd *cobra.Command, args []string) {
 	}
 	chainApi := getChainApi()
 
-	if purposeType != 44 && purposeType != 45 {
-		fmt.Println("purpose type is err: ", "purpose type must 44 or 45")
+	var subAddr, keyPath string
+	if wallet.SeedType == keybox.SeedType_ElectrumStandard || wallet.SeedType == keybox.SeedType_ElectrumSegwit {
+		// Electrum钱包使用Electrum的路径
+		subAddr, keyPath, err = wallet.CreateElectrumAccount(0, addressIndex, chainApi)
+	} else if orgName != "" {
+		subAddr, keyPath, err = wallet.CreateOrgAccount(orgName, getCoinType(cmd), bip32.ParseHDNum(account), uint32(0), addressIndex, chainApi)
+	} else if purposeType != 44 && purposeType != 45 && purposeType != 49 && purposeType != 84 && purposeType != 86 {
+		fmt.Println("purpose type is err: ", "purpose type must 44, 45, 49, 84 or 86")
 		os.Exit(1)
+	} else {
+		subAddr, keyPath, err = wallet.CreateAccount(bip32.ParseHDNum(purposeType), getCoinType(cmd), bip32.ParseHDNum(org), bip32.ParseHDNum(account), uint32(0), addressIndex, chainApi)
 	}
-	subAddr, keyPath, err := wallet.CreateAccount(bip32.ParseHDNum(purposeType), bip32.ParseHDNum(coinType), bip32.ParseHDNum(org), bip32.ParseHDNum(account), uint32(0), addressIndex, chainApi)
 	if err != nil {
 		fmt.Println("create child account is err: ", err.Error())
 		os.Exit(1)
 	}
 	fmt.Println("subAddress: ", subAddr)
 	fmt.Println("childPath: ", keyPath)
+	if label != "" {
+		if err := wallet.SetAccountLabel(subAddr, label); err != nil {
+			fmt.Println("set account label is err: ", err.Error())
+			os.Exit(1)
+		}
+	}
+	if len(tags) > 0 {
+		if err := wallet.AddAccountTags(subAddr, tags...); err != nil {
+			fmt.Println("add account tags is err: ", err.Error())
+			os.Exit(1)
+		}
+	}
+}
+
+// 批量生成子账户地址
+func runDeriveAddresses(cmd *cobra.Command, args []string) {
+	if outputFormat != "csv" && outputFormat != "json" {
+		fmt.Println("output format is err: ", "format must csv or json")
+		os.Exit(1)
+	}
+	wallet, err := loadWallet()
+	if err != nil {
+		return
+	}
+	chainApi := getChainApi()
+	template := pathTemplate
+	if template == "" {
+		coin := getCoinType(cmd)
+		if coin == 0 {
+			coin = chainApi.ChainInfo().ChainType
+		}
+		coin -= bip32.FirstHardenedChild
+		if purposeType == 45 {
+			template = fmt.Sprintf("m/45'/%d'/%d'/%d'/0/*", coin, org, account)
+		} else {
+			template = fmt.Sprintf("m/%d'/%d'/%d'/0/*", purposeType, coin, account)
+		}
+	}
+	addresses, err := wallet.DeriveAddresses(template, start, count, persist, chainApi)
+	if err != nil {
+		fmt.Println("derive addresses is err: ", err.Error())
+		os.Exit(1)
+	}
+
+	rows := make([][]string, 0, len(addresses))
+	for _, addr := range addresses {
+		rows = append(rows, []string{addr.Address, addr.PubKey, addr.KeyPath})
+	}
+	writeRecords([]string{"address", "pubKey", "keyPath"}, rows, addresses)
+}
+
+// 列出账户，可按元数据过滤
+func runListAccounts(cmd *cobra.Command, args []string) {
+	if outputFormat != "csv" && outputFormat != "json" {
+		fmt.Println("output format is err: ", "format must csv or json")
+		os.Exit(1)
+	}
+	wallet, err := loadWallet()
+	if err != nil {
+		return
+	}
+	accounts := wallet.QueryAccounts(&keybox.AccountQuery{
+		Label:         filterLabel,
+		Tags:          filterTags,
+		Chain:         filterChain,
+		Network:       filterNetwork,
+		KeyPathPrefix: filterKeyPathPrefix,
+		Extra:         filterExtra,
+	})
+	rows := make([][]string, 0, len(accounts))
+	for _, meta := range accounts {
+		rows = append(rows, []string{meta.Address, meta.Label, strings.Join(meta.Tags, ";"), meta.Chain, meta.Network, meta.KeyPath, strconv.FormatInt(meta.CreateTime, 10)})
+	}
+	writeRecords([]string{"address", "label", "tags", "chain", "network", "keyPath", "createTime"}, rows, accounts)
+}
+
+// 按输出格式写入csv或json，outputFile为空时输出到标准输出
+func writeRecords(header []string, rows [][]string, v interface{}) {
+	var out io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			fmt.Println("create output file is err: ", err.Error())
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	var err error
+	if outputFormat == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(v)
+	} else {
+		w := csv.NewWriter(out)
+		w.Write(header)
+		w.WriteAll(rows)
+		err = w.Error()
+	}
+	if err != nil {
+		fmt.Println("write output is err: ", err.Error())
+		os.Exit(1)
+	}
 }
 
 // 导出子账户内容
@@ -214,49 +582,431 @@ func runExportChild(cmd *cobra.Command, args []string) {
 	}
 }
 
+// 导出账户扩展公钥
+func runExportXpub(cmd *cobra.Command, args []string) {
+	wallet, err := loadWallet()
+	if err != nil {
+		return
+	}
+	var keyPath, extendedKey string
+	if orgName != "" {
+		keyPath, extendedKey, err = wallet.ExportOrgExtendedKey(orgName, getCoinType(cmd), bip32.ParseHDNum(account), exportAccountPrivate, chain.ParseToType(networkType), getChainApi())
+	} else {
+		keyPath, extendedKey, err = wallet.ExportAccountExtendedKey(bip32.ParseHDNum(purposeType), getCoinType(cmd), bip32.ParseHDNum(org), bip32.ParseHDNum(account), exportAccountPrivate, chain.ParseToType(networkType), getChainApi())
+	}
+	if err != nil {
+		fmt.Println("export account extended key is err: ", err.Error())
+		if err == keybox.ErrCurveMismatch {
+			fmt.Println("the wallet of eth,btc must be created with --curve secp256k1, the default p256 wallet can not export their xpub")
+		}
+		os.Exit(1)
+	}
+	fmt.Println("xpubPath: ", keyPath)
+	fmt.Println("extendedKey: ", extendedKey)
+}
+
+// 创建组织并列出全部组织
+func runOrg(cmd *cobra.Command, args []string) {
+	if outputFormat != "csv" && outputFormat != "json" {
+		fmt.Println("output format is err: ", "format must csv or json")
+		os.Exit(1)
+	}
+	wallet, err := loadWallet()
+	if err != nil {
+		return
+	}
+	if orgName != "" {
+		index := uint32(0)
+		if orgIndex >= 0 {
+			index = bip32.ParseHDNum(uint32(orgIndex))
+		}
+		if _, err := wallet.CreateOrg(orgName, index, orgChains); err != nil {
+			fmt.Println("create organization is err: ", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	orgs := wallet.ListOrgs()
+	rows := make([][]string, 0, len(orgs))
+	for _, o := range orgs {
+		accounts, _ := wallet.ListOrgAccounts(o.Name)
+		rows = append(rows, []string{o.Name, strconv.FormatUint(uint64(o.Index-bip32.FirstHardenedChild), 10), strings.Join(o.Chains, ";"), strconv.Itoa(len(accounts))})
+	}
+	writeRecords([]string{"name", "org", "chains", "accounts"}, rows, orgs)
+}
+
 // 使用子账户进行签名
 func runSign(cmd *cobra.Command, args []string) {
 	wallet, err := loadWallet()
 	if err != nil {
 		return
 	}
+	if (signHash == "") == (rawTx == "") {
+		fmt.Println("sign params is err: ", "one of signHash and rawTx is required")
+		os.Exit(1)
+	}
 	// 签名
-	signHashBytes, err := hexutil.Decode(signHash)
+	var sign string
+	if rawTx != "" {
+		rawTxBytes, err := hexutil.Decode(rawTx)
+		if err != nil {
+			fmt.Println("hex decode rawTx is err: ", err.Error())
+			os.Exit(1)
+		}
+		sign, err = wallet.SignTx(childAddress, childKeyPath, rawTxBytes, getChainApi())
+		if err != nil {
+			fmt.Println("sign rawTx is err: ", err.Error())
+			os.Exit(1)
+		}
+	} else {
+		signHashBytes, err := hexutil.Decode(signHash)
+		if err != nil {
+			fmt.Println("hex decode signHash is err: ", err.Error())
+			os.Exit(1)
+		}
+		sign, err = wallet.Sign(childAddress, childKeyPath, signHashBytes, getChainApi())
+		if err != nil {
+			fmt.Println("sign signHash is err: ", err.Error())
+			os.Exit(1)
+		}
+	}
+	fmt.Println("signature: ", sign)
+}
+
+// 备份钱包
+func runBackup(cmd *cobra.Command, args []string) {
+	wallet, err := loadWallet()
+	if err != nil {
+		return
+	}
+	bundle, err := wallet.Backup(backupPwd)
 	if err != nil {
-		fmt.Println("hex decode signHash is err: ", err.Error())
+		fmt.Println("backup wallet is err: ", err.Error())
+		os.Exit(1)
+	}
+	if err := os.WriteFile(backupFile, bundle, 0600); err != nil {
+		fmt.Println("write backup file is err: ", err.Error())
 		os.Exit(1)
 	}
-	sign, err := wallet.Sign(childAddress, childKeyPath, signHashBytes, getChainApi())
+	fmt.Println("backup file: ", backupFile)
+}
+
+// 恢复钱包
+func runRestore(cmd *cobra.Command, args []string) {
+	bundle, err := os.ReadFile(backupFile)
 	if err != nil {
-		fmt.Println("sign signHash is err: ", err.Error())
+		fmt.Println("read backup file is err: ", err.Error())
 		os.Exit(1)
 	}
-	fmt.Println("signature: ", sign)
+	store := keybox.NewFileStorage(filepath.Dir(path))
+	_, info, err := keybox.Restore(store, filepath.Base(path), bundle, backupPwd, password)
+	if err != nil {
+		fmt.Println("restore wallet is err: ", err.Error())
+		os.Exit(1)
+	}
+	fmt.Println("restored wallet: ", path)
+	fmt.Println("accounts: ", info.Accounts)
+	if info.MnemonicType != "" {
+		fmt.Println("mnemonicType: ", info.MnemonicType)
+	}
+}
+
+// 生成SLIP-0039分片助记词
+func runSplit(cmd *cobra.Command, args []string) {
+	groups := make([]slip39.MemberGroup, 0, len(shareGroups))
+	for _, g := range shareGroups {
+		var group slip39.MemberGroup
+		if _, err := fmt.Sscanf(g, "%d/%d", &group.MemberThreshold, &group.MemberCount); err != nil {
+			fmt.Println("groups is err: ", g)
+			os.Exit(1)
+		}
+		groups = append(groups, group)
+	}
+	var (
+		secret []byte
+		err    error
+	)
+	if masterSecret != "" {
+		secret, err = hex.DecodeString(strings.TrimPrefix(masterSecret, "0x"))
+	} else {
+		secret = make([]byte, strength/8)
+		_, err = rand.Read(secret)
+	}
+	if err != nil {
+		fmt.Println("masterSecret is err: ", err.Error())
+		os.Exit(1)
+	}
+	mnemonics, err := slip39.GenerateMnemonics(groupThreshold, groups, secret, []byte(sharePassphrase), extendable, iterationExponent)
+	if err != nil {
+		fmt.Println("split master secret is err: ", err.Error())
+		os.Exit(1)
+	}
+	for i, group := range mnemonics {
+		fmt.Printf("group %d (%d of %d):\n", i+1, groups[i].MemberThreshold, groups[i].MemberCount)
+		for j, m := range group {
+			fmt.Printf("  share %d: %s\n", j+1, m)
+		}
+	}
+	if path == "" {
+		return
+	}
+	// 使用生成的分片创建钱包
+	restoreShares := make([]string, 0)
+	for i := 0; i < groupThreshold; i++ {
+		restoreShares = append(restoreShares, mnemonics[i][:groups[i].MemberThreshold]...)
+	}
+	if _, err := keybox.LoadWalletFromSharesWithOptions(path, password, restoreShares, sharePassphrase, &keybox.WalletOptions{Curve: bip32.Curve(curve)}); err != nil {
+		fmt.Println("create wallet is err: ", err.Error())
+		os.Exit(1)
+	}
+	fmt.Println("wallet: ", path)
+}
+
+// 使用SLIP-0039分片助记词恢复钱包
+func runCombine(cmd *cobra.Command, args []string) {
+	if _, err := keybox.LoadWalletFromSharesWithOptions(path, password, shares, sharePassphrase, &keybox.WalletOptions{Curve: bip32.Curve(curve)}); err != nil {
+		fmt.Println("combine shares is err: ", err.Error())
+		os.Exit(1)
+	}
+	fmt.Println("wallet: ", path)
+}
+
+// BIP85派生子密钥，子助记词使用mnemonicType
+func runBIP85(cmd *cobra.Command, args []string) {
+	wallet, err := loadWallet()
+	if err != nil {
+		return
+	}
+	length := bip85Length
+	var value string
+	switch bip85App {
+	case "mnemonic":
+		if length == 0 {
+			length = 12
+		}
+		value, err = wallet.DeriveBIP85Mnemonic(keybox.ParseMnemonicType(mnemonicType), length, bip85Index)
+	case "wif":
+		value, err = wallet.DeriveBIP85WIF(bip85Index)
+	case "xprv":
+		value, err = wallet.DeriveBIP85XPRV(bip85Index)
+	case "hex":
+		if length == 0 {
+			length = 32
+		}
+		value, err = wallet.DeriveBIP85Hex(length, bip85Index)
+	case "base64", "base85":
+		if length == 0 {
+			length = 21
+			if bip85App == "base85" {
+				length = 12
+			}
+		}
+		value, err = wallet.DeriveBIP85Password(bip85App == "base85", length, bip85Index)
+	default:
+		err = fmt.Errorf("app %s is not supported", bip85App)
+	}
+	if err != nil {
+		fmt.Println("bip85 is err: ", err.Error())
+		os.Exit(1)
+	}
+	fmt.Println(bip85App+": ", value)
+}
+
+// 恢复输错或缺失单词的助记词
+func runRecoverMnemonic(cmd *cobra.Command, args []string) {
+	words := strings.Fields(mnemonic)
+	for i, suggestions := range keybox.SuggestMnemonicWords(mnemonic, maxSuggestions) {
+		fmt.Printf("word %d %s suggestions: %s\n", i+1, words[i], strings.Join(suggestions, ","))
+	}
+	passphrase := ""
+	if isUsePwdBlur {
+		passphrase = password
+	}
+	var chainApi keybox.ChainAPI
+	if childAddress != "" {
+		chainApi = getChainApi()
+	}
+	candidates, err := keybox.RecoverMnemonic(mnemonic, passphrase, bip32.Curve(curve), childKeyPath, childAddress, chainApi)
+	if err != nil {
+		fmt.Println("recover mnemonic is err: ", err.Error())
+		os.Exit(1)
+	}
+	for _, c := range candidates {
+		fmt.Println("candidate: ", c)
+	}
+	fmt.Println("candidates: ", len(candidates))
+}
+
+// 校验审计日志
+func runAuditVerify(cmd *cobra.Command, args []string) {
+	if auditLog == "" {
+		fmt.Println("auditLog is empty")
+		os.Exit(1)
+	}
+	signer, err := getAuditSigner()
+	if err != nil {
+		fmt.Println("auditKey is err: ", err.Error())
+		os.Exit(1)
+	}
+	last, err := keybox.VerifyAuditLog(auditLog, signer)
+	if err != nil {
+		fmt.Println("audit log verify is err: ", err.Error())
+		os.Exit(1)
+	}
+	if last == nil {
+		fmt.Println("audit log is empty")
+		return
+	}
+	fmt.Println("audit log is valid, entries: ", last.Seq, " lastHash: ", last.EntryHash)
+}
+
+func getAuditSigner() (keybox.AuditSigner, error) {
+	if auditKey == "" {
+		return nil, nil
+	}
+	key, err := hexutil.Decode(auditKey)
+	if err != nil {
+		return nil, err
+	}
+	return keybox.NewHMACAuditSigner(key), nil
+}
+
+// 获取新建钱包的选项，使用外部熵时需要确认生成的助记词
+func getWalletOptions() (*keybox.WalletOptions, error) {
+	opts := &keybox.WalletOptions{
+		MnemonicType: keybox.ParseMnemonicType(mnemonicType),
+		WordCount:    wordCount,
+		Curve:        bip32.Curve(curve),
+	}
+	if entropyHex == "" && diceRolls == "" && coinFlips == "" {
+		return opts, nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("wallet %s already exists", path)
+	}
+	if wordCount == 0 {
+		wordCount = keybox.DefaultWordCount
+	}
+	var err error
+	switch {
+	case entropyHex != "":
+		opts.Entropy, err = keybox.EntropyFromHex(entropyHex)
+	case diceRolls != "":
+		var rolls []int
+		if rolls, err = parseDiceRolls(diceRolls); err == nil {
+			opts.Entropy, err = keybox.EntropyFromDice(rolls, diceSides, wordCount)
+		}
+	default:
+		opts.Entropy, err = keybox.EntropyFromCoins(coinFlips, wordCount)
+	}
+	if err != nil {
+		return nil, err
+	}
+	derived, err := keybox.MnemonicCodec(opts.MnemonicType).NewMnemonic(opts.Entropy)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("mnemonic: ", derived)
+	if !confirmMnemonic {
+		fmt.Print("confirm the mnemonic and create wallet [y/N]: ")
+		var answer string
+		fmt.Scanln(&answer)
+		if answer != "y" && answer != "Y" && answer != "yes" {
+			return nil, fmt.Errorf("mnemonic is not confirmed")
+		}
+	}
+	return opts, nil
+}
+
+// 解析骰子点数，包含逗号或空格时按其分隔，否则每个字符为一次投掷
+func parseDiceRolls(s string) ([]int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) == 1 {
+		fields = strings.Split(fields[0], "")
+	}
+	rolls := make([]int, 0, len(fields))
+	for _, f := range fields {
+		roll, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("dice roll %s is invalid", f)
+		}
+		rolls = append(rolls, roll)
+	}
+	return rolls, nil
 }
 
 // 加载Wallet
 func loadWallet() (*keybox.Wallet, error) {
-	// 设置助记词类型
-	if mnemonicType != "" {
-		t := keybox.ParseMnemonicType(mnemonicType)
-		keybox.SetBip39MnemonicType(t)
-	}
 	var (
 		wallet *keybox.Wallet
 		err    error
 	)
-	if mnemonic != "" {
-		wallet, err = keybox.LoadWalletFromMnemonic(path, password, mnemonic, isUsePwdBlur)
+	if _, err = bip32.ParseCurve(curve); err != nil {
+		fmt.Println("curve is err: ", err.Error())
+		os.Exit(1)
+		return nil, err
+	}
+	curveOpts := &keybox.WalletOptions{Curve: bip32.Curve(curve)}
+	if xpub != "" {
+		wallet, err = keybox.NewWatchOnlyWallet(path, password, map[string]string{xpubPath: xpub})
+	} else if mnemonic != "" && seedType != "" {
+		passphrase := ""
+		if isUsePwdBlur {
+			passphrase = password
+		}
+		t := keybox.SeedType(seedType)
+		if seedType == "auto" {
+			t = ""
+		}
+		wallet, err = keybox.ImportSeedWithOptions(path, password, mnemonic, passphrase, t, curveOpts)
+	} else if mnemonic != "" {
+		wallet, err = keybox.LoadWalletFromMnemonicWithOptions(path, password, mnemonic, isUsePwdBlur, curveOpts)
 	} else if prvKeyBase58 != "" {
-		wallet, err = keybox.LoadWalletFromPrvKey(path, password, prvKeyBase58)
+		wallet, err = keybox.LoadWalletFromPrvKeyWithOptions(path, password, prvKeyBase58, curveOpts)
 	} else {
-		wallet, err = keybox.NewWallet(path, password)
+		opts, optsErr := getWalletOptions()
+		if optsErr != nil {
+			fmt.Println("entropy is err: ", optsErr.Error())
+			os.Exit(1)
+			return nil, optsErr
+		}
+		wallet, err = keybox.NewWalletWithOptions(path, password, opts)
 	}
 	if err != nil {
 		fmt.Println("load or new wallet is err: ", err.Error())
 		os.Exit(1)
 		return nil, err
 	}
+	if auditLog != "" {
+		signer, err := getAuditSigner()
+		if err != nil {
+			fmt.Println("auditKey is err: ", err.Error())
+			os.Exit(1)
+			return nil, err
+		}
+		log, err := keybox.OpenAuditLog(auditLog, signer)
+		if err != nil {
+			fmt.Println("open audit log is err: ", err.Error())
+			os.Exit(1)
+			return nil, err
+		}
+		wallet.SetAuditLog(log, actor)
+	}
+	if policyFile != "" {
+		policy, err := keybox.LoadPolicy(policyFile)
+		if err != nil {
+			fmt.Println("load policy is err: ", err.Error())
+			os.Exit(1)
+			return nil, err
+		}
+		engine, err := keybox.NewPolicyEngine(policy, policyFile+".state")
+		if err != nil {
+			fmt.Println("load policy is err: ", err.Error())
+			os.Exit(1)
+			return nil, err
+		}
+		wallet.SetPolicy(engine)
+	}
 	// 进行设置
 	wallet.SetIsSaveSubKey(isSaveSubKey)
 	wallet.SetIsSaveExtendedKey(isSaveExtendedKey)
@@ -266,20 +1016,33 @@ func loadWallet() (*keybox.Wallet, error) {
 	return wallet, err
 }
 
+// 按链名称从注册的链中创建，链名称不区分大小写
 func getChainApi() keybox.ChainAPI {
-	// 创建子账户
-	var chainApi keybox.ChainAPI
-	switch chainType {
-	case "ETH":
-		chainApi = eth.NewChain(chain.ParseToType(networkType))
-	case "BTC":
-		chainApi = btc.NewChain(chain.ParseToType(networkType))
-	default:
-		chainApi = eth.NewChain(chain.ParseToType(networkType))
+	chainApi, err := keybox.NewChainAPI(chainType, chain.ParseToType(networkType))
+	if err != nil {
+		fmt.Println("chain type is err: ", err.Error())
+		os.Exit(1)
 	}
 	return chainApi
 }
 
+// 注册的链名称，使用逗号分隔
+func registeredChainNames() string {
+	names := make([]string, 0)
+	for _, reg := range keybox.RegisteredChains() {
+		names = append(names, strings.ToLower(reg.Info.ChainName))
+	}
+	return strings.Join(names, ",")
+}
+
+// 未设置--coinType时返回0，使用链的币种类型
+func getCoinType(cmd *cobra.Command) uint32 {
+	if !cmd.Flags().Changed("coinType") {
+		return 0
+	}
+	return bip32.ParseHDNum(coinType)
+}
+
 func main() {
 	err := cmd.Execute()
 	if err != nil {